@@ -8,6 +8,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
 	"os"
 	"path"
 	"path/filepath"
@@ -474,6 +479,48 @@ func TestCLI_PackStatus(t *testing.T) {
 	})
 }
 
+func TestCLI_PackStatus_UnixSocket(t *testing.T) {
+	ct.HTTPTestParallel(t, ct.WithDefaultConfig(), func(s *agent.TestAgent) {
+		expectGoodPackDeploy(t, runTestPackCmd(t, s, []string{"run", getTestPackPath(t, testPack)}))
+
+		// The test agent only listens over TCP, so the socket proxies the
+		// requests to it.
+		sock := filepath.Join(t.TempDir(), "nomad.sock")
+		l, err := net.Listen("unix", sock)
+		must.NoError(t, err)
+		target, err := url.Parse(s.HTTPAddr())
+		must.NoError(t, err)
+		srv := &http.Server{Handler: httputil.NewSingleHostReverseProxy(target)}
+		go srv.Serve(l)
+		t.Cleanup(func() { srv.Close() })
+
+		result := runPackCmd(t, []string{"status", "--address=unix://" + sock})
+		must.Zero(t, result.exitCode)
+		must.StrContains(t, result.cmdOut.String(), "simple_raw_exec | "+cache.DevRegistryName+" ")
+	})
+}
+
+func TestCLI_PackStatus_DryRun(t *testing.T) {
+	ct.HTTPTestParallel(t, ct.WithDefaultConfig(), func(s *agent.TestAgent) {
+		packPath := getTestPackPath(t, testPack)
+
+		result := runTestPackCmd(t, s, []string{"status", packPath, "--dry-run"})
+		must.Zero(t, result.exitCode)
+		must.StrContains(t, result.cmdOut.String(), "| create")
+
+		expectGoodPackDeploy(t, runTestPackCmd(t, s, []string{"run", packPath}))
+
+		result = runTestPackCmd(t, s, []string{"status", packPath, "--dry-run"})
+		must.Zero(t, result.exitCode)
+		must.StrContains(t, result.cmdOut.String(), "| update")
+
+		result = runTestPackCmd(t, s, []string{"status", packPath, "--dry-run", "--name=other"})
+		must.Zero(t, result.exitCode)
+		must.StrContains(t, result.cmdOut.String(), "conflict: part of deployment")
+		must.StrContains(t, result.cmdOut.String(), "1 of 1 jobs conflict with existing jobs")
+	})
+}
+
 func TestCLI_PackStatus_Fails(t *testing.T) {
 	ct.HTTPTestParallel(t, ct.WithDefaultConfig(), func(s *agent.TestAgent) {
 		// test for status on missing pack
@@ -583,6 +630,369 @@ func TestCLI_PackRender_VarsInOutputTemplate(t *testing.T) {
 	must.SliceContainsAll(t, expected, elems, must.Sprintf("unexpected returned value.\nexpected: %v\nelems: %v\nstdout:\n%v\n", expected, elems, result.cmdOut.String()))
 }
 
+func TestCLI_PackRender_GroupErrors(t *testing.T) {
+	t.Parallel()
+
+	// Two variables that are not defined by the pack each produce an error
+	// with their own HCL Range but a shared Pack Name context.
+	varFile := filepath.Join(t.TempDir(), "overrides.hcl")
+	must.NoError(t, os.WriteFile(varFile, []byte("not_a_var = 1\nalso_not_a_var = 2\n"), 0644))
+
+	result := runPackCmd(t, []string{"render", "--var-file", varFile, getTestPackPath(t, testPack)})
+	must.Eq(t, 1, result.exitCode)
+	must.Eq(t, 2, strings.Count(result.cmdOut.String(), "Context:"), must.Sprintf(
+		"expected two error blocks, received %q", result.cmdOut.String()))
+
+	result = runPackCmd(t, []string{"render", "--group-errors", "--var-file", varFile, getTestPackPath(t, testPack)})
+	must.Eq(t, 1, result.exitCode)
+	must.Eq(t, 1, strings.Count(result.cmdOut.String(), "Context:"), must.Sprintf(
+		"expected a single grouped error block, received %q", result.cmdOut.String()))
+	must.StrContains(t, result.cmdOut.String(), "(2 errors)")
+	must.Eq(t, 2, strings.Count(result.cmdOut.String(), "HCL Range:"))
+}
+
+func TestCLI_PackRender_ErrorContextKeys(t *testing.T) {
+	t.Parallel()
+
+	varFile := filepath.Join(t.TempDir(), "overrides.hcl")
+	must.NoError(t, os.WriteFile(varFile, []byte("not_a_var = 1\n"), 0644))
+
+	result := runPackCmd(t, []string{"render", "--error-context-keys=pack-name", "--var-file", varFile, getTestPackPath(t, testPack)})
+	must.Eq(t, 1, result.exitCode)
+	must.StrContains(t, result.cmdOut.String(), "Pack Name: simple_raw_exec")
+	must.StrNotContains(t, result.cmdOut.String(), "Pack Path:")
+	must.StrNotContains(t, result.cmdOut.String(), "HCL Range:")
+}
+
+func TestCLI_PackRender_SummaryFD(t *testing.T) {
+	// This test is not parallel, as the summary is written to a file
+	// descriptor shared with the command.
+	r, w, err := os.Pipe()
+	must.NoError(t, err)
+	defer r.Close()
+
+	varFile := filepath.Join(t.TempDir(), "overrides.hcl")
+	must.NoError(t, os.WriteFile(varFile, []byte("not_a_var = 1\n"), 0644))
+
+	result := runPackCmd(t, []string{"render", fmt.Sprintf("--summary-fd=%d", w.Fd()), "--var-file", varFile, getTestPackPath(t, testPack)})
+	w.Close()
+	must.Eq(t, 1, result.exitCode)
+
+	// The summary is only written to the file descriptor.
+	must.StrNotContains(t, result.cmdOut.String(), `"exitCode"`)
+
+	b, err := io.ReadAll(r)
+	must.NoError(t, err)
+
+	var summary commandSummary
+	must.NoError(t, json.Unmarshal(b, &summary))
+	must.Eq(t, commandSummary{
+		Command:  "render",
+		ExitCode: 1,
+		Counts:   commandSummaryCounts{Errors: 1},
+		Warnings: []string{},
+	}, summary)
+
+	result = runPackCmd(t, []string{"render", "--summary-fd=-1", getTestPackPath(t, testPack)})
+	must.Eq(t, 1, result.exitCode)
+	must.StrContains(t, result.cmdOut.String(), "--summary-fd must not be negative")
+}
+
+func TestCLI_PackRender_NoCache(t *testing.T) {
+	t.Parallel()
+
+	// Local directory packs are used in place.
+	result := runPackCmd(t, []string{"render", "--no-cache", getTestPackPath(t, testPack)})
+	must.Zero(t, result.exitCode)
+	must.StrContains(t, result.cmdOut.String(), "simple_raw_exec/simple_raw_exec.nomad")
+
+	// Registry packs require the registry source.
+	result = runPackCmd(t, []string{"render", "--no-cache", testPack})
+	must.Eq(t, 1, result.exitCode)
+	must.StrContains(t, result.cmdOut.String(), "--registry must be set to the registry source")
+}
+
+func TestCLI_PackInfo_RenderOutputs(t *testing.T) {
+	t.Parallel()
+
+	result := runPackCmd(t, []string{
+		"info",
+		"--render-outputs",
+		"--var=job_name=foo",
+		getTestPackPath(t, "my_alias_test"),
+	})
+	must.Zero(t, result.exitCode)
+	must.StrContains(t, result.cmdOut.String(), "Output Template")
+	must.StrContains(t, result.cmdOut.String(), "foo,child1,child2")
+
+	// Packs without an output template only produce a warning.
+	result = runPackCmd(t, []string{"info", "--render-outputs", getTestPackPath(t, testPack)})
+	must.Zero(t, result.exitCode)
+	must.StrContains(t, result.cmdOut.String(), "Pack does not contain an output template")
+
+	// Errors in the output template are output along with their position.
+	packPath := path.Join(t.TempDir(), testPack)
+	must.NoError(t, filesystem.CopyDir(getTestPackPath(t, testPack), packPath, false, logging.Default()))
+	must.NoError(t, os.WriteFile(path.Join(packPath, "outputs.tpl"), []byte(`[[ template "missing" . ]]`), 0644))
+
+	result = runPackCmd(t, []string{"info", "--render-outputs", packPath})
+	must.Eq(t, 1, result.exitCode)
+	must.StrContains(t, result.cmdOut.String(), "Failed To Render Output Template")
+	must.StrContains(t, result.cmdOut.String(), `template "missing" not defined`)
+	must.StrContains(t, result.cmdOut.String(), "Filename: outputs.tpl")
+}
+
+func TestCLI_PackInfo_Readme(t *testing.T) {
+	t.Parallel()
+
+	packPath := path.Join(t.TempDir(), testPack)
+	must.NoError(t, filesystem.CopyDir(getTestPackPath(t, testPack), packPath, false, logging.Default()))
+
+	result := runPackCmd(t, []string{"info", "--readme", packPath})
+	must.Zero(t, result.exitCode)
+	must.StrContains(t, result.cmdOut.String(), "Pack does not contain a README")
+
+	must.NoError(t, os.WriteFile(path.Join(packPath, "README.md"), []byte("# Simple Raw Exec\n\nRuns a command."), 0644))
+
+	result = runPackCmd(t, []string{"info", "--readme", packPath})
+	must.Zero(t, result.exitCode)
+	must.StrContains(t, result.cmdOut.String(), "README")
+	must.StrContains(t, result.cmdOut.String(), "# Simple Raw Exec\n\nRuns a command.")
+}
+
+func TestCLI_RegistryValidate(t *testing.T) {
+	t.Parallel()
+
+	result := runPackCmd(t, []string{"registry", "validate", getTestPackRegistryPath(t)})
+	must.Zero(t, result.exitCode)
+	must.StrContains(t, result.cmdOut.String(), "All 4 packs are valid")
+
+	// Strict validation also requires the packs to be documented.
+	result = runPackCmd(t, []string{"registry", "validate", "--strict", getTestPackRegistryPath(t)})
+	must.Eq(t, 1, result.exitCode)
+	must.StrContains(t, result.cmdOut.String(), "missing README")
+
+	registryPath := t.TempDir()
+	packPath := path.Join(registryPath, "packs", "broken")
+	must.NoError(t, filesystem.CopyDir(getTestPackPath(t, testPack), packPath, false, logging.Default()))
+	must.NoError(t, os.WriteFile(path.Join(packPath, "variables.hcl"), []byte(`variable "count" { type = nope }`), 0644))
+
+	result = runPackCmd(t, []string{"registry", "validate", registryPath})
+	must.Eq(t, 1, result.exitCode)
+	must.StrContains(t, result.cmdOut.String(), "packs/broken")
+	must.StrContains(t, result.cmdOut.String(), `The keyword "nope" is not a valid type specification.`)
+	must.StrContains(t, result.cmdOut.String(), "1 of 1 packs failed validation")
+}
+
+func TestCLI_RegistryInfoAll(t *testing.T) {
+	reg, _, regPath := createTestRegistries(t)
+	defer cleanTestRegistry(t, regPath)
+
+	packPath := path.Join(regPath, "latest", "broken@latest")
+	must.NoError(t, filesystem.CopyDir(getTestPackPath(t, testPack), packPath, false, logging.Default()))
+	must.NoError(t, os.WriteFile(path.Join(packPath, "variables.hcl"), []byte(`variable "count" { type = nope }`), 0644))
+
+	result := runPackCmd(t, []string{"registry", "info-all", "--registry=" + reg.Name, "--parallelism=2"})
+	must.Zero(t, result.exitCode)
+
+	var docs []packInfoDocument
+	must.NoError(t, json.Unmarshal(result.cmdOut.Bytes(), &docs))
+
+	// The broken pack is output with an error, without failing the others.
+	byName := make(map[string]packInfoDocument, len(docs))
+	for _, doc := range docs {
+		byName[doc.Name+"@"+doc.Ref] = doc
+	}
+	must.MapLen(t, 3, byName)
+	must.StrContains(t, byName["broken@latest"].Error, `The keyword "nope" is not a valid type specification.`)
+	for _, ref := range []string{"latest", testRef} {
+		doc := byName[testPack+"@"+ref]
+		must.Eq(t, "", doc.Error)
+		must.Eq(t, reg.Name, doc.Registry)
+		must.SliceContains(t, doc.Variables, packInfoVariable{
+			Pack: testPack, Name: "count", Type: "number",
+			Description: "The number of app instances to deploy", Default: "1",
+		})
+	}
+
+	result = runPackCmd(t, []string{"registry", "info-all", "--registry=" + reg.Name, "--parallelism=0"})
+	must.Eq(t, 1, result.exitCode)
+	must.StrContains(t, result.cmdOut.String(), "--parallelism must be at least 1")
+}
+
+func TestCLI_PackInfo_OutputPlain(t *testing.T) {
+	t.Parallel()
+
+	result := runPackCmd(t, []string{"info", "--output=plain", getTestPackPath(t, testPack)})
+	must.Zero(t, result.exitCode)
+
+	out := result.cmdOut.String()
+	must.StrContains(t, out, "Pack Name:       simple_raw_exec\n")
+	must.StrContains(t, out, "Pack \"simple_raw_exec\" Variables:\n")
+	must.StrContains(t, out, `  - "command" (string: optional) - bash command to run`+"\n"+
+		`  - "count" (number: optional) - The number of app instances to deploy`)
+
+	// The output is deterministic between runs.
+	again := runPackCmd(t, []string{"info", "--output=plain", getTestPackPath(t, testPack)})
+	must.Eq(t, out, again.cmdOut.String())
+}
+
+func Test_ValidateTLSConfig(t *testing.T) {
+	dir := t.TempDir()
+	notPEM := filepath.Join(dir, "ca.txt")
+	must.NoError(t, os.WriteFile(notPEM, []byte("not a certificate"), 0o644))
+
+	testCases := []struct {
+		name   string
+		conf   *api.TLSConfig
+		errMsg string
+	}{
+		{
+			name: "empty",
+			conf: &api.TLSConfig{},
+		},
+		{
+			name:   "missing CA cert",
+			conf:   &api.TLSConfig{CACert: filepath.Join(dir, "missing.pem")},
+			errMsg: "failed to read CA certificate",
+		},
+		{
+			name:   "invalid CA cert",
+			conf:   &api.TLSConfig{CACert: notPEM},
+			errMsg: "no PEM encoded certificates found",
+		},
+		{
+			name:   "client cert without key",
+			conf:   &api.TLSConfig{ClientCert: notPEM},
+			errMsg: "a client key must be provided",
+		},
+		{
+			name:   "client key without cert",
+			conf:   &api.TLSConfig{ClientKey: notPEM},
+			errMsg: "a client certificate must be provided",
+		},
+		{
+			name:   "invalid client cert",
+			conf:   &api.TLSConfig{ClientCert: notPEM, ClientKey: notPEM},
+			errMsg: "failed to load client certificate",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateTLSConfig(tc.conf)
+			if tc.errMsg == "" {
+				must.NoError(t, err)
+				return
+			}
+			must.ErrorContains(t, err, tc.errMsg)
+		})
+	}
+}
+
+func Test_ValidateAddress(t *testing.T) {
+	dir := t.TempDir()
+	notSocket := filepath.Join(dir, "nomad.txt")
+	must.NoError(t, os.WriteFile(notSocket, nil, 0o644))
+
+	sock := filepath.Join(dir, "nomad.sock")
+	l, err := net.Listen("unix", sock)
+	must.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+
+	testCases := []struct {
+		name   string
+		addr   string
+		errMsg string
+	}{
+		{
+			name: "http",
+			addr: "http://127.0.0.1:4646",
+		},
+		{
+			name: "socket",
+			addr: "unix://" + sock,
+		},
+		{
+			name:   "relative socket",
+			addr:   "unix://nomad.sock",
+			errMsg: "the socket must be an absolute path",
+		},
+		{
+			name:   "missing socket",
+			addr:   "unix://" + filepath.Join(dir, "missing.sock"),
+			errMsg: "failed to access Nomad socket",
+		},
+		{
+			name:   "not a socket",
+			addr:   "unix://" + notSocket,
+			errMsg: "is not a Unix domain socket",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateAddress(tc.addr)
+			if tc.errMsg == "" {
+				must.NoError(t, err)
+				return
+			}
+			must.ErrorContains(t, err, tc.errMsg)
+		})
+	}
+}
+
+func TestCLI_ExtractChdirOption(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		args    []string
+		dir     string
+		rest    []string
+		wantErr bool
+	}{
+		{
+			desc: "no option",
+			args: []string{"nomad-pack", "info", "example"},
+			rest: []string{"nomad-pack", "info", "example"},
+		},
+		{
+			desc: "equals form",
+			args: []string{"nomad-pack", "--chdir=/tmp", "info", "example"},
+			dir:  "/tmp",
+			rest: []string{"nomad-pack", "info", "example"},
+		},
+		{
+			desc: "separate value",
+			args: []string{"nomad-pack", "-chdir", "/tmp", "info", "example"},
+			dir:  "/tmp",
+			rest: []string{"nomad-pack", "info", "example"},
+		},
+		{
+			desc: "after command",
+			args: []string{"nomad-pack", "info", "--chdir=/tmp", "example"},
+			rest: []string{"nomad-pack", "info", "--chdir=/tmp", "example"},
+		},
+		{
+			desc:    "missing value",
+			args:    []string{"nomad-pack", "--chdir"},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			dir, rest, err := extractChdirOption(tc.args)
+			if tc.wantErr {
+				must.Error(t, err)
+				return
+			}
+			must.NoError(t, err)
+			must.Eq(t, tc.dir, dir)
+			must.Eq(t, tc.rest, rest)
+		})
+	}
+}
+
 func TestCLI_CLIFlag_Namespace(t *testing.T) {
 	testCases := []struct {
 		desc   string
@@ -851,6 +1261,7 @@ func runPackCmd(t *testing.T, args []string) PackCommandResult {
 	if err != nil {
 		panic(err)
 	}
+	base.writeSummary(exitCode)
 
 	must.Eq(t, cmdErr.String(), "", must.Sprintf("cmdErr should be empty, but was %q", cmdErr.String()))
 
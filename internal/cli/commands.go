@@ -19,6 +19,7 @@ import (
 	"github.com/hashicorp/nomad-pack/internal/pkg/cache"
 	flag "github.com/hashicorp/nomad-pack/internal/pkg/flag"
 	"github.com/hashicorp/nomad-pack/internal/pkg/variable/envloader"
+	"github.com/hashicorp/nomad-pack/internal/pkg/variable/parser/config"
 	"github.com/hashicorp/nomad-pack/terminal"
 )
 
@@ -59,13 +60,34 @@ type baseCommand struct {
 	// vars sets values for defined input variables
 	vars map[string]string
 
+	// varPatches are JSON Patch documents applied to map and object input
+	// variables, keyed by the variable name
+	varPatches map[string]string
+
 	// envVars sets values for defined input variables from the environment
 	envVars map[string]string
 
+	// resolveVarRefs resolves the values of vars and envVars which reference
+	// a value resolver, such as vault:secret/data/app#password, as passed
+	// using the --resolve-var-refs flag.
+	resolveVarRefs bool
+
 	// varFiles is an HCL file(s) setting one or more values
 	// for defined input variables
 	varFiles []string
 
+	// profile is the name of the variable profile whose overrides are
+	// applied, as passed using the --profile flag.
+	profile string
+
+	// profileFile is the path to the file defining the variable profiles,
+	// which defaults to the profiles file of the pack.
+	profileFile string
+
+	// maxVarFileSize is the maximum size, in bytes, of the variable and
+	// profile files, as passed using the --max-var-file-size flag.
+	maxVarFileSize int64
+
 	// allowUnsetVars suppresses errors from variables with nil values,
 	// i.e. those that are not set and have no default
 	allowUnsetVars bool
@@ -85,6 +107,58 @@ type baseCommand struct {
 	// useParserV1 is true when the user supplies the --parser-v1 flag
 	useParserV1 bool
 
+	// groupErrors is true when the user supplies the --group-errors flag and
+	// errors from batch operations should be output together once complete.
+	groupErrors bool
+
+	// stylePrefixes replaces the prefixes of the non-interactive output, as
+	// passed using the --style-prefixes flag.
+	stylePrefixes string
+
+	// noColor is true when the user supplies the --no-color flag and the
+	// output should not be colored.
+	noColor bool
+
+	// quiet is true when the user supplies the --quiet flag and only errors
+	// and warnings should be output.
+	quiet bool
+
+	// errorContextKeys are the keys of the error context entries which are
+	// output, as passed using the --error-context-keys flag. If empty, every
+	// entry is output.
+	errorContextKeys []string
+
+	// summaryFD is the file descriptor a JSON summary of the command run is
+	// written to, as passed using the --summary-fd flag. If 0, no summary is
+	// written.
+	summaryFD int
+
+	// summaryFile is the file opened for summaryFD. It is held for the life of
+	// the command, as the file descriptor is closed once it is unreachable.
+	summaryFile *os.File
+
+	// summary records the warnings and errors output by the command, for the
+	// summary written to summaryFile.
+	summary *terminal.SummaryRecorder
+
+	// failFast is true when the user supplies the --fail-fast flag and
+	// operations over multiple items should stop at the first failure rather
+	// than collecting the failures and continuing.
+	failFast bool
+
+	// noCache is true when the user supplies the --no-cache flag and the pack
+	// should be fetched to a temporary directory rather than the global cache.
+	noCache bool
+
+	// noParseCache is true when the user supplies the --no-parse-cache flag
+	// and variables should always be parsed rather than read from the cache.
+	noParseCache bool
+
+	// allowExternalSymlinks is true when the user supplies the
+	// --allow-external-symlinks flag and packs may contain symlinks which
+	// resolve outside of the pack directory.
+	allowExternalSymlinks bool
+
 	// args that were present after parsing flags
 	args []string
 
@@ -121,6 +195,21 @@ func (c *baseCommand) Close() error {
 	return nil
 }
 
+// flushErrors outputs any errors held back by the UI when the --group-errors
+// flag is set. It is a no-op otherwise.
+func (c *baseCommand) flushErrors() {
+	ui := c.ui
+	if r, ok := ui.(*terminal.SummaryRecorder); ok {
+		ui = r.UI
+	}
+	if f, ok := ui.(*terminal.ContextFilter); ok {
+		ui = f.UI
+	}
+	if ec, ok := ui.(*terminal.ErrorCollector); ok {
+		ec.Flush()
+	}
+}
+
 func (c *baseCommand) IsWindows() bool {
 	return runtime.GOOS == "windows"
 }
@@ -184,10 +273,14 @@ func (c *baseCommand) Init(opts ...Option) error {
 		opt(&baseCfg)
 	}
 
+	// Buffer the output of steps, so the output of steps which run
+	// concurrently is readable when it is not written to a terminal.
+	uiOpts := []terminal.UIOption{terminal.WithBufferedSteps()}
+
 	// Init our UI first so we can write output to the user immediately.
 	ui := baseCfg.UI
 	if ui == nil {
-		ui = terminal.ConsoleUI(c.Ctx)
+		ui = terminal.NewUI(c.Ctx, uiOpts...)
 	}
 
 	c.ui = ui
@@ -199,6 +292,33 @@ func (c *baseCommand) Init(opts ...Option) error {
 	}
 	c.args = baseCfg.Flags.Args()
 
+	// Recreate the UI once the options of its output are known.
+	if c.stylePrefixes != "" {
+		prefixes, err := parseStylePrefixes(c.stylePrefixes)
+		if err != nil {
+			return err
+		}
+		uiOpts = append(uiOpts, terminal.WithStylePrefixes(prefixes))
+	}
+	if c.noColor {
+		uiOpts = append(uiOpts, terminal.WithNoColor())
+	}
+	if baseCfg.UI == nil && (c.stylePrefixes != "" || c.noColor) {
+		c.ui = terminal.NewUI(c.Ctx, uiOpts...)
+	}
+
+	if c.profileFile != "" && c.profile == "" {
+		return errors.New("--profile-file requires --profile to be set")
+	}
+
+	if c.maxVarFileSize < 0 {
+		return errors.New("--max-var-file-size must not be negative")
+	}
+
+	if c.summaryFile, err = openSummaryFD(c.summaryFD); err != nil {
+		return err
+	}
+
 	c.envVars = envloader.New().GetVarsFromEnv()
 
 	// if no flag, check env vars
@@ -214,14 +334,37 @@ func (c *baseCommand) Init(opts ...Option) error {
 		}
 	}
 
-	// Reset the UI to plain if that was set
-	if c.flagPlain {
-		c.ui = terminal.NonInteractiveUI(c.Ctx)
+	// Reset the UI to plain if that was set. Quiet output is always plain,
+	// as the interactive UI has no quiet mode.
+	if c.quiet {
+		uiOpts = append(uiOpts, terminal.WithQuiet())
+	}
+	if c.flagPlain || c.quiet {
+		c.ui = terminal.NonInteractiveUI(c.Ctx, uiOpts...)
+	}
+
+	// Hold back errors so they can be output together if requested. The
+	// command is responsible for calling flushErrors once it completes.
+	if c.groupErrors {
+		c.ui = terminal.NewErrorCollector(c.ui)
+	}
+
+	// Filter the error context before it is collected, as grouped errors
+	// output their shared context directly.
+	if len(c.errorContextKeys) > 0 {
+		c.ui = terminal.NewContextFilter(c.ui, c.errorContextKeys)
+	}
+
+	// Record the output for the summary last, so each warning and error is
+	// recorded once as the command outputs it.
+	if c.summaryFile != nil {
+		c.summary = terminal.NewSummaryRecorder(c.ui)
+		c.ui = c.summary
 	}
 
 	// Perform the cache ensure, but skip if we are running the version
-	// command.
-	if c.cmdKey != "version" {
+	// command or the global cache should not be touched.
+	if c.cmdKey != "version" && !c.noCache {
 		return c.ensureCache()
 	}
 
@@ -260,6 +403,35 @@ func (c *baseCommand) flagSet(bit flagSetBit, f func(*flag.Sets)) *flag.Sets {
 			Shorthand: "f",
 		})
 
+		f.StringVar(&flag.StringVar{
+			Name:    "profile",
+			Target:  &c.profile,
+			Default: "",
+			Usage: `The name of a variable profile whose overrides are applied.
+					Profiles are defined in the profiles.hcl file of the pack,
+					or the file passed using --profile-file, and can inherit
+					the overrides of other profiles. Profile overrides take
+					precedence over environment variables, and are replaced
+					by --var-file and --var overrides.`,
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:       "profile-file",
+			Target:     &c.profileFile,
+			Default:    "",
+			Usage:      `Specifies the path to the file defining the variable profiles.`,
+			Completion: complete.PredictFiles("*.hcl"),
+		})
+
+		f.Int64Var(&flag.Int64Var{
+			Name:    "max-var-file-size",
+			Target:  &c.maxVarFileSize,
+			Default: config.DefaultMaxVariableFileSize,
+			Usage: `Maximum size, in bytes, of each variable and profile file.
+					Larger files are rejected rather than read, as are files
+					which are not regular files, such as devices.`,
+		})
+
 		f.BoolVar(&flag.BoolVar{
 			Name:    "allow-unset-vars",
 			Target:  &c.allowUnsetVars,
@@ -282,7 +454,37 @@ func (c *baseCommand) flagSet(bit flagSetBit, f func(*flag.Sets)) *flag.Sets {
 			Target:  &c.vars,
 			Default: make(map[string]string),
 			Usage: `Specifies single override variables in the form of HCL
-					syntax and can be specified multiple times per command.`,
+					syntax and can be specified multiple times per command.
+					Individual entries of a map or object variable can be set
+					using a dotted name, such as labels.env=prod, and are
+					merged with the variable's default value. A type hint
+					can follow the name to force how the value is
+					interpreted, such as port:number=8080.`,
+		})
+
+		f.StringMapVar(&flag.StringMapVar{
+			Name:    "var-patch",
+			Target:  &c.varPatches,
+			Default: make(map[string]string),
+			Usage: `Applies a JSON Patch (RFC 6902) document to a map or object
+					variable, in the form name='[{"op":"replace","path":"/a/b","value":1}]'.
+					The patch is applied after the value of the variable has
+					been resolved from all other sources, and can be specified
+					once per variable.`,
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "resolve-var-refs",
+			Target:  &c.resolveVarRefs,
+			Default: false,
+			Usage: `Resolve the values of --var flags and environment variables
+					which reference a value resolver, in the form
+					<resolver>:<reference>, when parsing the variables. The
+					vault resolver reads a field of a Vault secret, such as
+					vault:secret/data/app#password, the consul resolver reads
+					a Consul key, and the env resolver reads an environment
+					variable. Values resolved from Vault are sensitive, so
+					they are not recorded with the deployed pack.`,
 		})
 
 		f.StringVar(&flag.StringVar{
@@ -311,6 +513,68 @@ func (c *baseCommand) flagSet(bit flagSetBit, f func(*flag.Sets)) *flag.Sets {
 			enables pack to run packs for earlier versions while you are
 			migrating them to the new syntax`,
 		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "group-errors",
+			Target:  &c.groupErrors,
+			Default: false,
+			Usage: `Collect the errors produced by the command and output them
+					together once it completes. Errors which share the same
+					context, such as the pack name, are grouped under a single
+					context block and duplicate errors are only output once.`,
+		})
+
+		f.StringSliceVar(&flag.StringSliceVar{
+			Name:    "error-context-keys",
+			Target:  &c.errorContextKeys,
+			Default: make([]string, 0),
+			Usage: `Only output the error context entries with these keys, such
+					as pack-name or template-name. The details and suggestions
+					of an error are always output. This can be provided
+					multiple times, or as a comma separated list. If not
+					specified, all context is output.`,
+		})
+
+		f.IntVar(&flag.IntVar{
+			Name:    "summary-fd",
+			Target:  &c.summaryFD,
+			Default: 0,
+			Usage: `Write a JSON summary of the command run, including its exit
+					code and the warnings and errors it output, to this open
+					file descriptor once it completes. This separates the
+					output read by machines, such as CI systems, from stdout
+					and stderr. If not specified, no summary is written.`,
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "fail-fast",
+			Target:  &c.failFast,
+			Default: false,
+			Usage: `Stop at the first failure when the command operates on
+					multiple items, such as the jobs of a pack, and return
+					that error. By default, failures are collected and the
+					command continues with the remaining items.`,
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "allow-external-symlinks",
+			Target:  &c.allowExternalSymlinks,
+			Default: false,
+			Usage: `Allow the pack to contain symlinks which resolve to a path
+					outside of the pack directory. By default, such packs fail
+					to load, as the symlinks may expose files from elsewhere
+					on the host.`,
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "no-parse-cache",
+			Target:  &c.noParseCache,
+			Default: false,
+			Usage: `Do not use the cache of parsed variables. By default, the
+					result of parsing the pack variables is cached and reused
+					when the pack, variable files, and variable flags are
+					unchanged.`,
+		})
 	}
 	if bit&flagSetNeedsApproval != 0 {
 		f := set.NewSet("Approval Options")
@@ -332,7 +596,9 @@ func (c *baseCommand) flagSet(bit flagSetBit, f func(*flag.Sets)) *flag.Sets {
 			Name:    "address",
 			Target:  &c.nomadConfig.address,
 			Default: "",
-			Usage: `The address of the Nomad server.
+			Usage: `The address of the Nomad server. A Nomad server reachable
+					only through a Unix domain socket is addressed using the
+					path of the socket, such as unix:///var/run/nomad.sock.
 					Overrides the NOMAD_ADDR environment variable if set.`,
 		})
 
@@ -407,6 +673,43 @@ func (c *baseCommand) flagSet(bit flagSetBit, f func(*flag.Sets)) *flag.Sets {
 		})
 	}
 
+	{
+		f := set.NewSet("Output Options")
+		f.StringVar(&flag.StringVar{
+			Name:    "style-prefixes",
+			Target:  &c.stylePrefixes,
+			Default: "",
+			Usage: fmt.Sprintf(`Replace the prefixes output before headers, errors,
+					and steps when the output is not written to a terminal,
+					as a comma separated list of key=prefix pairs, such as
+					"header=# ,error=ERROR: ". The keys are header, error,
+					and step, and an empty prefix disables it. The value
+					%q disables every prefix, which suits strict line
+					oriented log processors.`, stylePrefixesNone),
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "no-color",
+			Target:  &c.noColor,
+			Default: false,
+			Usage: `Disable colored output, such as when the output is
+					captured by a log. Setting the NO_COLOR environment
+					variable has the same effect. The tree output by
+					status --tree is drawn using ASCII characters.`,
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "quiet",
+			Target:  &c.quiet,
+			Default: false,
+			Usage: `Only output errors and warnings, which are written to
+					stderr, along with the output of the command itself,
+					such as rendered templates. Headers, informational
+					messages, tables, and named values are not output,
+					which suits scripts that only check the exit code.`,
+		})
+	}
+
 	if f != nil {
 		// Configure our values
 		f(set)
@@ -444,5 +747,12 @@ var (
 )
 
 func (c *baseCommand) getAPIClient() (*api.Client, error) {
-	return api.NewClient(clientOptsFromCLI(c))
+	conf := clientOptsFromCLI(c)
+	if err := validateAddress(conf.Address); err != nil {
+		return nil, err
+	}
+	if err := validateTLSConfig(conf.TLSConfig); err != nil {
+		return nil, err
+	}
+	return api.NewClient(conf)
 }
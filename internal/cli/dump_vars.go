@@ -0,0 +1,96 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+
+	"github.com/hashicorp/nomad-pack/internal/pkg/variable/parser"
+	"github.com/hashicorp/nomad-pack/sdk/pack"
+)
+
+// dumpedVariable is a single resolved variable output by info --dump-vars.
+type dumpedVariable struct {
+	// name is relative to the parent pack, such as child.count, which is how
+	// the variable is set within a variable file.
+	name      string
+	value     cty.Value
+	sensitive bool
+}
+
+// set returns whether the variable has a value which can be written to a
+// variable file.
+func (v dumpedVariable) set() bool {
+	return !v.value.IsNull() && v.value.IsWhollyKnown()
+}
+
+// dumpedVariables returns the resolved variables of the pack and its
+// dependencies, ordered by pack and variable name.
+func dumpedVariables(parsedVars *parser.ParsedVariables, parentID pack.ID) []dumpedVariable {
+	var out []dumpedVariable
+
+	vars := parsedVars.GetVars()
+	for _, pID := range slices.Sorted(maps.Keys(vars)) {
+		for _, vID := range slices.Sorted(maps.Keys(vars[pID])) {
+			v := vars[pID][vID]
+			out = append(out, dumpedVariable{
+				name:      strings.TrimPrefix(pID.Join(pack.ID(vID)).String(), parentID.String()+"."),
+				value:     v.Value,
+				sensitive: v.Sensitive,
+			})
+		}
+	}
+	return out
+}
+
+// formatVarFileHCL formats the variables as an HCL variable file. Variables
+// without a value, and sensitive variables unless showSensitive is set, are
+// written as comments so the file can be passed to a later run as is.
+func formatVarFileHCL(vars []dumpedVariable, showSensitive bool) string {
+	var b strings.Builder
+	for _, v := range vars {
+		switch {
+		case v.sensitive && !showSensitive:
+			fmt.Fprintf(&b, "# %s is sensitive and has been redacted\n", v.name)
+		case !v.set():
+			fmt.Fprintf(&b, "# %s is not set\n", v.name)
+		default:
+			// hclwrite escapes any template sequences within strings, so the
+			// value is read back as is.
+			fmt.Fprintf(&b, "%s = %s\n", v.name, hclwrite.TokensForValue(v.value).Bytes())
+		}
+	}
+	return b.String()
+}
+
+// formatVarFileJSON formats the variables as a JSON variable file. JSON has
+// no comments, so variables without a value, and sensitive variables unless
+// showSensitive is set, are omitted.
+func formatVarFileJSON(vars []dumpedVariable, showSensitive bool) (string, error) {
+	out := make(map[string]json.RawMessage)
+	for _, v := range vars {
+		if (v.sensitive && !showSensitive) || !v.set() {
+			continue
+		}
+		b, err := ctyjson.Marshal(v.value, v.value.Type())
+		if err != nil {
+			return "", fmt.Errorf("failed to encode variable %q: %w", v.name, err)
+		}
+		out[v.name] = b
+	}
+
+	b, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(b) + "\n", nil
+}
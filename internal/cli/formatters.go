@@ -4,10 +4,17 @@
 package cli
 
 import (
+	"fmt"
+	"regexp"
 	"strings"
 	"time"
 
+	"github.com/fatih/color"
+	"github.com/hashicorp/nomad/api"
 	"github.com/ryanuber/columnize"
+
+	"github.com/hashicorp/nomad-pack/internal/pkg/helper"
+	"github.com/hashicorp/nomad-pack/internal/pkg/helper/pointer"
 )
 
 // formatList takes a set of strings and formats them into properly
@@ -33,7 +40,7 @@ func formatTime(t time.Time) string {
 // truncating to a passed unit.
 // E.g. formatTimeDifference(first=1m22s33ms, second=1m28s55ms, time.Second) -> 6s
 func formatTimeDifference(first, second time.Time, d time.Duration) string {
-	return second.Truncate(d).Sub(first.Truncate(d)).String()
+	return helper.FormatDuration(second.Truncate(d).Sub(first.Truncate(d)))
 }
 
 func formatSHA1Reference(in string) string {
@@ -48,3 +55,104 @@ func formatSHA1Reference(in string) string {
 	}
 	return in[:l]
 }
+
+const (
+	// jobKindPeriodic is the first word of the status of a periodic job.
+	jobKindPeriodic = "periodic"
+
+	// jobKindParameterized is the first word of the status of a
+	// parameterized job.
+	jobKindParameterized = "parameterized"
+)
+
+// formatPeriodicStatus formats the status of a periodic job from the time it
+// last launched a child job and the summary of its children.
+func formatPeriodicStatus(lastRun time.Time, children *api.JobChildrenSummary) string {
+	details := []string{"not yet run"}
+	if !lastRun.IsZero() {
+		details = []string{"last run: " + formatTime(lastRun)}
+	}
+	if children != nil && children.Running > 0 {
+		details = append(details, fmt.Sprintf("%d running", children.Running))
+	}
+	return fmt.Sprintf("%s (%s)", jobKindPeriodic, strings.Join(details, ", "))
+}
+
+// formatParameterizedStatus formats the status of a parameterized job from the
+// summary of the jobs dispatched from it.
+func formatParameterizedStatus(children *api.JobChildrenSummary) string {
+	if children == nil || children.Sum() == 0 {
+		return jobKindParameterized + " (none dispatched)"
+	}
+	return fmt.Sprintf("%s (%d dispatched, %d running)", jobKindParameterized, children.Sum(), children.Running)
+}
+
+// formatEvent formats a Nomad event as a single line, summarizing the object
+// within its payload. The key of the event is used if the payload cannot be
+// decoded.
+func formatEvent(ev *api.Event) string {
+	var details string
+	switch ev.Topic {
+	case api.TopicAllocation:
+		if alloc, err := ev.Allocation(); err == nil && alloc != nil {
+			details = fmt.Sprintf("job %q allocation %s on node %q: %s (desired: %s)",
+				alloc.JobID, shortID(alloc.ID), alloc.NodeName, alloc.ClientStatus, alloc.DesiredStatus)
+		}
+	case api.TopicDeployment:
+		if d, err := ev.Deployment(); err == nil && d != nil {
+			details = fmt.Sprintf("job %q deployment %s: %s", d.JobID, shortID(d.ID), d.Status)
+			if d.StatusDescription != "" {
+				details += " - " + d.StatusDescription
+			}
+		}
+	case api.TopicEvaluation:
+		if eval, err := ev.Evaluation(); err == nil && eval != nil {
+			details = fmt.Sprintf("job %q evaluation %s triggered by %s: %s",
+				eval.JobID, shortID(eval.ID), eval.TriggeredBy, eval.Status)
+		}
+	case api.TopicJob:
+		if job, err := ev.Job(); err == nil && job != nil && job.ID != nil {
+			details = fmt.Sprintf("job %q version %d: %s", *job.ID, pointer.Value(job.Version), pointer.Value(job.Status))
+		}
+	}
+	if details == "" {
+		details = ev.Key
+	}
+	return fmt.Sprintf("%s %s %s", ev.Topic, ev.Type, details)
+}
+
+// markdownEmphasis matches bold and inline code spans within a line of
+// markdown.
+var markdownEmphasis = regexp.MustCompile("\\*\\*([^*]+)\\*\\*|`([^`]+)`")
+
+// formatMarkdown renders markdown as styled terminal text. Headings are bold
+// and underlined, bold spans are bold, and code is faint. The markdown syntax
+// used for these elements is removed, all other content is unchanged.
+func formatMarkdown(in string) string {
+	heading := color.New(color.Bold, color.Underline).SprintFunc()
+	bold := color.New(color.Bold).SprintFunc()
+	code := color.New(color.Faint).SprintFunc()
+
+	var inFence bool
+	lines := strings.Split(in, "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "```"):
+			inFence = !inFence
+			lines[i] = ""
+		case inFence:
+			lines[i] = code("    " + line)
+		case strings.HasPrefix(trimmed, "#"):
+			lines[i] = heading(strings.TrimSpace(strings.TrimLeft(trimmed, "#")))
+		default:
+			lines[i] = markdownEmphasis.ReplaceAllStringFunc(line, func(m string) string {
+				if strings.HasPrefix(m, "**") {
+					return bold(strings.Trim(m, "*"))
+				}
+				return code(strings.Trim(m, "`"))
+			})
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -4,10 +4,31 @@
 package cli
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/nomad/api"
 	"github.com/shoenig/test/must"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/hashicorp/nomad-pack/internal/pkg/cache"
+	"github.com/hashicorp/nomad-pack/internal/pkg/helper/pointer"
+	"github.com/hashicorp/nomad-pack/internal/pkg/renderer"
+	"github.com/hashicorp/nomad-pack/internal/pkg/variable/decoder"
+	"github.com/hashicorp/nomad-pack/internal/pkg/variable/parser"
+	"github.com/hashicorp/nomad-pack/internal/pkg/variable/schema"
+	"github.com/hashicorp/nomad-pack/internal/runner/job"
+	"github.com/hashicorp/nomad-pack/sdk/pack"
+	"github.com/hashicorp/nomad-pack/sdk/pack/variables"
+	"github.com/hashicorp/nomad-pack/terminal"
 )
 
 func Test_FormatList(t *testing.T) {
@@ -62,3 +83,1391 @@ func Test_FormatTimeDifference(t *testing.T) {
 	second := first.Add(6*time.Second + 22*time.Millisecond)
 	must.Eq(t, "6s", formatTimeDifference(first, second, time.Second))
 }
+
+func Test_FormatPeriodicStatus(t *testing.T) {
+	lastRun := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	must.Eq(t, "periodic (not yet run)", formatPeriodicStatus(time.Time{}, nil))
+	must.Eq(t, "periodic (last run: 2024-01-02T03:04:05Z)",
+		formatPeriodicStatus(lastRun, &api.JobChildrenSummary{Dead: 3}))
+	must.Eq(t, "periodic (last run: 2024-01-02T03:04:05Z, 1 running)",
+		formatPeriodicStatus(lastRun, &api.JobChildrenSummary{Running: 1, Dead: 3}))
+}
+
+func Test_FormatParameterizedStatus(t *testing.T) {
+	must.Eq(t, "parameterized (none dispatched)", formatParameterizedStatus(nil))
+	must.Eq(t, "parameterized (none dispatched)", formatParameterizedStatus(&api.JobChildrenSummary{}))
+	must.Eq(t, "parameterized (4 dispatched, 2 running)",
+		formatParameterizedStatus(&api.JobChildrenSummary{Pending: 1, Running: 2, Dead: 1}))
+}
+
+func Test_FormatMarkdown(t *testing.T) {
+	in := strings.Join([]string{
+		"# My Pack",
+		"",
+		"Deploys **hello** using `docker`.",
+		"",
+		"```",
+		"nomad-pack run my_pack",
+		"```",
+	}, "\n")
+
+	// Color is disabled when testing, so only the markdown syntax is removed.
+	expect := strings.Join([]string{
+		"My Pack",
+		"",
+		"Deploys hello using docker.",
+		"",
+		"",
+		"    nomad-pack run my_pack",
+		"",
+	}, "\n")
+	must.Eq(t, expect, formatMarkdown(in))
+}
+
+func Test_DeploymentRefConflicts(t *testing.T) {
+	packJobs := []JobStatusInfo{
+		{deploymentName: "dev", packRef: "v2", jobID: "b"},
+		{deploymentName: "dev", packRef: "v1", jobID: "a"},
+		{deploymentName: "prod", packRef: "v1", jobID: "c"},
+		{deploymentName: "prod", packRef: "v1", jobID: "d"},
+	}
+	must.Eq(t, map[string][]string{"dev": {"v1", "v2"}}, deploymentRefConflicts(packJobs))
+
+	tbl := formatDeployedPackJobs(packJobs, jobTableOptions{splitByRef: true})
+	must.Eq(t, []string{"Pack Name", "Registry Name", "Deployment Name", "Pack Ref", "Job Name", "Status", "Running", "Pending", "Failed"}, tbl.Headers)
+	must.Eq(t, [][]string{
+		{"", "", "dev", "v1", "a", "", "", "", ""},
+		{"", "", "dev", "v2", "b", "", "", "", ""},
+		{"", "", "prod", "v1", "c", "", "", "", ""},
+		{"", "", "prod", "v1", "d", "", "", "", ""},
+	}, tbl.Rows)
+}
+
+func Test_FilterJobsByStatus(t *testing.T) {
+	packJobs := func() []JobStatusInfo {
+		return []JobStatusInfo{
+			{jobID: "web", status: "running"},
+			{jobID: "db", status: "pending"},
+			{jobID: "batch", status: "dead"},
+			{jobID: "cleanup", status: formatPeriodicStatus(time.Time{}, nil)},
+		}
+	}
+
+	jobs := filterJobsByStatus(packJobs(), []string{"running", "dead"})
+	must.Eq(t, []JobStatusInfo{{jobID: "web", status: "running"}, {jobID: "batch", status: "dead"}}, jobs)
+
+	// Periodic jobs are matched by their kind rather than the summary of
+	// their children.
+	jobs = filterJobsByStatus(packJobs(), []string{"periodic"})
+	must.Len(t, 1, jobs)
+	must.Eq(t, "cleanup", jobs[0].jobID)
+
+	must.SliceEmpty(t, filterJobsByStatus(packJobs(), []string{"parameterized"}))
+}
+
+func Test_FilterJobsByNode(t *testing.T) {
+	packJobs := []JobStatusInfo{{jobID: "web"}, {jobID: "db"}}
+	jobAllocs := map[string][]*api.AllocationListStub{
+		"web": {
+			{ID: "a1", NodeID: "f7476465-4d6e-c0de-26d0-e383c49be941", NodeName: "client-1"},
+			{ID: "a2", NodeID: "0a1b2c3d-4d6e-c0de-26d0-e383c49be941", NodeName: "client-2"},
+		},
+		"db": {
+			{ID: "a3", NodeID: "0a1b2c3d-4d6e-c0de-26d0-e383c49be941", NodeName: "client-2"},
+		},
+	}
+
+	jobs, allocs := filterJobsByNode(packJobs, jobAllocs, "f7476465")
+	must.Eq(t, []JobStatusInfo{{jobID: "web"}}, jobs)
+	must.Len(t, 1, allocs["web"])
+	must.Eq(t, "a1", allocs["web"][0].ID)
+
+	jobs, allocs = filterJobsByNode(packJobs, jobAllocs, "client-2")
+	must.Len(t, 2, jobs)
+	must.Eq(t, "a2", allocs["web"][0].ID)
+	must.Eq(t, "a3", allocs["db"][0].ID)
+
+	jobs, _ = filterJobsByNode(packJobs, jobAllocs, "missing")
+	must.SliceEmpty(t, jobs)
+
+	tbl := formatPackJobAllocs(packJobs, jobAllocs)
+	must.Eq(t, []string{"web", "a1", "f7476465", "client-1", "", "", ""}, tbl.Rows[0])
+}
+
+func Test_FilterJobsByMeta(t *testing.T) {
+	newJobs := func() []JobStatusInfo {
+		return []JobStatusInfo{
+			{jobID: "web", meta: map[string]string{"team": "edge", "cost-center": "42"}},
+			{jobID: "api", meta: map[string]string{"team": "edge", "cost-center": "7"}},
+			{jobID: "db"},
+		}
+	}
+	jobIDs := func(jobs []JobStatusInfo) []string {
+		var ids []string
+		for _, info := range jobs {
+			ids = append(ids, info.jobID)
+		}
+		return ids
+	}
+
+	must.Eq(t, []string{"web", "api"}, jobIDs(filterJobsByMeta(newJobs(), map[string]string{"team": "edge"})))
+	must.Eq(t, []string{"web"}, jobIDs(filterJobsByMeta(newJobs(), map[string]string{"team": "edge", "cost-center": "42"})))
+	must.SliceEmpty(t, filterJobsByMeta(newJobs(), map[string]string{"team": "core"}))
+	must.SliceEmpty(t, filterJobsByMeta(newJobs(), map[string]string{"owner": ""}))
+
+	must.Eq(t, "cost-center=42, team=edge", formatMetaFilter(map[string]string{"team": "edge", "cost-center": "42"}))
+}
+
+func Test_FormatDeployedPackJobs_ShowVersion(t *testing.T) {
+	packJobs := []JobStatusInfo{
+		{packName: "example", jobID: "web", version: 3, modifyIndex: 42, status: "running"},
+	}
+
+	tbl := formatDeployedPackJobs(packJobs, jobTableOptions{showVersion: true})
+	must.Eq(t, []string{"Pack Name", "Registry Name", "Deployment Name", "Job Name", "Version", "Modify Index", "Status", "Running", "Pending", "Failed"}, tbl.Headers)
+	must.Eq(t, [][]string{{"example", "", "", "web", "3", "42", "running", "", "", ""}}, tbl.Rows)
+}
+
+func Test_FormatDeployedPackJobs_AllocCounts(t *testing.T) {
+	allocs := newAllocCounts(&api.JobSummary{Summary: map[string]api.TaskGroupSummary{
+		"web":   {Running: 2, Starting: 1, Failed: 1, Complete: 4},
+		"cache": {Running: 1, Queued: 2},
+	}})
+	must.Eq(t, &allocCounts{running: 3, pending: 3, failed: 1}, allocs)
+
+	// The counts of jobs whose allocation summary could not be retrieved are
+	// left blank.
+	packJobs := []JobStatusInfo{
+		{packName: "example", jobID: "web", status: "running", allocs: allocs},
+		{packName: "example", jobID: "db", status: "running"},
+	}
+	tbl := formatDeployedPackJobs(packJobs, jobTableOptions{})
+	must.Eq(t, [][]string{
+		{"example", "", "", "web", "running", "3", "3", "1"},
+		{"example", "", "", "db", "running", "", "", ""},
+	}, tbl.Rows)
+}
+
+func Test_FormatDeployedPackJobs_ExpectedRegistries(t *testing.T) {
+	packJobs := []JobStatusInfo{
+		{packName: "example", registryName: "approved", jobID: "api", namespace: "prod", status: "running"},
+		{packName: "example", registryName: "community", jobID: "web", namespace: "prod", status: "running"},
+		{packName: "example", registryName: "community", jobID: "dev", namespace: "dev", status: "running"},
+	}
+	expected := map[string]string{"prod": "approved"}
+
+	tbl := formatDeployedPackJobs(packJobs, jobTableOptions{expectedRegistries: expected})
+	must.Eq(t, []string{"Pack Name", "Registry Name", "Deployment Name", "Job Name", "Namespace", "Status", "Running", "Pending", "Failed", "Registry Check"}, tbl.Headers)
+	must.Eq(t, []string{"example", "approved", "", "api", "prod", "running", "", "", "", "ok"}, tbl.Rows[0])
+	must.StrContains(t, tbl.Rows[1][9], `expected registry "approved"`)
+	must.Eq(t, "", tbl.Rows[2][9])
+
+	must.Eq(t, 1, registryMismatches(packJobs, expected))
+	must.Eq(t, 0, registryMismatches(packJobs, nil))
+}
+
+func Test_FormatDeployedPackJobs_ShowNamespace(t *testing.T) {
+	packJobs := []JobStatusInfo{
+		{packName: "example", jobID: "web", namespace: "prod", status: "running"},
+		{packName: "example", jobID: "web", namespace: "dev", status: "pending"},
+	}
+
+	tbl := formatDeployedPackJobs(packJobs, jobTableOptions{showNamespace: true})
+	must.Eq(t, []string{"Pack Name", "Registry Name", "Deployment Name", "Job Name", "Namespace", "Status", "Running", "Pending", "Failed"}, tbl.Headers)
+	must.Eq(t, [][]string{
+		{"example", "", "", "web", "prod", "running", "", "", ""},
+		{"example", "", "", "web", "dev", "pending", "", "", ""},
+	}, tbl.Rows)
+}
+
+func Test_StatusCommand_AllNamespaces(t *testing.T) {
+	c := &StatusCommand{baseCommand: &baseCommand{}}
+
+	t.Setenv("NOMAD_NAMESPACE", "")
+	must.False(t, c.allNamespaces())
+
+	t.Setenv("NOMAD_NAMESPACE", "*")
+	must.True(t, c.allNamespaces())
+
+	c.nomadConfig.namespace = "prod"
+	must.False(t, c.allNamespaces())
+}
+
+func Test_FormatDeployedPackJobs_Columns(t *testing.T) {
+	packJobs := []JobStatusInfo{
+		{packName: "example", jobID: "web", status: "running", version: 3},
+		{packName: "example", jobID: "db", status: "pending", version: 1},
+	}
+
+	tbl := formatDeployedPackJobs(packJobs, jobTableOptions{
+		showVersion: true,
+		columns:     []string{"Status", "Job Name", "Version"},
+	})
+	must.Eq(t, []string{"Status", "Job Name", "Version"}, tbl.Headers)
+	must.Eq(t, [][]string{
+		{"running", "web", "3"},
+		{"pending", "db", "1"},
+	}, tbl.Rows)
+}
+
+func Test_PageJobs(t *testing.T) {
+	packJobs := []JobStatusInfo{
+		{jobID: "web", namespace: "prod"},
+		{jobID: "api", namespace: "prod"},
+		{jobID: "web", namespace: "dev"},
+	}
+	ids := func(jobs []JobStatusInfo) []string {
+		var out []string
+		for _, j := range jobs {
+			out = append(out, j.namespace+"/"+j.jobID)
+		}
+		return out
+	}
+
+	page, token, err := pageJobs(packJobs, "", 2)
+	must.NoError(t, err)
+	must.Eq(t, []string{"dev/web", "prod/api"}, ids(page))
+	must.NotEq(t, "", token)
+
+	// A job added before the position of the token does not shift the next
+	// page, unlike an offset.
+	packJobs = append(packJobs, JobStatusInfo{jobID: "db", namespace: "dev"})
+	page, next, err := pageJobs(packJobs, token, 2)
+	must.NoError(t, err)
+	must.Eq(t, []string{"prod/web"}, ids(page))
+	must.Eq(t, "", next)
+
+	// The job the token was issued for no longer exists.
+	packJobs = slices.DeleteFunc(packJobs, func(j JobStatusInfo) bool { return j.jobID == "api" })
+	page, _, err = pageJobs(packJobs, token, 0)
+	must.NoError(t, err)
+	must.Eq(t, []string{"prod/web"}, ids(page))
+
+	_, _, err = pageJobs(packJobs, "not-a-token", 2)
+	must.EqError(t, err, "invalid page token")
+}
+
+func Test_FormatHTMLReport(t *testing.T) {
+	packJobs := []JobStatusInfo{
+		{packName: "example", jobID: "web", status: "running"},
+		{packName: "example", jobID: "<script>", status: "dead"},
+	}
+	tables := []statusReportTable{{title: "Jobs", tbl: formatDeployedPackJobs(packJobs, jobTableOptions{})}}
+
+	out, err := formatHTMLReport(`Pack "example" Status`, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), tables, map[string]string{"Job Name": "Job ID"})
+	must.NoError(t, err)
+	must.StrHasPrefix(t, "<!DOCTYPE html>", out)
+	must.StrContains(t, out, "<title>Pack &#34;example&#34; Status</title>")
+	must.StrContains(t, out, "Generated at 2024-01-02T03:04:05Z")
+	must.StrContains(t, out, `<td class="status-ok">running</td>`)
+	must.StrContains(t, out, `<td class="status-failed">dead</td>`)
+	must.StrContains(t, out, "<td>&lt;script&gt;</td>")
+	must.StrNotContains(t, out, "<script>")
+	must.StrContains(t, out, "<th>Job ID</th><th>Status</th>")
+}
+
+func Test_JobHealth(t *testing.T) {
+	summary := func(complete, failed int) *api.JobSummary {
+		return &api.JobSummary{Summary: map[string]api.TaskGroupSummary{
+			"group": {Complete: complete, Failed: failed},
+		}}
+	}
+
+	testCases := []struct {
+		name    string
+		job     *api.Job
+		summary *api.JobSummary
+		exp     jobHealthState
+	}{
+		{
+			name: "running",
+			job:  &api.Job{Status: pointer.Of("running")},
+			exp:  jobHealthy,
+		},
+		{
+			name: "pending",
+			job:  &api.Job{Status: pointer.Of("pending")},
+			exp:  jobPending,
+		},
+		{
+			name: "stopped",
+			job:  &api.Job{Status: pointer.Of("dead"), Stop: pointer.Of(true)},
+			exp:  jobDead,
+		},
+		{
+			name:    "failed",
+			job:     &api.Job{Status: pointer.Of("dead"), Type: pointer.Of(api.JobTypeService)},
+			summary: summary(0, 2),
+			exp:     jobFailed,
+		},
+		{
+			name:    "completed batch",
+			job:     &api.Job{Status: pointer.Of("dead"), Type: pointer.Of(api.JobTypeBatch)},
+			summary: summary(1, 0),
+			exp:     jobHealthy,
+		},
+		{
+			name:    "dead service",
+			job:     &api.Job{Status: pointer.Of("dead"), Type: pointer.Of(api.JobTypeService)},
+			summary: summary(1, 0),
+			exp:     jobDead,
+		},
+		{
+			name: "periodic",
+			job:  &api.Job{Status: pointer.Of("dead"), Periodic: &api.PeriodicConfig{Enabled: pointer.Of(true)}},
+			exp:  jobHealthy,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			health, reason := jobHealth(tc.job, tc.summary)
+			must.Eq(t, tc.exp, health)
+			must.NotEq(t, "", reason)
+		})
+	}
+}
+
+func Test_PackHealth(t *testing.T) {
+	must.Eq(t, jobHealthy, packHealth(nil))
+	must.Eq(t, 0, packHealth(nil).exitCode())
+
+	packJobs := []JobStatusInfo{{health: jobHealthy}, {health: jobFailed}, {health: jobPending}}
+	must.Eq(t, jobFailed, packHealth(packJobs))
+	must.Eq(t, 4, packHealth(packJobs).exitCode())
+	must.Eq(t, 2, jobPending.exitCode())
+	must.Eq(t, 3, jobDead.exitCode())
+}
+
+func Test_NewPackMetaKeys(t *testing.T) {
+	must.Eq(t, packMetaKeys{
+		name:           job.PackNameKey,
+		registry:       job.PackRegistryKey,
+		deploymentName: job.PackDeploymentNameKey,
+		ref:            job.PackRefKey,
+	}, newPackMetaKeys(defaultPackMetaPrefix))
+
+	must.Eq(t, packMetaKeys{
+		name:           "nomad-pack/name",
+		registry:       "nomad-pack/registry",
+		deploymentName: "nomad-pack/deployment_name",
+		ref:            "nomad-pack/version",
+	}, newPackMetaKeys("nomad-pack/"))
+}
+
+func Test_FormatHealthSummaries(t *testing.T) {
+	packJobs := []JobStatusInfo{
+		{packName: "web", health: jobHealthy},
+		{packName: "db", health: jobFailed},
+		{packName: "web", health: jobPending},
+		{packName: "web", health: jobHealthy},
+	}
+	must.Eq(t, "db: 0/1 jobs healthy\nweb: 2/3 jobs healthy", formatHealthSummaries(packJobs))
+	must.Eq(t, "", formatHealthSummaries(nil))
+}
+
+func Test_JobPendingGC(t *testing.T) {
+	must.True(t, jobPendingGC(&api.Job{Status: pointer.Of("dead"), Stop: pointer.Of(true)}))
+	must.True(t, jobPendingGC(&api.Job{Status: pointer.Of("dead"), Type: pointer.Of("batch")}))
+	must.False(t, jobPendingGC(&api.Job{Status: pointer.Of("running"), Stop: pointer.Of(true)}))
+	must.False(t, jobPendingGC(&api.Job{Status: pointer.Of("pending")}))
+	must.False(t, jobPendingGC(&api.Job{}))
+}
+
+func Test_SortBySeverity(t *testing.T) {
+	jobs := func() []JobStatusInfo {
+		return []JobStatusInfo{
+			{jobID: "a", health: jobHealthy},
+			{jobID: "b", health: jobDead},
+			{jobID: "c", health: jobPending},
+			{jobID: "d", health: jobFailed},
+			{jobID: "e", health: jobDead},
+		}
+	}
+	ids := func(packJobs []JobStatusInfo) []string {
+		out := make([]string, 0, len(packJobs))
+		for _, info := range packJobs {
+			out = append(out, info.jobID)
+		}
+		return out
+	}
+
+	ranking, err := parseSeverityRanking("")
+	must.NoError(t, err)
+	packJobs := jobs()
+	sortBySeverity(packJobs, ranking)
+	must.Eq(t, []string{"d", "b", "e", "c", "a"}, ids(packJobs))
+
+	// Unlisted states are ranked last, in their default order.
+	ranking, err = parseSeverityRanking("pending, dead")
+	must.NoError(t, err)
+	packJobs = jobs()
+	sortBySeverity(packJobs, ranking)
+	must.Eq(t, []string{"c", "b", "e", "d", "a"}, ids(packJobs))
+
+	_, err = parseSeverityRanking("failed,broken")
+	must.ErrorContains(t, err, `unknown health state "broken"`)
+	_, err = parseSeverityRanking("failed,dead,failed")
+	must.ErrorContains(t, err, `health state "failed" is ranked more than once`)
+}
+
+func Test_ExplainJobHealth(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	alloc := func(desired, client string, healthy *bool) *api.AllocationListStub {
+		a := &api.AllocationListStub{DesiredStatus: desired, ClientStatus: client}
+		if healthy != nil {
+			a.DeploymentStatus = &api.AllocDeploymentStatus{Healthy: healthy}
+		}
+		return a
+	}
+
+	running := JobStatusInfo{jobID: "web", version: 2, status: "running", health: jobHealthy, healthReason: "job is running"}
+	allocs := []*api.AllocationListStub{
+		alloc("run", "running", pointer.Of(true)),
+		alloc("run", "running", pointer.Of(true)),
+		alloc("run", "running", pointer.Of(false)),
+		alloc("stop", "complete", pointer.Of(true)),
+	}
+	deployment := &api.Deployment{JobVersion: 2, Status: "successful", ModifyTime: now.Add(-5 * time.Minute).UnixNano()}
+	must.Eq(t, "running: 2/3 allocations healthy, last deployment succeeded 5m ago",
+		explainJobHealth(running, allocs, deployment, now))
+
+	// Without deployment health, running allocations are counted instead.
+	allocs = []*api.AllocationListStub{alloc("run", "running", nil), alloc("run", "pending", nil)}
+	must.Eq(t, "running: 1/2 allocations running", explainJobHealth(running, allocs, nil, now))
+
+	failed := JobStatusInfo{jobID: "db", version: 3, status: "dead", health: jobFailed, healthReason: "job is dead with 2 failed and 0 lost allocations"}
+	deployment = &api.Deployment{JobVersion: 2, Status: "failed", StatusDescription: "Failed due to progress deadline"}
+	must.Eq(t, "dead: job is dead with 2 failed and 0 lost allocations, last deployment of version 2 failed (Failed due to progress deadline)",
+		explainJobHealth(failed, nil, deployment, now))
+
+	// Nothing is known other than the reason for the health of the job.
+	must.Eq(t, "running: job is running", explainJobHealth(running, nil, nil, now))
+}
+
+func Test_NewStatusSummary(t *testing.T) {
+	packJobs := []JobStatusInfo{
+		{jobID: "web", namespace: "prod", packName: "example", registryName: "community", deploymentName: "dev", status: "running", health: jobHealthy, healthReason: "job is running"},
+		{jobID: "db", packName: "example", registryName: "community", deploymentName: "dev", status: "pending", health: jobPending},
+	}
+	jobErrs := []JobStatusError{{jobID: "cache", jobError: errors.New("permission denied")}}
+
+	b, err := json.Marshal(newStatusSummary("example", "dev", packJobs, jobErrs))
+	must.NoError(t, err)
+	must.Eq(t, `{"pack":"example","deployment":"dev","health":"pending","jobs":[`+
+		`{"id":"web","namespace":"prod","pack":"example","registry":"community","deployment":"dev","status":"running","health":"healthy","healthReason":"job is running"},`+
+		`{"id":"db","pack":"example","registry":"community","deployment":"dev","status":"pending","health":"pending"}],`+
+		`"errors":[{"id":"cache","error":"permission denied"}]}`, string(b))
+}
+
+func Test_FormatJSONReport(t *testing.T) {
+	out, err := formatStatusReport(statusFormatJSON, &statusReport{
+		packName: "example",
+		index:    1234,
+		packJobs: []JobStatusInfo{{jobID: "web", packName: "example", registryName: "community", deploymentName: "dev", status: "running", health: jobHealthy}},
+		jobErrs:  []JobStatusError{{jobID: "db", jobError: errors.New("permission denied")}},
+	}, time.Now())
+	must.NoError(t, err)
+	must.Eq(t, `{
+  "pack": "example",
+  "health": "healthy",
+  "jobs": [
+    {
+      "id": "web",
+      "pack": "example",
+      "registry": "community",
+      "deployment": "dev",
+      "status": "running",
+      "health": "healthy"
+    }
+  ],
+  "errors": [
+    {
+      "id": "db",
+      "error": "permission denied"
+    }
+  ],
+  "index": 1234
+}
+`, out)
+
+	// Jobs are output as an empty list when none are found, or none changed
+	// since the index.
+	out, err = formatStatusReport(statusFormatJSON, &statusReport{packName: "example", index: 1234}, time.Now())
+	must.NoError(t, err)
+	must.StrContains(t, out, `"jobs": [],`)
+}
+
+func Test_CustomMetadata(t *testing.T) {
+	md := &pack.Metadata{
+		App: &pack.MetadataApp{URL: "https://example.com"},
+		Pack: &pack.MetadataPack{
+			Name:    "example",
+			Version: "0.0.1",
+			Custom: map[string]cty.Value{
+				"maintainer": cty.StringVal("platform-team"),
+				"tags":       cty.TupleVal([]cty.Value{cty.StringVal("web"), cty.StringVal("edge")}),
+				"tier":       cty.NumberIntVal(1),
+				"owners":     cty.ObjectVal(map[string]cty.Value{"team": cty.StringVal("edge")}),
+			},
+		},
+	}
+
+	must.Eq(t, []terminal.NamedValue{
+		{Name: "maintainer", Value: "platform-team"},
+		{Name: "owners", Value: `{
+  team = "edge"
+}`},
+		{Name: "tags", Value: "web, edge"},
+		{Name: "tier", Value: "1"},
+	}, customMetadataValues(md))
+	must.SliceEmpty(t, customMetadataValues(&pack.Metadata{Pack: &pack.MetadataPack{}}))
+
+	out, err := formatInfoJSON(md, nil, nil)
+	must.NoError(t, err)
+	var decoded map[string]map[string]any
+	must.NoError(t, json.Unmarshal([]byte(out), &decoded))
+	must.Eq(t, map[string]any{
+		"name":        "example",
+		"description": "",
+		"version":     "0.0.1",
+		"maintainer":  "platform-team",
+		"tags":        []any{"web", "edge"},
+		"tier":        float64(1),
+		"owners":      map[string]any{"team": "edge"},
+	}, decoded["metadata"]["pack"].(map[string]any))
+	must.Eq(t, map[string]any{"url": "https://example.com"}, decoded["metadata"]["app"].(map[string]any))
+}
+
+func Test_FormatInfoJSON_WithStatus(t *testing.T) {
+	md := &pack.Metadata{App: &pack.MetadataApp{}, Pack: &pack.MetadataPack{Name: "example"}}
+	vars := []packInfoVariable{{Pack: "example", Name: "count", Type: "number", Default: "1"}}
+	status := newStatusSummary("example", "", []JobStatusInfo{
+		{jobID: "web", packName: "example", registryName: "community", deploymentName: "dev", status: "running", health: jobHealthy},
+	}, nil)
+
+	out, err := formatInfoJSON(md, vars, status)
+	must.NoError(t, err)
+	var decoded struct {
+		Metadata  map[string]any     `json:"metadata"`
+		Variables []packInfoVariable `json:"variables"`
+		Status    statusSummary      `json:"status"`
+	}
+	must.NoError(t, json.Unmarshal([]byte(out), &decoded))
+	must.Eq(t, "example", decoded.Metadata["pack"].(map[string]any)["name"])
+	must.Eq(t, vars, decoded.Variables)
+	must.Eq(t, *status, decoded.Status)
+
+	// The variables and status are omitted unless requested.
+	out, err = formatInfoJSON(md, nil, nil)
+	must.NoError(t, err)
+	must.StrNotContains(t, out, `"variables"`)
+	must.StrNotContains(t, out, `"status"`)
+}
+
+func Test_FormatInfoDependencies(t *testing.T) {
+	must.Nil(t, formatInfoDependencies(&pack.Metadata{}))
+
+	tbl := formatInfoDependencies(&pack.Metadata{Dependencies: []*pack.Dependency{
+		{Name: "child", Alias: "child1"},
+		{Name: "base", Source: "git::https://github.com/example/packs//base", Ref: "v1.2.0"},
+	}})
+	must.Eq(t, []string{"Name", "Alias", "Source", "Ref"}, tbl.Headers)
+	must.Eq(t, [][]string{
+		{"child", "child1", "", ""},
+		{"base", "", "git::https://github.com/example/packs//base", "v1.2.0"},
+	}, tbl.Rows)
+}
+
+func Test_FormatInfoYAML(t *testing.T) {
+	md := &pack.Metadata{
+		App:  &pack.MetadataApp{URL: "https://example.com"},
+		Pack: &pack.MetadataPack{Name: "example", Description: "An example pack."},
+		Dependencies: []*pack.Dependency{
+			{Name: "base", Alias: "shared", Source: "git::https://github.com/example/packs//base", Ref: "v1.2.0"},
+		},
+	}
+	parsedVars := new(parser.ParsedVariables)
+	must.NoError(t, parsedVars.LoadV2Result(map[pack.ID]map[variables.ID]*variables.Variable{
+		"example": {
+			"count":    {Name: "count", Type: cty.Number, Default: cty.NumberIntVal(1)},
+			"tags":     {Name: "tags", Default: cty.ListVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b")})},
+			"password": {Name: "password", Type: cty.String, Default: cty.StringVal("secret"), Sensitive: true},
+			"region":   {Name: "region", Type: cty.String, Description: " The region. "},
+		},
+	}))
+
+	out, err := formatInfoYAML(md, parsedVars)
+	must.NoError(t, err)
+
+	// The required variables are output before the optional ones, and the
+	// default of the sensitive variable is omitted.
+	must.Eq(t, `metadata:
+  name: example
+  description: An example pack.
+  url: https://example.com
+  dependencies:
+    - name: base
+      alias: shared
+      source: git::https://github.com/example/packs//base
+      ref: v1.2.0
+variables:
+  - pack: example
+    name: region
+    type: string
+    required: true
+    description: The region.
+  - pack: example
+    name: count
+    type: number
+    required: false
+    description: ""
+    default: 1
+  - pack: example
+    name: password
+    type: string
+    required: false
+    description: ""
+  - pack: example
+    name: tags
+    type: list of string
+    required: false
+    description: ""
+    default:
+      - a
+      - b
+`, out)
+}
+
+func Test_ParseSyslogAddress(t *testing.T) {
+	network, addr, err := parseSyslogAddress("")
+	must.NoError(t, err)
+	must.Eq(t, "", network)
+	must.Eq(t, "", addr)
+
+	network, addr, err = parseSyslogAddress("logs:514")
+	must.NoError(t, err)
+	must.Eq(t, "udp", network)
+	must.Eq(t, "logs:514", addr)
+
+	network, addr, err = parseSyslogAddress("tcp://logs:601")
+	must.NoError(t, err)
+	must.Eq(t, "tcp", network)
+	must.Eq(t, "logs:601", addr)
+
+	_, _, err = parseSyslogAddress("http://logs:514")
+	must.EqError(t, err, `unsupported syslog network "http", must be udp or tcp`)
+}
+
+func Test_FormatJUnitReport(t *testing.T) {
+	packJobs := []JobStatusInfo{
+		{jobID: "web", deploymentName: "dev", status: "running", health: jobHealthy},
+		{jobID: "worker", deploymentName: "dev", status: "dead", health: jobFailed, healthReason: "job is dead with 1 failed and 0 lost allocations"},
+	}
+	jobErrs := []JobStatusError{{jobID: "cache", jobError: errors.New("permission denied")}}
+
+	out, err := formatJUnitReport("example", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), packJobs, jobErrs)
+	must.NoError(t, err)
+	must.StrContains(t, out, `<testsuites tests="3" failures="1" errors="1">`)
+	must.StrContains(t, out, `<testsuite name="example" tests="3" failures="1" errors="1" timestamp="2024-01-02T03:04:05Z">`)
+	must.StrContains(t, out, `<testcase name="web" classname="example.dev"></testcase>`)
+	must.StrContains(t, out, `<failure message="dead" type="failed">job is dead with 1 failed and 0 lost allocations</failure>`)
+	must.StrContains(t, out, `<error message="permission denied" type="error"></error>`)
+}
+
+func Test_FormatInfluxReport(t *testing.T) {
+	packJobs := []JobStatusInfo{
+		{jobID: "web", registryName: "community", deploymentName: "dev", namespace: "prod", version: 3, status: "running", health: jobHealthy},
+		{jobID: "my job,1=a", registryName: "community", status: `say "hi" \`, health: jobFailed},
+	}
+	jobErrs := []JobStatusError{{jobID: "cache", jobError: errors.New(`permission "denied"`)}}
+
+	out := formatInfluxReport("example", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), packJobs, jobErrs)
+	must.Eq(t, `nomad_pack_job,pack=example,registry=community,deployment=dev,namespace=prod,job=web status="running",health="healthy",version=3i 1704164645000000000
+nomad_pack_job,pack=example,registry=community,job=my\ job\,1\=a status="say \"hi\" \\",health="failed",version=0i 1704164645000000000
+nomad_pack_job,pack=example,job=cache error="permission \"denied\"" 1704164645000000000
+`, out)
+}
+
+func Test_FailedJobLogAllocs(t *testing.T) {
+	allocs := []*api.AllocationListStub{
+		{ID: "a1", ClientStatus: api.AllocClientStatusComplete, ModifyIndex: 30},
+		{ID: "a2", ClientStatus: api.AllocClientStatusFailed, ModifyIndex: 10},
+		{ID: "a3", ClientStatus: api.AllocClientStatusFailed, ModifyIndex: 20},
+	}
+	must.Eq(t, []*api.AllocationListStub{allocs[1], allocs[2]}, failedJobLogAllocs(allocs))
+	must.Eq(t, []*api.AllocationListStub{allocs[0]}, failedJobLogAllocs(allocs[:1]))
+	must.SliceEmpty(t, failedJobLogAllocs(nil))
+}
+
+func Test_LastLines(t *testing.T) {
+	must.Eq(t, "c\nd", lastLines("a\nb\nc\nd\n", 2))
+	must.Eq(t, "a\nb", lastLines("a\nb", 5))
+	must.Eq(t, "", lastLines("", 3))
+}
+
+func Test_FormatEvent(t *testing.T) {
+	ev := &api.Event{
+		Topic: api.TopicAllocation,
+		Type:  "AllocationUpdated",
+		Key:   "5f8b3c1a-0000-0000-0000-000000000000",
+		Payload: map[string]any{
+			"Allocation": map[string]any{
+				"ID":            "5f8b3c1a-0000-0000-0000-000000000000",
+				"JobID":         "web",
+				"NodeName":      "client-1",
+				"ClientStatus":  "running",
+				"DesiredStatus": "run",
+			},
+		},
+	}
+	must.Eq(t, `Allocation AllocationUpdated job "web" allocation 5f8b3c1a on node "client-1": running (desired: run)`, formatEvent(ev))
+
+	ev = &api.Event{Topic: api.TopicNode, Type: "NodeRegistration", Key: "node-1"}
+	must.Eq(t, "Node NodeRegistration node-1", formatEvent(ev))
+}
+
+func Test_ParseHeaderMap(t *testing.T) {
+	headers, err := parseHeaderMap("job=Job ID, status = State")
+	must.NoError(t, err)
+	must.Eq(t, map[string]string{"Job Name": "Job ID", "Status": "State"}, headers)
+	must.Eq(t, []string{"Pack Name", "Job ID", "State"}, mapHeaders([]string{"Pack Name", "Job Name", "Status"}, headers))
+
+	headers, err = parseHeaderMap("")
+	must.NoError(t, err)
+	must.Nil(t, headers)
+
+	_, err = parseHeaderMap("jobs=Job ID")
+	must.ErrorContains(t, err, `unknown column key "jobs"`)
+
+	_, err = parseHeaderMap("job")
+	must.ErrorContains(t, err, "expected the form key=header")
+}
+
+func Test_ParseColumns(t *testing.T) {
+	headers := jobTableHeaders(jobTableOptions{})
+
+	columns, err := parseColumns([]string{"job", " status", "Pack name", "FAILED"}, headers)
+	must.NoError(t, err)
+	must.Eq(t, []string{"Job Name", "Status", "Pack Name", "Failed"}, columns)
+
+	// Optional columns are only valid when enabled.
+	_, err = parseColumns([]string{"version"}, headers)
+	must.ErrorContains(t, err, `unknown column "version", must be one of: Pack Name, Registry Name`)
+
+	columns, err = parseColumns([]string{"version"}, jobTableHeaders(jobTableOptions{showVersion: true}))
+	must.NoError(t, err)
+	must.Eq(t, []string{"Version"}, columns)
+
+	_, err = parseColumns([]string{"jobs"}, headers)
+	must.ErrorContains(t, err, `unknown column "jobs"`)
+}
+
+func Test_ParseStylePrefixes(t *testing.T) {
+	prefixes, err := parseStylePrefixes("header=# , error=ERROR: ,step=")
+	must.NoError(t, err)
+	must.Eq(t, terminal.StylePrefixes{Header: "# ", Error: "ERROR: "}, prefixes)
+
+	prefixes, err = parseStylePrefixes("error=E ")
+	must.NoError(t, err)
+	must.Eq(t, terminal.StylePrefixes{Header: "» ", Error: "E ", Step: "-> "}, prefixes)
+
+	prefixes, err = parseStylePrefixes("none")
+	must.NoError(t, err)
+	must.Eq(t, terminal.StylePrefixes{}, prefixes)
+
+	_, err = parseStylePrefixes("warning=W ")
+	must.ErrorContains(t, err, `unknown style prefix key "warning"`)
+
+	_, err = parseStylePrefixes("header")
+	must.ErrorContains(t, err, "expected the form key=prefix")
+}
+
+func Test_FormatDeployedVarFile(t *testing.T) {
+	out := formatDeployedVarFile(map[string]string{
+		"count":         "3",
+		"command":       `"echo ${HOME}"`,
+		"child.env":     `{"A" = "b"}`,
+		"child.enabled": "true",
+	})
+	must.Eq(t, `child.enabled = true
+child.env = {"A" = "b"}
+command = "echo $${HOME}"
+count = 3
+`, out)
+	must.Eq(t, "", formatDeployedVarFile(nil))
+}
+
+func Test_SumJobResources(t *testing.T) {
+	web := api.NewServiceJob("web", "web", "global", 50)
+	web.AddTaskGroup(
+		api.NewTaskGroup("web", 3).
+			AddTask(api.NewTask("server", "docker").Require(&api.Resources{CPU: pointer.Of(500), MemoryMB: pointer.Of(256)})).
+			AddTask(api.NewTask("sidecar", "docker")).
+			RequireDisk(&api.EphemeralDisk{SizeMB: pointer.Of(1000)}),
+	)
+
+	// The task and task group use the Nomad default resources.
+	batch := api.NewBatchJob("batch", "batch", "global", 50)
+	batch.AddTaskGroup(api.NewTaskGroup("batch", 1).AddTask(api.NewTask("run", "exec")))
+
+	must.Eq(t, jobResources{cpu: 1900, memoryMB: 1968, diskMB: 3300}, sumJobResources([]*api.Job{web, batch}))
+	must.Eq(t, jobResources{}, sumJobResources(nil))
+}
+
+func Test_SummarizeJobs(t *testing.T) {
+	web := api.NewServiceJob("web", "web", "global", 50)
+	web.AddTaskGroup(api.NewTaskGroup("web", 3).AddTask(api.NewTask("server", "docker")).AddTask(api.NewTask("sidecar", "docker")))
+	web.AddTaskGroup(api.NewTaskGroup("worker", 1).AddTask(api.NewTask("worker", "docker")))
+
+	batch := api.NewBatchJob("batch", "batch", "global", 50)
+	batch.AddTaskGroup(api.NewTaskGroup("batch", 1).AddTask(api.NewTask("run", "exec")))
+
+	must.Eq(t, jobPlanSummary{jobIDs: []string{"web", "batch"}, groups: 3, tasks: 4}, summarizeJobs([]*api.Job{web, batch}))
+	must.Eq(t, jobPlanSummary{jobIDs: []string{}}, summarizeJobs(nil))
+}
+
+func Test_FormatJobConstraints(t *testing.T) {
+	linux := &api.Constraint{LTarget: "${attr.kernel.name}", RTarget: "linux"}
+	ssd := api.NewAffinity("${meta.disk}", "=", "ssd", 75)
+
+	web := api.NewServiceJob("web", "web", "global", 50)
+	web.Constrain(linux)
+	web.AddTaskGroup(
+		api.NewTaskGroup("api", 1).
+			AddAffinity(ssd).
+			AddTask(api.NewTask("server", "docker").Constrain(api.NewConstraint("${attr.cpu.arch}", "=", "amd64"))),
+	)
+
+	batch := api.NewBatchJob("batch", "batch", "global", 50)
+	batch.Constrain(api.NewConstraint("${attr.kernel.name}", "=", "linux"))
+	batch.AddTaskGroup(api.NewTaskGroup("run", 1).AddAffinity(&api.Affinity{LTarget: "${node.class}", RTarget: "batch"}))
+
+	tbl := formatJobConstraints([]*api.Job{web, batch})
+	must.Eq(t, []string{"Kind", "Attribute", "Operator", "Value", "Weight", "Set On"}, tbl.Headers)
+	must.Eq(t, [][]string{
+		{"constraint", "${attr.cpu.arch}", "=", "amd64", "", "web.api.server"},
+		{"constraint", "${attr.kernel.name}", "=", "linux", "", "web, batch"},
+		{"affinity", "${meta.disk}", "=", "ssd", "75", "web.api"},
+		{"affinity", "${node.class}", "=", "batch", "50", "batch.run"},
+	}, tbl.Rows)
+
+	must.SliceEmpty(t, formatJobConstraints(nil).Rows)
+}
+
+func Test_FormatPackJobScaling(t *testing.T) {
+	tbl := formatPackJobScaling([]taskGroupScaling{
+		{jobID: "web", group: "api", enabled: true, min: 1, max: 5, desired: 3, running: 3},
+		{jobID: "web", group: "worker", enabled: false, min: 2, max: 4, desired: 1, running: 1},
+	})
+	must.Eq(t, []string{"Job Name", "Task Group", "Enabled", "Min", "Max", "Desired", "Running", "In Bounds"}, tbl.Headers)
+	must.Eq(t, [][]string{
+		{"web", "api", "true", "1", "5", "3", "3", "true"},
+		{"web", "worker", "false", "2", "4", "1", "1", "false"},
+	}, tbl.Rows)
+}
+
+func Test_LatestJobEvaluation(t *testing.T) {
+	_, ok := latestJobEvaluation("web", nil)
+	must.False(t, ok)
+
+	failed := map[string]*api.AllocationMetric{"api": {NodesEvaluated: 2, NodesExhausted: 2}}
+	eval, ok := latestJobEvaluation("web", []*api.Evaluation{
+		{ID: "blocked", Status: "blocked", TriggeredBy: "queued-allocs", PreviousEval: "failed", CreateIndex: 12},
+		{ID: "failed", Status: "complete", TriggeredBy: "job-register", FailedTGAllocs: failed, CreateIndex: 11},
+		{ID: "old", Status: "complete", TriggeredBy: "job-register", CreateIndex: 5},
+	})
+	must.True(t, ok)
+	must.Eq(t, "blocked", eval.evalID)
+	must.Eq(t, "blocked", eval.status)
+	must.Eq(t, failed, eval.failedTGAllocs)
+}
+
+func Test_FormatPackJobEvals(t *testing.T) {
+	tbl := formatPackJobEvals([]jobEvaluation{
+		{jobID: "db", evalID: "3c9a5e1b-0000", triggeredBy: "job-register", status: "complete"},
+		{jobID: "web", evalID: "8f2d4a6c-0000", triggeredBy: "queued-allocs", status: "blocked", failedTGAllocs: map[string]*api.AllocationMetric{
+			"worker": {NodesEvaluated: 0},
+			"api": {
+				NodesEvaluated:     3,
+				NodesAvailable:     map[string]int{"dc1": 3, "dc2": 0},
+				ConstraintFiltered: map[string]int{"${attr.kernel.name} = windows": 2},
+				NodesExhausted:     1,
+				DimensionExhausted: map[string]int{"memory": 1},
+				CoalescedFailures:  2,
+			},
+		}},
+	})
+	must.Eq(t, []string{"Job Name", "Eval ID", "Triggered By", "Eval Status", "Task Group", "Placement Failures"}, tbl.Headers)
+	must.Eq(t, [][]string{
+		{"db", "3c9a5e1b", "job-register", "complete", "", "(none)"},
+		{"web", "8f2d4a6c", "queued-allocs", "blocked", "api", `no nodes are available in datacenter "dc2"; ` +
+			`constraint "${attr.kernel.name} = windows" filtered 2 nodes; resources exhausted on 1 nodes; ` +
+			`dimension "memory" exhausted on 1 nodes; 2 additional allocations could not be placed`},
+		{"web", "8f2d4a6c", "queued-allocs", "blocked", "worker", "no nodes were eligible for evaluation"},
+	}, tbl.Rows)
+}
+
+func Test_FormatDryRunJobs(t *testing.T) {
+	existing := func(deployment string) *api.Job {
+		j := &api.Job{Status: pointer.Of("running")}
+		if deployment != "" {
+			j.Meta = map[string]string{job.PackDeploymentNameKey: deployment}
+		}
+		return j
+	}
+
+	tbl, conflicts := formatDryRunJobs([]dryRunJob{
+		{tplName: "example/templates/new.nomad.tpl", jobID: "new", namespace: "default"},
+		{tplName: "example/templates/web.nomad.tpl", jobID: "web", namespace: "default", existing: existing("example@latest")},
+		{tplName: "example/templates/db.nomad.tpl", jobID: "db", namespace: "prod", existing: existing("dev")},
+		{tplName: "example/templates/cache.nomad.tpl", jobID: "cache", namespace: "default", existing: existing("")},
+	}, "example@latest")
+	must.Eq(t, 2, conflicts)
+	must.Eq(t, []string{"Job Name", "Namespace", "Template", "Existing Status", "Result"}, tbl.Headers)
+	must.Eq(t, [][]string{
+		{"new", "default", "example/templates/new.nomad.tpl", "", "create"},
+		{"web", "default", "example/templates/web.nomad.tpl", "running", "update"},
+		{"db", "prod", "example/templates/db.nomad.tpl", "running", `conflict: part of deployment "dev"`},
+		{"cache", "default", "example/templates/cache.nomad.tpl", "running", "conflict: not managed by nomad pack"},
+	}, tbl.Rows)
+}
+
+func Test_JobFollowState(t *testing.T) {
+	newJob := func(status string, version uint64) *api.Job {
+		return &api.Job{ID: pointer.Of("web"), Type: pointer.Of(api.JobTypeService), Status: pointer.Of(status), Version: pointer.Of(version)}
+	}
+	newDeployment := func(status, desc string, version uint64) *api.Deployment {
+		return &api.Deployment{
+			ID:                "5e1d9c3a-0000",
+			JobVersion:        version,
+			Status:            status,
+			StatusDescription: desc,
+			TaskGroups: map[string]*api.DeploymentState{
+				"api":    {DesiredTotal: 3, PlacedAllocs: 2, HealthyAllocs: 1},
+				"worker": {DesiredTotal: 1, PlacedAllocs: 1, UnhealthyAllocs: 1},
+			},
+		}
+	}
+
+	testCases := []struct {
+		desc           string
+		state          jobFollowState
+		expectedString string
+		expectedDone   bool
+		expectedHealth jobHealthState
+		expectedReason string
+	}{
+		{
+			desc:           "pending without deployment",
+			state:          jobFollowState{job: newJob("pending", 0)},
+			expectedString: `job "web" version 0: pending`,
+			expectedHealth: jobPending,
+			expectedReason: "job is pending",
+		},
+		{
+			desc:           "running deployment",
+			state:          jobFollowState{job: newJob("running", 2), deployment: newDeployment("running", "Deployment is running", 2)},
+			expectedString: `job "web" version 2: running, deployment 5e1d9c3a: running (4 desired, 3 placed, 1 healthy, 1 unhealthy) - Deployment is running`,
+			expectedHealth: jobPending,
+			expectedReason: "deployment running",
+		},
+		{
+			desc:           "successful deployment",
+			state:          jobFollowState{job: newJob("running", 2), deployment: newDeployment("successful", "", 2)},
+			expectedString: `job "web" version 2: running, deployment 5e1d9c3a: successful (4 desired, 3 placed, 1 healthy, 1 unhealthy)`,
+			expectedDone:   true,
+			expectedHealth: jobHealthy,
+			expectedReason: "deployment succeeded",
+		},
+		{
+			desc:           "failed deployment",
+			state:          jobFollowState{job: newJob("running", 2), deployment: newDeployment("failed", "Failed due to progress deadline", 2)},
+			expectedString: `job "web" version 2: running, deployment 5e1d9c3a: failed (4 desired, 3 placed, 1 healthy, 1 unhealthy) - Failed due to progress deadline`,
+			expectedDone:   true,
+			expectedHealth: jobFailed,
+			expectedReason: "deployment failed: Failed due to progress deadline",
+		},
+		{
+			desc:           "deployment of previous version",
+			state:          jobFollowState{job: newJob("running", 3), deployment: newDeployment("failed", "", 2)},
+			expectedString: `job "web" version 3: running`,
+			expectedDone:   true,
+			expectedHealth: jobHealthy,
+			expectedReason: "job is running",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			must.Eq(t, tc.expectedString, tc.state.String())
+			done, health, reason := tc.state.terminal()
+			must.Eq(t, tc.expectedDone, done)
+			must.Eq(t, tc.expectedHealth, health)
+			must.Eq(t, tc.expectedReason, reason)
+		})
+	}
+}
+
+func Test_DiffDeployedVariables(t *testing.T) {
+	parsedVars := new(parser.ParsedVariables)
+	must.NoError(t, parsedVars.LoadV2Result(map[pack.ID]map[variables.ID]*variables.Variable{
+		"example": {
+			"count":       {Name: "count", Default: cty.NumberIntVal(1)},
+			"datacenters": {Name: "datacenters", Default: cty.ListVal([]cty.Value{cty.StringVal("dc1")})},
+			"image":       {Name: "image", Default: cty.NullVal(cty.String)},
+			"region":      {Name: "region", Default: cty.StringVal("global")},
+		},
+		"example.child": {
+			"port": {Name: "port", Default: cty.NumberIntVal(8080)},
+		},
+	}))
+
+	deployed := map[string]string{
+		"count":       "3",
+		"datacenters": `["dc1"]`,
+		"image":       `"redis:7"`,
+		"child.port":  "8080",
+	}
+
+	table := diffDeployedVariables(parsedVars, "example", deployed)
+	must.Eq(t, []string{"Variable", "Default", "Deployed"}, table.Headers)
+	must.Eq(t, [][]string{
+		{"count", "1", "3"},
+		{"image", "(required)", `"redis:7"`},
+		{"region", `"global"`, "(not recorded)"},
+	}, table.Rows)
+}
+
+func Test_FormatExampleRun(t *testing.T) {
+	parsedVars := new(parser.ParsedVariables)
+	must.NoError(t, parsedVars.LoadV2Result(map[pack.ID]map[variables.ID]*variables.Variable{
+		"example": {
+			"count":       {Name: "count", Default: cty.NumberIntVal(1)},
+			"datacenters": {Name: "datacenters", Type: cty.List(cty.String)},
+			"region":      {Name: "region", Type: cty.String, Default: cty.NullVal(cty.String)},
+		},
+		"example.child": {
+			"token": {Name: "token"},
+		},
+	}))
+
+	cfg := &cache.PackConfig{Name: "example", Registry: "community", Ref: "v0.1.0"}
+	must.Eq(t, `nomad-pack run example \
+  --registry=community \
+  --ref=v0.1.0 \
+  --var 'child.token=<value>' \
+  --var 'datacenters=<list of string>' \
+  --var 'region=<string>'`, formatExampleRun(cfg, parsedVars, "example"))
+
+	// Packs loaded from a directory are run by their path, and the default
+	// registry and ref are omitted.
+	cfg = &cache.PackConfig{Name: "example", SourcePath: "./packs/example", Registry: cache.DevRegistryName, Ref: cache.DevRef}
+	must.StrHasPrefix(t, "nomad-pack run ./packs/example \\\n  --var", formatExampleRun(cfg, parsedVars, "example"))
+
+	cfg = &cache.PackConfig{Name: "example", Registry: cache.DefaultRegistryName, Ref: cache.DefaultRef}
+	noRequired := new(parser.ParsedVariables)
+	must.NoError(t, noRequired.LoadV2Result(map[pack.ID]map[variables.ID]*variables.Variable{
+		"example": {"count": {Name: "count", Default: cty.NumberIntVal(1)}},
+	}))
+	must.Eq(t, "nomad-pack run example", formatExampleRun(cfg, noRequired, "example"))
+}
+
+func Test_RenderInfoDoc(t *testing.T) {
+	p := &pack.Pack{Metadata: &pack.Metadata{
+		App:  &pack.MetadataApp{URL: "https://example.com"},
+		Pack: &pack.MetadataPack{Name: "example", Description: "a pack whose description is too long for the width"},
+	}}
+	packVars := []infoPackVariables{{pack: "example", variables: []string{`- "count" (number: optional) - the number of instances`}}}
+
+	var buf bytes.Buffer
+	renderInfoDoc(&buf, 40, p, packVars)
+	lines := strings.Split(strings.TrimRight(reANSI.ReplaceAllString(buf.String(), ""), "\n"), "\n")
+
+	must.Eq(t, []string{
+		"Pack Name       example",
+		"Description     a pack whose description",
+		"                is too long for the",
+		"                width",
+		"Application URL https://example.com",
+		`Pack "example" Variables:`,
+		` - "count" (number: optional) - the`,
+		" number of instances",
+	}, lines)
+}
+
+func Test_InfoVariables_Validations(t *testing.T) {
+	parsedVars := new(parser.ParsedVariables)
+	must.NoError(t, parsedVars.LoadV2Result(map[pack.ID]map[variables.ID]*variables.Variable{
+		"example": {
+			"port": {Name: "port", Type: cty.Number, Validations: []*variables.Validation{
+				{ConditionSource: "var.port > 0", ErrorMessage: "port must be positive"},
+				{ErrorMessage: "port must be free"},
+			}},
+		},
+	}))
+
+	packVars := infoVariables(parsedVars, false, "")
+	must.Eq(t, []string{`- "port" (number: required) - 
+  Validation: var.port > 0 - port must be positive
+  Validation: port must be free`}, packVars[0].variables)
+
+	// The validation lines are indented along with the variable.
+	p := &pack.Pack{Metadata: &pack.Metadata{
+		App:  &pack.MetadataApp{},
+		Pack: &pack.MetadataPack{Name: "example"},
+	}}
+	must.StrContains(t, formatInfoPlain(p, packVars), `  - "port" (number: required) - 
+    Validation: var.port > 0 - port must be positive
+    Validation: port must be free`)
+}
+
+func Test_InfoVariables_Group(t *testing.T) {
+	parsedVars := new(parser.ParsedVariables)
+	must.NoError(t, parsedVars.LoadV2Result(map[pack.ID]map[variables.ID]*variables.Variable{
+		"example": {
+			"count":     {Name: "count", Type: cty.Number},
+			"http_port": {Name: "http_port", Type: cty.Number, Group: "networking", Default: cty.NumberIntVal(80)},
+			"dns":       {Name: "dns", Type: cty.String, Group: "networking"},
+			"image":     {Name: "image", Type: cty.String, Group: "app"},
+		},
+	}))
+
+	t.Run("sections by group", func(t *testing.T) {
+		packVars := infoVariables(parsedVars, false, "")
+		must.Len(t, 1, packVars)
+		must.Eq(t, []string{`- "count" (number: required) - `}, packVars[0].variables)
+		must.Eq(t, []infoVariableGroup{
+			{name: "app", variables: []string{`- "image" (string: required) - `}},
+			{name: "networking", variables: []string{
+				`- "dns" (string: required) - `,
+				`- "http_port" (number: optional) - `,
+			}},
+		}, packVars[0].groups)
+
+		p := &pack.Pack{Metadata: &pack.Metadata{
+			App:  &pack.MetadataApp{},
+			Pack: &pack.MetadataPack{Name: "example"},
+		}}
+		must.StrContains(t, formatInfoPlain(p, packVars), `  Group "networking":
+    - "dns" (string: required) - 
+    - "http_port" (number: optional) - `)
+	})
+
+	t.Run("filters by group", func(t *testing.T) {
+		packVars := infoVariables(parsedVars, false, "networking")
+		must.Len(t, 1, packVars)
+		must.Eq(t, []string{
+			`- "dns" (string: required) - `,
+			`- "http_port" (number: optional) - `,
+		}, packVars[0].variables)
+		must.SliceEmpty(t, packVars[0].groups)
+	})
+}
+
+func Test_FormatVariableUsage(t *testing.T) {
+	parsedVars := new(parser.ParsedVariables)
+	must.NoError(t, parsedVars.LoadV2Result(map[pack.ID]map[variables.ID]*variables.Variable{
+		"example": {
+			"count": {Name: "count"},
+			"image": {Name: "image"},
+		},
+		"example.child": {
+			"port": {Name: "port"},
+		},
+	}))
+
+	p := &pack.Pack{
+		Metadata:      &pack.Metadata{Pack: &pack.MetadataPack{Name: "example"}},
+		TemplateFiles: []*pack.File{{Name: "a.nomad.tpl", Content: []byte(`[[ var "count" . ]][[ vars .child ]]`)}},
+	}
+	usage, err := renderer.ScanVariableUsage(p)
+	must.NoError(t, err)
+
+	tbl, unused := formatVariableUsage(parsedVars, usage)
+	must.Eq(t, 1, unused)
+	must.Eq(t, []string{"example", "count", "used"}, tbl.Rows[0])
+	must.Eq(t, []string{"example", "image"}, tbl.Rows[1][:2])
+	must.StrContains(t, tbl.Rows[1][2], "unused")
+	must.Eq(t, []string{"example.child", "port", "unknown, variables accessed dynamically"}, tbl.Rows[2])
+}
+
+func Test_FormatDefaultValidations(t *testing.T) {
+	file, diags := hclsyntax.ParseConfig([]byte(`
+variable "count" {
+  default = 0
+  validation {
+    condition     = var.count > 0
+    error_message = "count must be positive"
+  }
+}
+variable "region" {
+  default = "eu"
+  validation {
+    condition     = contains(["eu", "us"], var.region)
+    error_message = "region must be eu or us"
+  }
+}
+variable "image" {
+  validation {
+    condition     = var.image != ""
+    error_message = "image must be set"
+  }
+}
+variable "name" {
+  default = ""
+}
+`), "variables.hcl", hcl.InitialPos)
+	must.SliceEmpty(t, diags)
+	content, diags := file.Body.Content(schema.VariableFileSchema)
+	must.SliceEmpty(t, diags)
+
+	vars := make(map[variables.ID]*variables.Variable)
+	for _, block := range content.Blocks {
+		v, diags := decoder.DecodeVariableBlock(block)
+		must.SliceEmpty(t, diags)
+		vars[v.Name] = v
+	}
+	parsedVars := new(parser.ParsedVariables)
+	must.NoError(t, parsedVars.LoadV2Result(map[pack.ID]map[variables.ID]*variables.Variable{"example": vars}))
+
+	// Only the variables with both a default and validations are checked.
+	tbl, checked := formatDefaultValidations(parsedVars)
+	must.Eq(t, 2, checked)
+	must.Eq(t, [][]string{{
+		"example", "count", "variables.hcl:4",
+		`The value of the variable "count" is invalid: count must be positive`,
+	}}, tbl.Rows)
+}
+
+func Test_FormatDependencyGraph(t *testing.T) {
+	newPack := func(name string, deps ...*pack.Dependency) *pack.Pack {
+		return &pack.Pack{Metadata: &pack.Metadata{
+			Pack:         &pack.MetadataPack{Name: name},
+			Dependencies: deps,
+		}}
+	}
+
+	child := newPack("child", &pack.Dependency{Name: "grandchild", Ref: "v0.1.0"})
+	child.AddDependency("grandchild", newPack("grandchild"))
+
+	parent := newPack("parent", &pack.Dependency{Name: "child", Alias: "first"})
+	parent.AddDependency("first", child)
+
+	must.Eq(t, `digraph "parent" {
+  "parent" [label="parent"];
+  "parent" -> "parent.first" [label="latest"];
+  "parent.first" [label="first (child)"];
+  "parent.first" -> "parent.first.grandchild" [label="v0.1.0"];
+  "parent.first.grandchild" [label="grandchild"];
+}`, formatDependencyGraph(parent))
+}
+
+func Test_DeployedPacksSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	packs := map[string]map[string]struct{}{
+		"web": {"default": {}, "community": {}},
+		"db":  {"default": {}},
+	}
+
+	s := newDeployedPacksSnapshot("http://127.0.0.1:4646", "prod", packs, now)
+	must.Eq(t, []string{"community", "default"}, s.Packs["web"])
+	must.NoError(t, writeDeployedPacksSnapshot(dir, s))
+
+	read, err := readDeployedPacksSnapshot(dir, "http://127.0.0.1:4646", "prod")
+	must.NoError(t, err)
+	must.Eq(t, now, read.Time)
+	must.Eq(t, packs, read.packRegistryMap())
+
+	// Snapshots are kept separately for each cluster and namespace.
+	_, err = readDeployedPacksSnapshot(dir, "http://127.0.0.1:4646", "default")
+	must.ErrorIs(t, err, os.ErrNotExist)
+	_, err = readDeployedPacksSnapshot(dir, "http://10.0.0.1:4646", "prod")
+	must.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func Test_FormatStatusTree(t *testing.T) {
+	packJobs := []JobStatusInfo{
+		{packName: "web", registryName: "default", deploymentName: "prod", jobID: "web-b", status: "running"},
+		{packName: "web", registryName: "default", deploymentName: "prod", jobID: "web-a", status: "pending"},
+		{packName: "web", registryName: "default", deploymentName: "dev", jobID: "web-dev", status: "dead"},
+		{packName: "db", registryName: "default", jobID: "db", status: "running"},
+		{packName: "cache", registryName: "community", deploymentName: "cache", jobID: "redis", status: "running"},
+	}
+	tree := buildDeployedPacksTree(packJobs)
+
+	must.Eq(t, `registry community
+`+"`"+`-- pack cache
+    `+"`"+`-- deployment cache
+        `+"`"+`-- redis: running
+registry default
+|-- pack db
+|   `+"`"+`-- deployment (none)
+|       `+"`"+`-- db: running
+`+"`"+`-- pack web
+    |-- deployment dev
+    |   `+"`"+`-- web-dev: dead
+    `+"`"+`-- deployment prod
+        |-- web-a: pending
+        `+"`"+`-- web-b: running`, formatStatusTree(tree, asciiTreeGlyphs))
+
+	must.StrContains(t, formatStatusTree(tree, unicodeTreeGlyphs), `registry default
+├── pack db
+│   └── deployment (none)
+│       └── db: running
+└── pack web`)
+}
+
+func Test_ParseCSVDelimiter(t *testing.T) {
+	for in, want := range map[string]rune{",": ',', ";": ';', `\t`: '\t', "|": '|'} {
+		got, err := parseCSVDelimiter(in)
+		must.NoError(t, err)
+		must.Eq(t, want, got)
+	}
+
+	for _, in := range []string{"", ";;", `"`, `\n`} {
+		_, err := parseCSVDelimiter(in)
+		must.Error(t, err)
+	}
+}
+
+func Test_FormatVarFile(t *testing.T) {
+	parsedVars := new(parser.ParsedVariables)
+	must.NoError(t, parsedVars.LoadV2Result(map[pack.ID]map[variables.ID]*variables.Variable{
+		"example": {
+			"command":  {Name: "command", Value: cty.StringVal("echo ${HOME}")},
+			"count":    {Name: "count", Value: cty.NumberIntVal(3)},
+			"image":    {Name: "image", Value: cty.NullVal(cty.String)},
+			"password": {Name: "password", Value: cty.StringVal("hunter2"), Sensitive: true},
+		},
+		"example.child": {
+			"ports": {Name: "ports", Value: cty.ListVal([]cty.Value{cty.NumberIntVal(80)})},
+		},
+	}))
+	vars := dumpedVariables(parsedVars, "example")
+
+	must.Eq(t, `command = "echo $${HOME}"
+count = 3
+# image is not set
+# password is sensitive and has been redacted
+child.ports = [80]
+`, formatVarFileHCL(vars, false))
+	must.StrContains(t, formatVarFileHCL(vars, true), "password = \"hunter2\"\n")
+
+	out, err := formatVarFileJSON(vars, false)
+	must.NoError(t, err)
+	must.Eq(t, `{
+  "child.ports": [
+    80
+  ],
+  "command": "echo ${HOME}",
+  "count": 3
+}
+`, out)
+
+	out, err = formatVarFileJSON(vars, true)
+	must.NoError(t, err)
+	must.StrContains(t, out, `"password": "hunter2"`)
+}
@@ -128,10 +128,14 @@ func (c *generateVarFileCommand) Run(args []string) int {
 		return 1
 	}
 
+	// Output any errors held back by --group-errors once the run completes.
+	defer c.flushErrors()
+
 	c.packConfig.Name = c.args[0]
 
 	// Set the packConfig defaults if necessary and generate our UI error context.
 	errorContext := initPackCommand(c.packConfig)
+	defer c.packConfig.Cleanup()
 
 	if err := cache.VerifyPackExists(c.packConfig, errorContext, c.ui); err != nil {
 		return 1
@@ -0,0 +1,348 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+
+	"github.com/hashicorp/nomad-pack/internal/pkg/helper/pointer"
+)
+
+// jobHealthState categorizes the health of a deployed job. The states are
+// ordered by increasing severity.
+type jobHealthState int
+
+const (
+	// jobHealthy is a job which is running, has completed successfully, or
+	// launches child jobs which are reported separately.
+	jobHealthy jobHealthState = iota
+
+	// jobPending is a job which has not yet placed its allocations.
+	jobPending
+
+	// jobDead is a job which is no longer running, such as one which has
+	// been stopped.
+	jobDead
+
+	// jobFailed is a job which is no longer running because its allocations
+	// failed or were lost.
+	jobFailed
+)
+
+func (h jobHealthState) String() string {
+	switch h {
+	case jobHealthy:
+		return "healthy"
+	case jobPending:
+		return "pending"
+	case jobDead:
+		return "dead"
+	case jobFailed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
+// exitCode returns the exit code reporting the health state. Healthy jobs exit
+// with 0, and the other states with a code above 1, increasing with their
+// severity, so they are distinct from the exit code of an error.
+func (h jobHealthState) exitCode() int {
+	if h == jobHealthy {
+		return 0
+	}
+	return int(h) + 1
+}
+
+// jobHealthStates are the health states, ordered by increasing severity.
+var jobHealthStates = []jobHealthState{jobHealthy, jobPending, jobDead, jobFailed}
+
+// severityRanking ranks the health states for triage, with the lowest rank
+// being the most severe. It orders jobs when sorting by severity, so that
+// problems are output first.
+type severityRanking map[jobHealthState]int
+
+// defaultSeverityRanking ranks the health states by their severity, from
+// failed to healthy.
+var defaultSeverityRanking = severityRanking{jobFailed: 0, jobDead: 1, jobPending: 2, jobHealthy: 3}
+
+// parseSeverityRanking parses a comma separated list of health states, ordered
+// from the most to the least severe, such as "dead,failed,pending,healthy".
+// States which are not listed rank after those which are, in their default
+// order. An empty list returns the default ranking.
+func parseSeverityRanking(in string) (severityRanking, error) {
+	if in == "" {
+		return defaultSeverityRanking, nil
+	}
+
+	names := make(map[string]jobHealthState, len(jobHealthStates))
+	for _, h := range jobHealthStates {
+		names[h.String()] = h
+	}
+
+	out := make(severityRanking, len(jobHealthStates))
+	for _, name := range strings.Split(in, ",") {
+		name = strings.TrimSpace(name)
+		h, ok := names[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown health state %q, must be one of: failed, dead, pending, healthy", name)
+		}
+		if _, ok := out[h]; ok {
+			return nil, fmt.Errorf("health state %q is ranked more than once", name)
+		}
+		out[h] = len(out)
+	}
+
+	unranked := slices.DeleteFunc(slices.Clone(jobHealthStates), func(h jobHealthState) bool {
+		_, ok := out[h]
+		return ok
+	})
+	slices.SortFunc(unranked, func(a, b jobHealthState) int {
+		return cmp.Compare(defaultSeverityRanking[a], defaultSeverityRanking[b])
+	})
+	for _, h := range unranked {
+		out[h] = len(out)
+	}
+	return out, nil
+}
+
+// sortBySeverity orders the jobs by the rank of their health, with jobs of
+// the same health ordered by namespace and ID.
+func sortBySeverity(packJobs []JobStatusInfo, ranking severityRanking) {
+	slices.SortStableFunc(packJobs, func(a, b JobStatusInfo) int {
+		return cmp.Or(
+			cmp.Compare(ranking[a.health], ranking[b.health]),
+			cmp.Compare(a.namespace, b.namespace),
+			cmp.Compare(a.jobID, b.jobID),
+		)
+	})
+}
+
+// packHealth returns the most severe health state of the jobs.
+func packHealth(packJobs []JobStatusInfo) jobHealthState {
+	health := jobHealthy
+	for _, info := range packJobs {
+		health = max(health, info.health)
+	}
+	return health
+}
+
+// formatHealthSummaries returns a line for each pack, ordered by name, with the
+// number of its jobs which are healthy, such as "example: 8/10 jobs healthy".
+func formatHealthSummaries(packJobs []JobStatusInfo) string {
+	total := make(map[string]int)
+	healthy := make(map[string]int)
+	for _, info := range packJobs {
+		total[info.packName]++
+		if info.health == jobHealthy {
+			healthy[info.packName]++
+		}
+	}
+
+	var b strings.Builder
+	for _, name := range slices.Sorted(maps.Keys(total)) {
+		fmt.Fprintf(&b, "%s: %d/%d jobs healthy\n", name, healthy[name], total[name])
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// jobHealth categorizes the health of the job from its status and the summary
+// of its allocations. The returned reason describes why the job is in the
+// state, for inclusion in output.
+func jobHealth(nomadJob *api.Job, summary *api.JobSummary) (jobHealthState, string) {
+	if nomadJob.Stop != nil && *nomadJob.Stop {
+		return jobDead, "job has been stopped"
+	}
+
+	// Periodic and parameterized jobs never run themselves, their child jobs
+	// are responsible for the work.
+	if nomadJob.IsPeriodic() || nomadJob.IsParameterized() {
+		return jobHealthy, "job launches child jobs"
+	}
+
+	var complete, failed, lost int
+	if summary != nil {
+		for _, tg := range summary.Summary {
+			complete += tg.Complete
+			failed += tg.Failed
+			lost += tg.Lost
+		}
+	}
+
+	status := ""
+	if nomadJob.Status != nil {
+		status = *nomadJob.Status
+	}
+
+	switch status {
+	case "running":
+		return jobHealthy, "job is running"
+	case "pending":
+		return jobPending, "job is pending"
+	case "dead":
+		if failed+lost > 0 {
+			return jobFailed, fmt.Sprintf("job is dead with %d failed and %d lost allocations", failed, lost)
+		}
+		if complete > 0 && nomadJob.Type != nil &&
+			(*nomadJob.Type == api.JobTypeBatch || *nomadJob.Type == api.JobTypeSysbatch) {
+			return jobHealthy, "job has completed"
+		}
+		return jobDead, "job is dead"
+	default:
+		return jobPending, fmt.Sprintf("job status is %q", status)
+	}
+}
+
+// jobPendingGC returns whether the job is eligible for garbage collection,
+// which is once it is dead, whether it was stopped or its allocations have all
+// finished. A stopped job is not eligible until its allocations have stopped.
+func jobPendingGC(nomadJob *api.Job) bool {
+	return pointer.Value(nomadJob.Status) == "dead"
+}
+
+// jobFollowState is a snapshot of a job followed using status --follow, along
+// with its latest deployment.
+type jobFollowState struct {
+	job     *api.Job
+	summary *api.JobSummary
+
+	// deployment is the latest deployment of the job, or nil if the job has
+	// never been deployed, such as a job without an update block.
+	deployment *api.Deployment
+}
+
+// currentDeployment returns the latest deployment when it is for the current
+// version of the job. An older deployment does not describe the progress of
+// the current version.
+func (s jobFollowState) currentDeployment() *api.Deployment {
+	if s.deployment == nil || s.deployment.JobVersion != pointer.Value(s.job.Version) {
+		return nil
+	}
+	return s.deployment
+}
+
+// String describes the state, so that a line is output each time it changes.
+func (s jobFollowState) String() string {
+	out := fmt.Sprintf("job %q version %d: %s", pointer.Value(s.job.ID), pointer.Value(s.job.Version), pointer.Value(s.job.Status))
+
+	d := s.currentDeployment()
+	if d == nil {
+		return out
+	}
+
+	var desired, placed, healthy, unhealthy int
+	for _, tg := range d.TaskGroups {
+		desired += tg.DesiredTotal
+		placed += tg.PlacedAllocs
+		healthy += tg.HealthyAllocs
+		unhealthy += tg.UnhealthyAllocs
+	}
+	out += fmt.Sprintf(", deployment %s: %s (%d desired, %d placed, %d healthy, %d unhealthy)",
+		shortID(d.ID), d.Status, desired, placed, healthy, unhealthy)
+	if d.StatusDescription != "" {
+		out += " - " + d.StatusDescription
+	}
+	return out
+}
+
+// terminal returns whether the followed job has reached a terminal state,
+// along with its health and the reason for it. The deployment of the current
+// version of the job determines the state when there is one, otherwise the
+// status of the job is used.
+func (s jobFollowState) terminal() (bool, jobHealthState, string) {
+	if d := s.currentDeployment(); d != nil {
+		switch d.Status {
+		case api.DeploymentStatusSuccessful:
+			return true, jobHealthy, "deployment succeeded"
+		case api.DeploymentStatusFailed, api.DeploymentStatusCancelled:
+			reason := "deployment " + d.Status
+			if d.StatusDescription != "" {
+				reason += ": " + d.StatusDescription
+			}
+			return true, jobFailed, reason
+		default:
+			return false, jobPending, "deployment " + d.Status
+		}
+	}
+
+	health, reason := jobHealth(s.job, s.summary)
+	return health != jobPending, health, reason
+}
+
+// explainJobHealth describes why the job has its status, such as "running:
+// 3/3 allocations healthy, last deployment succeeded 5m ago", from its
+// allocations and latest deployment. Either may be nil when unavailable, in
+// which case the description omits it, falling back to the reason for the
+// health of the job when nothing else is known.
+func explainJobHealth(info JobStatusInfo, allocs []*api.AllocationListStub, deployment *api.Deployment, now time.Time) string {
+	var details []string
+	if info.health != jobHealthy {
+		details = append(details, info.healthReason)
+	}
+
+	var desired, running, healthy, checked int
+	for _, alloc := range allocs {
+		if alloc.DesiredStatus != api.AllocDesiredStatusRun {
+			continue
+		}
+		desired++
+		if alloc.ClientStatus == api.AllocClientStatusRunning {
+			running++
+		}
+		if alloc.DeploymentStatus != nil && alloc.DeploymentStatus.Healthy != nil {
+			checked++
+			if *alloc.DeploymentStatus.Healthy {
+				healthy++
+			}
+		}
+	}
+	switch {
+	case desired == 0:
+	case checked > 0:
+		// Allocations only have a health when they are part of a deployment,
+		// otherwise being running is the best indication available.
+		details = append(details, fmt.Sprintf("%d/%d allocations healthy", healthy, desired))
+	default:
+		details = append(details, fmt.Sprintf("%d/%d allocations running", running, desired))
+	}
+
+	if deployment != nil {
+		detail := "last deployment"
+		if deployment.JobVersion != info.version {
+			detail += fmt.Sprintf(" of version %d", deployment.JobVersion)
+		}
+		switch deployment.Status {
+		case api.DeploymentStatusSuccessful:
+			detail += " succeeded"
+		case api.DeploymentStatusFailed:
+			detail += " failed"
+		case api.DeploymentStatusCancelled:
+			detail += " was cancelled"
+		default:
+			detail += " is " + deployment.Status
+		}
+		if deployment.ModifyTime > 0 {
+			detail += " " + formatTimeDifference(time.Unix(0, deployment.ModifyTime), now, time.Second) + " ago"
+		}
+		// The description of a deployment in progress only repeats its status,
+		// whereas that of a failed deployment explains the failure.
+		if (deployment.Status == api.DeploymentStatusFailed || deployment.Status == api.DeploymentStatusCancelled) &&
+			deployment.StatusDescription != "" {
+			detail += " (" + deployment.StatusDescription + ")"
+		}
+		details = append(details, detail)
+	}
+
+	if len(details) == 0 {
+		details = append(details, info.healthReason)
+	}
+	return info.status + ": " + strings.Join(details, ", ")
+}
@@ -4,19 +4,38 @@
 package cli
 
 import (
+	"cmp"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"io/fs"
+	"maps"
+	"net/url"
 	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/nomad/api"
+	"github.com/zclconf/go-cty/cty"
 
+	"github.com/hashicorp/nomad-pack/internal/config"
 	"github.com/hashicorp/nomad-pack/internal/pkg/cache"
 	"github.com/hashicorp/nomad-pack/internal/pkg/errors"
+	"github.com/hashicorp/nomad-pack/internal/pkg/flag"
+	"github.com/hashicorp/nomad-pack/internal/pkg/helper/pointer"
+	"github.com/hashicorp/nomad-pack/internal/pkg/loader"
 	"github.com/hashicorp/nomad-pack/internal/pkg/manager"
 	"github.com/hashicorp/nomad-pack/internal/pkg/renderer"
 	"github.com/hashicorp/nomad-pack/internal/pkg/variable/parser"
 	"github.com/hashicorp/nomad-pack/internal/runner"
 	"github.com/hashicorp/nomad-pack/internal/runner/job"
+	"github.com/hashicorp/nomad-pack/sdk/pack/variables"
 	"github.com/hashicorp/nomad-pack/terminal"
 )
 
@@ -35,6 +54,142 @@ func initPackCommand(cfg *cache.PackConfig) (errorContext *errors.UIErrorContext
 	return
 }
 
+// fetchEphemeralPack adds the pack to a temporary cache when the --no-cache
+// flag is set, so the global cache is neither read nor modified. When fetching,
+// the pack config registry is expected to be the registry source. The returned
+// func removes the temporary cache and should be deferred by the caller.
+func fetchEphemeralPack(c *baseCommand, cfg *cache.PackConfig) (func(), error) {
+	noop := func() {}
+	if !c.noCache {
+		return noop, nil
+	}
+
+	// Packs loaded from a local directory are used in place.
+	if _, err := os.Stat(cfg.Name); err == nil {
+		return noop, nil
+	}
+
+	if cfg.Registry == "" {
+		return noop, errors.New("--registry must be set to the registry source when using --no-cache")
+	}
+
+	tmpDir, err := os.MkdirTemp("", "nomad-pack-")
+	if err != nil {
+		return noop, err
+	}
+	cleanup := func() { _ = os.RemoveAll(tmpDir) }
+
+	tmpCache, err := cache.NewCache(&cache.CacheConfig{
+		Path:   tmpDir,
+		Logger: c.ui,
+	})
+	if err != nil {
+		cleanup()
+		return noop, err
+	}
+
+	source := cfg.Registry
+	cfg.Registry = ephemeralRegistryName(source)
+	cfg.CachePath = tmpDir
+
+	if _, err := tmpCache.Add(c.Ctx, &cache.AddOpts{
+		RegistryName: cfg.Registry,
+		Source:       source,
+		PackName:     cfg.Name,
+		Ref:          cfg.Ref,
+	}); err != nil {
+		cleanup()
+		return noop, err
+	}
+
+	return cleanup, nil
+}
+
+// ephemeralRegistryName returns the name of the registry used for packs
+// fetched with --no-cache, which is the final element of the registry source.
+func ephemeralRegistryName(source string) string {
+	name := path.Base(strings.TrimSuffix(source, "/"))
+	for _, ext := range []string{".git", ".tar.gz", ".tgz"} {
+		name = strings.TrimSuffix(name, ext)
+	}
+	return name
+}
+
+// noCacheFlag adds the --no-cache flag to the passed flag set.
+func noCacheFlag(f *flag.Set, target *bool) {
+	f.BoolVar(&flag.BoolVar{
+		Name:    "no-cache",
+		Target:  target,
+		Default: false,
+		Usage: `Fetch the pack into a temporary directory which is removed once
+				the command completes, without reading or modifying the global
+				cache. The --registry flag must be set to the registry source,
+				such as a git URL, rather than the name of a cached registry.`,
+	})
+}
+
+// promptVariable prompts for the value of a required variable which has not
+// been set, until a value valid for the type of the variable is entered.
+func (c *baseCommand) promptVariable(name string, v *variables.Variable) (cty.Value, error) {
+	prompt := fmt.Sprintf("Enter a value for required variable %q", name)
+	if v.Type != cty.NilType {
+		prompt += fmt.Sprintf(" (%s)", v.Type.FriendlyName())
+	}
+	if v.Description != "" {
+		prompt += " - " + v.Description
+	}
+
+	for {
+		raw, err := c.ui.Input(&terminal.Input{
+			Prompt: prompt + ":",
+			Style:  terminal.InfoStyle,
+		})
+		if err != nil {
+			return cty.NilVal, err
+		}
+
+		val, diags := parser.ParseVariableValue(v, raw)
+		if !diags.HasErrors() {
+			return val, nil
+		}
+		for _, diag := range diags {
+			if diag.Severity == hcl.DiagError {
+				c.ui.Output(diag.Detail, terminal.WithStyle(terminal.ErrorBoldStyle))
+			}
+		}
+	}
+}
+
+// profileFilePath returns the path to the file defining the variable profiles,
+// which is the file passed using --profile-file, or the profiles file at the
+// root of the pack.
+func profileFilePath(c *baseCommand, packPath string) string {
+	if c.profileFile != "" {
+		return c.profileFile
+	}
+	return filepath.Join(packPath, config.FileNameProfiles)
+}
+
+// outputLoadError outputs an error loading a pack along with its error
+// context. When the pack structure is invalid, the missing or invalid elements
+// of the pack are output as the details of the error, along with suggestions
+// to fix them, rather than as a single line.
+func outputLoadError(ui terminal.UI, err error, sub string, errorContext *errors.UIErrorContext) {
+	var structErr *loader.StructureError
+	if !errors.As(err, &structErr) {
+		ui.ErrorWithContext(err, sub, errorContext.GetAll()...)
+		return
+	}
+
+	errCtx := errors.NewUIErrorContext()
+	errCtx.Add(errors.UIContextErrorDetail, strings.Join(structErr.Problems, "; "))
+	if len(structErr.Suggestions) > 0 {
+		errCtx.Add(errors.UIContextErrorSuggestion, strings.Join(structErr.Suggestions, "; "))
+	}
+	errCtx.Append(errorContext)
+	ui.ErrorWithContext(loader.ErrInvalidStructure, sub, errCtx.GetAll()...)
+}
+
 // generatePackManager is used to generate the pack manager for this Nomad Pack run.
 func generatePackManager(c *baseCommand, client *api.Client, packCfg *cache.PackConfig) *manager.PackManager {
 	// TODO: Refactor to have manager use cache.
@@ -42,9 +197,25 @@ func generatePackManager(c *baseCommand, client *api.Client, packCfg *cache.Pack
 		Path:            packCfg.Path,
 		VariableFiles:   c.varFiles,
 		VariableCLIArgs: c.vars,
+		VariablePatches: c.varPatches,
 		VariableEnvVars: c.envVars,
 		AllowUnsetVars:  c.allowUnsetVars,
 		UseParserV1:     c.useParserV1,
+		Profile:         c.profile,
+		ProfileFile:     profileFilePath(c, packCfg.Path),
+
+		MaxVariableFileSize: c.maxVarFileSize,
+		ResolveVariableRefs: c.resolveVarRefs,
+
+		AllowExternalSymlinks: c.allowExternalSymlinks,
+
+		Warning: c.ui.Warning,
+	}
+	if c.ui.Interactive() && !c.ignoreMissingVars {
+		cfg.PromptVariable = c.promptVariable
+	}
+	if !c.noParseCache && !c.noCache {
+		cfg.ParseCacheDir = cache.DefaultParseCachePath()
 	}
 	return manager.NewPackManager(&cfg, client)
 }
@@ -217,6 +388,12 @@ func getPackJobsByDeploy(c *api.Client, cfg *cache.PackConfig, deploymentName st
 	var packJobs []*api.Job
 	hasOtherDeploys := false
 	for _, jobStub := range jobs {
+		// Jobs launched by periodic and parameterized jobs inherit the pack
+		// metadata of their parent, but are reported as part of its status.
+		if jobStub.ParentID != "" {
+			continue
+		}
+
 		nomadJob, _, err := jobsApi.Info(jobStub.ID, &api.QueryOptions{})
 		if err != nil {
 			return nil, fmt.Errorf("error retrieving job %s for pack %s: %s", *nomadJob.ID, cfg.Name, err)
@@ -289,11 +466,11 @@ func generateRunner(client *api.Client, packType, cliCfg any, runnerCfg *runner.
 // TODO: Not all commands use vars or varFiles. These fields should be abstracted
 // away from the baseCommand and then this function can get moved where appropriate.
 func hasVarOverrides(c *baseCommand) bool {
-	return len(c.varFiles) > 0 || len(c.vars) > 0
+	return len(c.varFiles) > 0 || len(c.vars) > 0 || len(c.varPatches) > 0 || c.profile != ""
 }
 
 // TODO: Move to a domain specific package.
-func getDeployedPacks(c *api.Client) (map[string]map[string]struct{}, error) {
+func getDeployedPacks(c *api.Client, keys packMetaKeys) (map[string]map[string]struct{}, error) {
 	jobsApi := c.Jobs()
 	jobs, _, err := jobsApi.List(&api.QueryOptions{})
 	if err != nil {
@@ -302,16 +479,22 @@ func getDeployedPacks(c *api.Client) (map[string]map[string]struct{}, error) {
 
 	packRegistryMap := map[string]map[string]struct{}{}
 	for _, jobStub := range jobs {
-		nomadJob, _, err := jobsApi.Info(jobStub.ID, &api.QueryOptions{})
+		// Jobs launched by periodic and parameterized jobs inherit the pack
+		// metadata of their parent, but are reported as part of its status.
+		if jobStub.ParentID != "" {
+			continue
+		}
+
+		nomadJob, _, err := jobsApi.Info(jobStub.ID, &api.QueryOptions{Namespace: jobStub.Namespace})
 		if err != nil {
-			return nil, fmt.Errorf("error retrieving job %s: %s", *nomadJob.ID, err)
+			return nil, fmt.Errorf("error retrieving job %s: %s", jobStub.ID, err)
 		}
 
 		if nomadJob.Meta != nil {
 			jobMeta := nomadJob.Meta
 			// Check metadata for pack info
-			packName, packNameOk := jobMeta[job.PackNameKey]
-			packRegistry, registryNameOk := jobMeta[job.PackRegistryKey]
+			packName, packNameOk := jobMeta[keys.name]
+			packRegistry, registryNameOk := jobMeta[keys.registry]
 			if packNameOk && registryNameOk {
 				// Build a map of packs and their registries
 				registryMap, deployedPackOk := packRegistryMap[packName]
@@ -331,6 +514,31 @@ func getDeployedPacks(c *api.Client) (map[string]map[string]struct{}, error) {
 	return packRegistryMap, nil
 }
 
+// defaultPackMetaPrefix is the prefix of the metadata keys Nomad Pack sets on
+// the jobs it deploys.
+const defaultPackMetaPrefix = "pack."
+
+// packMetaKeys are the metadata keys of a deployed job which identify the pack
+// it was deployed from.
+type packMetaKeys struct {
+	name           string
+	registry       string
+	deploymentName string
+	ref            string
+}
+
+// newPackMetaKeys returns the pack metadata keys using the prefix in place of
+// the default prefix, such as to match jobs deployed using an older scheme.
+func newPackMetaKeys(prefix string) packMetaKeys {
+	key := func(k string) string { return prefix + strings.TrimPrefix(k, defaultPackMetaPrefix) }
+	return packMetaKeys{
+		name:           key(job.PackNameKey),
+		registry:       key(job.PackRegistryKey),
+		deploymentName: key(job.PackDeploymentNameKey),
+		ref:            key(job.PackRefKey),
+	}
+}
+
 // TODO: Move to a domain specific package.
 
 // JobStatusInfo encapsulates status information about a running job.
@@ -338,8 +546,49 @@ type JobStatusInfo struct {
 	packName       string
 	registryName   string
 	deploymentName string
+	packRef        string
 	jobID          string
+	namespace      string
+	version        uint64
+	modifyIndex    uint64
 	status         string
+	health         jobHealthState
+	healthReason   string
+
+	// pendingGC is true when the job is dead, and so will be removed by the
+	// next garbage collection.
+	pendingGC bool
+
+	// meta is the metadata of the job, including that added by Nomad Pack.
+	meta map[string]string
+
+	// allocs is the number of allocations of the job in each state, which is
+	// nil when the allocation summary of the job could not be retrieved.
+	allocs *allocCounts
+}
+
+// allocCounts is the number of allocations of a job in each state, summed
+// across its task groups.
+type allocCounts struct {
+	running int
+
+	// pending includes the allocations queued for placement, as well as
+	// those placed which are starting.
+	pending int
+
+	failed int
+}
+
+// newAllocCounts sums the allocation counts of the task groups of the job
+// summary.
+func newAllocCounts(summary *api.JobSummary) *allocCounts {
+	counts := &allocCounts{}
+	for _, tg := range summary.Summary {
+		counts.running += tg.Running
+		counts.pending += tg.Queued + tg.Starting
+		counts.failed += tg.Failed
+	}
+	return counts
 }
 
 // TODO: Move to a domain specific package.
@@ -352,18 +601,54 @@ type JobStatusError struct {
 }
 
 // TODO: Move to a domain specific package.
-func getDeployedPackJobs(c *api.Client, cfg *cache.PackConfig, deploymentName string) ([]JobStatusInfo, []JobStatusError, error) {
+
+// getDeployedPackJobs returns the status of the jobs deployed by the pack.
+// Jobs whose details cannot be retrieved are returned as JobStatusErrors, so
+// the status of the remaining jobs can still be reported. When failFast is
+// set, the first such failure is instead returned as the error. When the pack
+// name is empty, the jobs deployed by every pack are returned.
+func getDeployedPackJobs(c *api.Client, cfg *cache.PackConfig, deploymentName string, keys packMetaKeys, failFast bool) ([]JobStatusInfo, []JobStatusError, error) {
+	packJobs, jobErrs, _, err := getDeployedPackJobsSince(context.Background(), c, cfg, deploymentName, keys, 0, failFast)
+	return packJobs, jobErrs, err
+}
+
+// getDeployedPackJobsSince returns the status of the jobs deployed by the pack
+// in the same way as getDeployedPackJobs, along with the Nomad index the jobs
+// are current as of. When sinceIndex is set, the jobs are listed using a
+// blocking query, which waits until a job has changed after the index, and
+// only the jobs modified after the index are returned.
+func getDeployedPackJobsSince(ctx context.Context, c *api.Client, cfg *cache.PackConfig, deploymentName string, keys packMetaKeys, sinceIndex uint64, failFast bool) ([]JobStatusInfo, []JobStatusError, uint64, error) {
 	jobsApi := c.Jobs()
-	jobs, _, err := jobsApi.List(&api.QueryOptions{})
+	q := &api.QueryOptions{WaitIndex: sinceIndex}
+	jobs, meta, err := jobsApi.List(q.WithContext(ctx))
 	if err != nil {
-		return nil, nil, fmt.Errorf("error finding jobs for pack %s: %s", cfg.Name, err)
+		if cfg.Name == "" {
+			return nil, nil, 0, fmt.Errorf("error finding jobs: %s", err)
+		}
+		return nil, nil, 0, fmt.Errorf("error finding jobs for pack %s: %s", cfg.Name, err)
 	}
 
 	var packJobs []JobStatusInfo
 	var jobErrs []JobStatusError
 	for _, jobStub := range jobs {
-		nomadJob, _, err := jobsApi.Info(jobStub.ID, &api.QueryOptions{})
+		// Jobs launched by periodic and parameterized jobs inherit the pack
+		// metadata of their parent, but are reported as part of its status.
+		if jobStub.ParentID != "" {
+			continue
+		}
+
+		// The job stub is modified whenever the job or its summary changes.
+		if jobStub.ModifyIndex <= sinceIndex {
+			continue
+		}
+
+		// The namespace of the job is used for the jobs it lists, as the
+		// client may query all the namespaces using the * wildcard.
+		nomadJob, _, err := jobsApi.Info(jobStub.ID, &api.QueryOptions{Namespace: jobStub.Namespace})
 		if err != nil {
+			if failFast {
+				return nil, nil, 0, fmt.Errorf("error retrieving job %s: %w", jobStub.ID, err)
+			}
 			jobErrs = append(jobErrs, JobStatusError{
 				jobID:    jobStub.ID,
 				jobError: err,
@@ -373,26 +658,500 @@ func getDeployedPackJobs(c *api.Client, cfg *cache.PackConfig, deploymentName st
 
 		if nomadJob.Meta != nil {
 			jobMeta := nomadJob.Meta
-			jobPackName, ok := jobMeta[job.PackNameKey]
-			if ok && jobPackName == cfg.Name {
+			jobPackName, ok := jobMeta[keys.name]
+			if ok && (cfg.Name == "" || jobPackName == cfg.Name) {
 				// Filter by deployment name if specified
 				if deploymentName != "" {
-					jobDeployName, deployOk := jobMeta[job.PackDeploymentNameKey]
+					jobDeployName, deployOk := jobMeta[keys.deploymentName]
 					if deployOk && jobDeployName != deploymentName {
 						continue
 					}
 				}
+
+				// The summary is included when listing jobs, but is retrieved
+				// for any job listed without one. Failing to retrieve it only
+				// omits the allocation counts of the job.
+				summary := jobStub.JobSummary
+				if summary == nil {
+					summary, _, err = jobsApi.Summary(jobStub.ID, &api.QueryOptions{Namespace: jobStub.Namespace})
+					if err != nil {
+						if failFast {
+							return nil, nil, 0, fmt.Errorf("error retrieving allocation summary for job %s: %w", jobStub.ID, err)
+						}
+						jobErrs = append(jobErrs, JobStatusError{
+							jobID:    jobStub.ID,
+							jobError: fmt.Errorf("error retrieving allocation summary: %w", err),
+						})
+						summary = nil
+					}
+				}
+				var allocs *allocCounts
+				if summary != nil {
+					allocs = newAllocCounts(summary)
+				}
+
+				health, healthReason := jobHealth(nomadJob, summary)
 				packJobs = append(packJobs, JobStatusInfo{
-					packName:       cfg.Name,
-					registryName:   jobMeta[job.PackRegistryKey],
-					deploymentName: jobMeta[job.PackDeploymentNameKey],
+					packName:       jobPackName,
+					registryName:   jobMeta[keys.registry],
+					deploymentName: jobMeta[keys.deploymentName],
+					packRef:        jobMeta[keys.ref],
 					jobID:          *nomadJob.ID,
-					status:         *nomadJob.Status,
+					namespace:      pointer.Value(nomadJob.Namespace),
+					version:        pointer.Value(nomadJob.Version),
+					modifyIndex:    pointer.Value(nomadJob.JobModifyIndex),
+					status:         jobStatus(jobsApi, nomadJob, summary),
+					health:         health,
+					healthReason:   healthReason,
+					pendingGC:      jobPendingGC(nomadJob),
+					meta:           jobMeta,
+					allocs:         allocs,
 				})
 			}
 		}
 	}
-	return packJobs, jobErrs, nil
+	return packJobs, jobErrs, meta.LastIndex, nil
+}
+
+// getPackJobAllocs returns the allocations of each of the pack jobs, keyed by
+// the job ID. Jobs whose allocations cannot be retrieved are added to the
+// returned job errors, unless failFast is set, in which case the first failure
+// is returned as the error.
+func getPackJobAllocs(c *api.Client, packJobs []JobStatusInfo, jobErrs []JobStatusError, failFast bool) (map[string][]*api.AllocationListStub, []JobStatusError, error) {
+	jobAllocs := make(map[string][]*api.AllocationListStub, len(packJobs))
+	for _, info := range packJobs {
+		allocs, _, err := c.Jobs().Allocations(info.jobID, false, &api.QueryOptions{Namespace: info.namespace})
+		if err != nil {
+			if failFast {
+				return nil, nil, fmt.Errorf("error retrieving allocations for job %s: %w", info.jobID, err)
+			}
+			jobErrs = append(jobErrs, JobStatusError{
+				jobID:    info.jobID,
+				jobError: fmt.Errorf("error retrieving allocations: %w", err),
+			})
+			continue
+		}
+		jobAllocs[info.jobID] = allocs
+	}
+	return jobAllocs, jobErrs, nil
+}
+
+// getPackJobHealthExplanations returns the explanation of the health of each
+// of the pack jobs, keyed by job ID. Allocations already retrieved are passed
+// as jobAllocs, and retrieved for the other jobs. Failing to retrieve the
+// allocations or latest deployment of a job does not fail the command, the
+// explanation only omits them instead.
+func getPackJobHealthExplanations(c *api.Client, packJobs []JobStatusInfo, jobAllocs map[string][]*api.AllocationListStub, now time.Time) map[string]string {
+	out := make(map[string]string, len(packJobs))
+	for _, info := range packJobs {
+		allocs, ok := jobAllocs[info.jobID]
+		if !ok {
+			allocs, _, _ = c.Jobs().Allocations(info.jobID, false, &api.QueryOptions{Namespace: info.namespace})
+		}
+		deployment, _, err := c.Jobs().LatestDeployment(info.jobID, &api.QueryOptions{Namespace: info.namespace})
+		if err != nil {
+			deployment = nil
+		}
+		out[info.jobID] = explainJobHealth(info, allocs, deployment, now)
+	}
+	return out
+}
+
+// taskGroupScaling is the scaling state of a task group which has a
+// horizontal scaling policy.
+type taskGroupScaling struct {
+	jobID   string
+	group   string
+	enabled bool
+	min     int64
+	max     int64
+	desired int
+	running int
+}
+
+// inBounds returns whether the desired count of the task group is within the
+// minimum and maximum of its scaling policy.
+func (s taskGroupScaling) inBounds() bool {
+	return int64(s.desired) >= s.min && int64(s.desired) <= s.max
+}
+
+// getPackJobScaling returns the scaling state of the task groups of the pack
+// jobs which have horizontal scaling policies. Task groups without a policy
+// are omitted. Jobs whose scaling state cannot be retrieved are added to the
+// returned JobStatusErrors, unless failFast is set, in which case the first
+// failure is returned as the error.
+func getPackJobScaling(c *api.Client, packJobs []JobStatusInfo, jobErrs []JobStatusError, failFast bool) ([]taskGroupScaling, []JobStatusError, error) {
+	var out []taskGroupScaling
+	for _, info := range packJobs {
+		scaling, err := getJobScaling(c, info.namespace, info.jobID)
+		if err != nil {
+			if failFast {
+				return nil, nil, fmt.Errorf("error retrieving scaling policies for job %s: %w", info.jobID, err)
+			}
+			jobErrs = append(jobErrs, JobStatusError{
+				jobID:    info.jobID,
+				jobError: fmt.Errorf("error retrieving scaling policies: %w", err),
+			})
+			continue
+		}
+		out = append(out, scaling...)
+	}
+	return out, jobErrs, nil
+}
+
+// getJobScaling returns the scaling state of the task groups of the job which
+// have horizontal scaling policies, ordered by task group name.
+func getJobScaling(c *api.Client, namespace, jobID string) ([]taskGroupScaling, error) {
+	stubs, _, err := c.Scaling().ListPolicies(&api.QueryOptions{Namespace: namespace, Params: map[string]string{"job": jobID}})
+	if err != nil {
+		return nil, err
+	}
+	if len(stubs) == 0 {
+		return nil, nil
+	}
+
+	status, _, err := c.Jobs().ScaleStatus(jobID, &api.QueryOptions{Namespace: namespace})
+	if err != nil {
+		return nil, err
+	}
+
+	var out []taskGroupScaling
+	for _, stub := range stubs {
+		if stub.Type != api.ScalingPolicyTypeHorizontal {
+			continue
+		}
+		policy, _, err := c.Scaling().GetPolicy(stub.ID, &api.QueryOptions{Namespace: namespace})
+		if err != nil {
+			return nil, err
+		}
+
+		group := stub.Target["Group"]
+		tgScaling := taskGroupScaling{
+			jobID:   jobID,
+			group:   group,
+			enabled: stub.Enabled,
+			desired: status.TaskGroups[group].Desired,
+			running: status.TaskGroups[group].Running,
+		}
+		if policy.Min != nil {
+			tgScaling.min = *policy.Min
+		}
+		if policy.Max != nil {
+			tgScaling.max = *policy.Max
+		}
+		out = append(out, tgScaling)
+	}
+
+	slices.SortFunc(out, func(a, b taskGroupScaling) int { return strings.Compare(a.group, b.group) })
+	return out, nil
+}
+
+// jobEvaluation is the latest evaluation of a job, along with the placement
+// failures of its task groups.
+type jobEvaluation struct {
+	jobID       string
+	evalID      string
+	triggeredBy string
+	status      string
+
+	// failedTGAllocs are the placement failures of the evaluation, keyed by
+	// task group name. When the latest evaluation is blocked, these are the
+	// failures of the evaluation which created it.
+	failedTGAllocs map[string]*api.AllocationMetric
+}
+
+// getPackJobEvals returns the latest evaluation of each of the pack jobs.
+// Jobs without evaluations are omitted. Jobs whose evaluations cannot be
+// retrieved are added to the returned JobStatusErrors, unless failFast is
+// set, in which case the first failure is returned as the error.
+func getPackJobEvals(c *api.Client, packJobs []JobStatusInfo, jobErrs []JobStatusError, failFast bool) ([]jobEvaluation, []JobStatusError, error) {
+	var out []jobEvaluation
+	for _, info := range packJobs {
+		evals, _, err := c.Jobs().Evaluations(info.jobID, &api.QueryOptions{Namespace: info.namespace})
+		if err != nil {
+			if failFast {
+				return nil, nil, fmt.Errorf("error retrieving evaluations for job %s: %w", info.jobID, err)
+			}
+			jobErrs = append(jobErrs, JobStatusError{
+				jobID:    info.jobID,
+				jobError: fmt.Errorf("error retrieving evaluations: %w", err),
+			})
+			continue
+		}
+		if eval, ok := latestJobEvaluation(info.jobID, evals); ok {
+			out = append(out, eval)
+		}
+	}
+	return out, jobErrs, nil
+}
+
+// latestJobEvaluation returns the most recently created of the evaluations.
+// A blocked evaluation has no placement failures of its own, since it is
+// created by an evaluation which failed to place allocations, so the
+// failures of that evaluation are used instead.
+func latestJobEvaluation(jobID string, evals []*api.Evaluation) (jobEvaluation, bool) {
+	if len(evals) == 0 {
+		return jobEvaluation{}, false
+	}
+
+	latest := slices.MaxFunc(evals, func(a, b *api.Evaluation) int {
+		return cmp.Compare(a.CreateIndex, b.CreateIndex)
+	})
+
+	out := jobEvaluation{
+		jobID:          jobID,
+		evalID:         latest.ID,
+		triggeredBy:    latest.TriggeredBy,
+		status:         latest.Status,
+		failedTGAllocs: latest.FailedTGAllocs,
+	}
+	if len(out.failedTGAllocs) == 0 && latest.PreviousEval != "" {
+		for _, eval := range evals {
+			if eval.ID == latest.PreviousEval {
+				out.failedTGAllocs = eval.FailedTGAllocs
+				break
+			}
+		}
+	}
+	return out, true
+}
+
+// placementFailureReasons returns the reasons the scheduler could not place
+// the allocations of a task group, such as nodes excluded by constraints or
+// with exhausted resources, in a consistent order.
+func placementFailureReasons(metric *api.AllocationMetric) []string {
+	var out []string
+
+	if metric.NodesEvaluated == 0 {
+		out = append(out, "no nodes were eligible for evaluation")
+	}
+	for _, dc := range slices.Sorted(maps.Keys(metric.NodesAvailable)) {
+		if metric.NodesAvailable[dc] == 0 {
+			out = append(out, fmt.Sprintf("no nodes are available in datacenter %q", dc))
+		}
+	}
+	for _, class := range slices.Sorted(maps.Keys(metric.ClassFiltered)) {
+		out = append(out, fmt.Sprintf("class %q filtered %d nodes", class, metric.ClassFiltered[class]))
+	}
+	for _, constraint := range slices.Sorted(maps.Keys(metric.ConstraintFiltered)) {
+		out = append(out, fmt.Sprintf("constraint %q filtered %d nodes", constraint, metric.ConstraintFiltered[constraint]))
+	}
+	if metric.NodesExhausted > 0 {
+		out = append(out, fmt.Sprintf("resources exhausted on %d nodes", metric.NodesExhausted))
+	}
+	for _, class := range slices.Sorted(maps.Keys(metric.ClassExhausted)) {
+		out = append(out, fmt.Sprintf("class %q exhausted on %d nodes", class, metric.ClassExhausted[class]))
+	}
+	for _, dim := range slices.Sorted(maps.Keys(metric.DimensionExhausted)) {
+		out = append(out, fmt.Sprintf("dimension %q exhausted on %d nodes", dim, metric.DimensionExhausted[dim]))
+	}
+	for _, quota := range metric.QuotaExhausted {
+		out = append(out, fmt.Sprintf("quota limit hit %q", quota))
+	}
+	if metric.CoalescedFailures > 0 {
+		out = append(out, fmt.Sprintf("%d additional allocations could not be placed", metric.CoalescedFailures))
+	}
+	return out
+}
+
+// filterJobsByNode returns only the jobs, and their allocations, which are
+// placed on the client node. The node may be specified by its ID, a prefix of
+// its ID, or its name.
+func filterJobsByNode(packJobs []JobStatusInfo, jobAllocs map[string][]*api.AllocationListStub, node string) ([]JobStatusInfo, map[string][]*api.AllocationListStub) {
+	var outJobs []JobStatusInfo
+	outAllocs := make(map[string][]*api.AllocationListStub)
+
+	for _, info := range packJobs {
+		for _, alloc := range jobAllocs[info.jobID] {
+			if strings.HasPrefix(alloc.NodeID, node) || alloc.NodeName == node {
+				outAllocs[info.jobID] = append(outAllocs[info.jobID], alloc)
+			}
+		}
+		if len(outAllocs[info.jobID]) > 0 {
+			outJobs = append(outJobs, info)
+		}
+	}
+	return outJobs, outAllocs
+}
+
+// filterJobsByMeta returns only the jobs whose metadata has every key of meta
+// set to the same value.
+func filterJobsByMeta(packJobs []JobStatusInfo, meta map[string]string) []JobStatusInfo {
+	return slices.DeleteFunc(packJobs, func(info JobStatusInfo) bool {
+		for k, v := range meta {
+			if jobVal, ok := info.meta[k]; !ok || jobVal != v {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// filterJobsByStatus removes the jobs whose status is not one of the passed
+// statuses. The status of periodic and parameterized jobs summarizes their
+// children, so they are matched by their kind, such as periodic.
+func filterJobsByStatus(packJobs []JobStatusInfo, statuses []string) []JobStatusInfo {
+	return slices.DeleteFunc(packJobs, func(info JobStatusInfo) bool {
+		status, _, _ := strings.Cut(info.status, " ")
+		return !slices.Contains(statuses, status)
+	})
+}
+
+// formatMetaFilter formats the metadata filter as sorted key=value pairs.
+func formatMetaFilter(meta map[string]string) string {
+	pairs := make([]string, 0, len(meta))
+	for _, k := range slices.Sorted(maps.Keys(meta)) {
+		pairs = append(pairs, k+"="+meta[k])
+	}
+	return strings.Join(pairs, ", ")
+}
+
+// logTailBytes is the number of bytes read from the end of a task log, from
+// which the requested number of lines are taken.
+const logTailBytes = 64 * 1024
+
+// failedJobLogAllocs returns the allocations whose logs are relevant to why a
+// job has failed. These are its failed allocations, or its most recently
+// modified allocation when none have failed.
+func failedJobLogAllocs(allocs []*api.AllocationListStub) []*api.AllocationListStub {
+	var failed []*api.AllocationListStub
+	var latest *api.AllocationListStub
+	for _, alloc := range allocs {
+		if alloc.ClientStatus == api.AllocClientStatusFailed {
+			failed = append(failed, alloc)
+		}
+		if latest == nil || alloc.ModifyIndex > latest.ModifyIndex {
+			latest = alloc
+		}
+	}
+	if len(failed) == 0 && latest != nil {
+		return []*api.AllocationListStub{latest}
+	}
+	return failed
+}
+
+// getAllocLogTail returns the last n lines of the stderr log of each task in
+// the allocation, keyed by the task name.
+func getAllocLogTail(c *api.Client, stub *api.AllocationListStub, n int) (map[string]string, error) {
+	alloc, _, err := c.Allocations().Info(stub.ID, &api.QueryOptions{Namespace: stub.Namespace})
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving allocation %s: %w", shortID(stub.ID), err)
+	}
+
+	logs := make(map[string]string, len(alloc.TaskStates))
+	for task := range alloc.TaskStates {
+		cancel := make(chan struct{})
+		frames, errCh := c.AllocFS().Logs(alloc, false, task, "stderr", api.OriginEnd, logTailBytes, cancel, nil)
+
+		var b strings.Builder
+	read:
+		for {
+			select {
+			case frame, ok := <-frames:
+				if !ok {
+					break read
+				}
+				b.Write(frame.Data)
+			case err := <-errCh:
+				close(cancel)
+				return nil, fmt.Errorf("error retrieving logs of task %q in allocation %s: %w", task, shortID(stub.ID), err)
+			}
+		}
+		close(cancel)
+
+		logs[task] = lastLines(b.String(), n)
+	}
+	return logs, nil
+}
+
+// lastLines returns the last n lines of s, ignoring any trailing newline.
+func lastLines(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// shortID returns the short form of a Nomad UUID, as output by the Nomad CLI.
+func shortID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
+
+// deploymentRefConflicts returns the deployment names which are shared by jobs
+// deployed from differing pack refs, along with the sorted refs in use. This
+// usually means a deployment name was reused for a different version of the
+// pack, leaving stale jobs from the earlier deployment behind.
+func deploymentRefConflicts(packJobs []JobStatusInfo) map[string][]string {
+	refs := make(map[string]map[string]struct{})
+	for _, info := range packJobs {
+		if refs[info.deploymentName] == nil {
+			refs[info.deploymentName] = make(map[string]struct{})
+		}
+		refs[info.deploymentName][info.packRef] = struct{}{}
+	}
+
+	conflicts := make(map[string][]string)
+	for name, set := range refs {
+		if len(set) < 2 {
+			continue
+		}
+		for ref := range set {
+			conflicts[name] = append(conflicts[name], ref)
+		}
+		sort.Strings(conflicts[name])
+	}
+	return conflicts
+}
+
+// jobStatus returns the status to report for a deployed job. Periodic and
+// parameterized jobs never run themselves and are reported as "dead" by Nomad,
+// so their status is instead derived from the child jobs they have launched.
+func jobStatus(jobsApi *api.Jobs, nomadJob *api.Job, summary *api.JobSummary) string {
+	if nomadJob.Stop != nil && *nomadJob.Stop {
+		return *nomadJob.Status
+	}
+
+	var children *api.JobChildrenSummary
+	if summary != nil {
+		children = summary.Children
+	}
+
+	switch {
+	case nomadJob.IsPeriodic():
+		return formatPeriodicStatus(lastPeriodicLaunch(jobsApi, nomadJob), children)
+	case nomadJob.IsParameterized():
+		return formatParameterizedStatus(children)
+	default:
+		return *nomadJob.Status
+	}
+}
+
+// lastPeriodicLaunch returns the submit time of the most recent child job
+// launched by the periodic job. A zero time is returned if the job has not
+// launched any children, or they could not be listed.
+func lastPeriodicLaunch(jobsApi *api.Jobs, nomadJob *api.Job) time.Time {
+	children, _, err := jobsApi.List(&api.QueryOptions{
+		Prefix:    *nomadJob.ID + api.JobPeriodicLaunchSuffix,
+		Namespace: *nomadJob.Namespace,
+	})
+	if err != nil {
+		return time.Time{}
+	}
+
+	var last int64
+	for _, child := range children {
+		if child.ParentID == *nomadJob.ID && child.SubmitTime > last {
+			last = child.SubmitTime
+		}
+	}
+	if last == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, last)
 }
 
 // clientOptsFromCLI emits a slice of v1.ClientOptions based on the environment
@@ -408,6 +1167,64 @@ func clientOptsFromCLI(c *baseCommand) *api.Config {
 	return conf
 }
 
+// validateAddress verifies the Unix domain socket referenced by a unix://
+// address exists, so that a missing socket is reported clearly when the client
+// is initialized rather than as a failed request. The client connects to the
+// socket using a dialer, rather than over TCP. Other addresses are not checked.
+func validateAddress(addr string) error {
+	if !strings.HasPrefix(addr, "unix://") {
+		return nil
+	}
+
+	u, err := url.Parse(addr)
+	if err != nil {
+		return fmt.Errorf("invalid Nomad address %q: %w", addr, err)
+	}
+	if u.Host != "" || u.Path == "" {
+		return fmt.Errorf("invalid Nomad address %q: the socket must be an absolute path, such as unix:///var/run/nomad.sock", addr)
+	}
+
+	fi, err := os.Stat(u.Path)
+	if err != nil {
+		return fmt.Errorf("failed to access Nomad socket: %w", err)
+	}
+	if fi.Mode().Type() != fs.ModeSocket {
+		return fmt.Errorf("invalid Nomad address %q: %s is not a Unix domain socket", addr, u.Path)
+	}
+	return nil
+}
+
+// validateTLSConfig verifies the certificate files referenced by the TLS
+// config can be loaded, so that misconfigured files are reported clearly when
+// the client is initialized rather than as a failed request.
+func validateTLSConfig(conf *api.TLSConfig) error {
+	if conf == nil {
+		return nil
+	}
+
+	if conf.CACert != "" {
+		pem, err := os.ReadFile(conf.CACert)
+		if err != nil {
+			return fmt.Errorf("failed to read CA certificate %q: %w", conf.CACert, err)
+		}
+		if !x509.NewCertPool().AppendCertsFromPEM(pem) {
+			return fmt.Errorf("invalid CA certificate %q: no PEM encoded certificates found", conf.CACert)
+		}
+	}
+
+	switch {
+	case conf.ClientCert != "" && conf.ClientKey == "":
+		return errors.New("a client key must be provided with the client certificate, using --client-key or NOMAD_CLIENT_KEY")
+	case conf.ClientCert == "" && conf.ClientKey != "":
+		return errors.New("a client certificate must be provided with the client key, using --client-cert or NOMAD_CLIENT_CERT")
+	case conf.ClientCert != "":
+		if _, err := tls.LoadX509KeyPair(conf.ClientCert, conf.ClientKey); err != nil {
+			return fmt.Errorf("failed to load client certificate %q and key %q: %w", conf.ClientCert, conf.ClientKey, err)
+		}
+	}
+	return nil
+}
+
 // handlBasicAuth checks whether the NOMAD_ADDR string is in the user:pass@addr
 // format and if it is, it returns user, password and address. It returns "", "",
 // address otherwise.
@@ -480,8 +1297,12 @@ func clientOptsFromFlags(c *baseCommand, conf *api.Config) {
 	if cfg.token != "" {
 		conf.SecretID = cfg.token
 	}
-	if cfg.clientCert != "" && cfg.clientKey != "" {
+	// The client certificate and key are set independently, so passing only
+	// one of them is reported by validateTLSConfig rather than ignored.
+	if cfg.clientCert != "" {
 		conf.TLSConfig.ClientCert = cfg.clientCert
+	}
+	if cfg.clientKey != "" {
 		conf.TLSConfig.ClientKey = cfg.clientKey
 	}
 	if cfg.caCert != "" {
@@ -494,3 +1315,36 @@ func clientOptsFromFlags(c *baseCommand, conf *api.Config) {
 		conf.TLSConfig.Insecure = true
 	}
 }
+
+// stylePrefixesNone is the value of --style-prefixes which disables every
+// prefix of the non-interactive output.
+const stylePrefixesNone = "none"
+
+// parseStylePrefixes parses a comma separated list of key=prefix pairs, such
+// as "header=# ,error=ERROR: ", which replace the default prefixes of the
+// non-interactive output. Unlike the keys, the prefixes are not trimmed, so
+// they can end with a space.
+func parseStylePrefixes(in string) (terminal.StylePrefixes, error) {
+	if in == stylePrefixesNone {
+		return terminal.StylePrefixes{}, nil
+	}
+
+	out := terminal.DefaultStylePrefixes
+	for _, pair := range strings.Split(in, ",") {
+		key, prefix, ok := strings.Cut(pair, "=")
+		if !ok {
+			return out, fmt.Errorf("invalid style prefix %q, expected the form key=prefix", pair)
+		}
+		switch strings.TrimSpace(key) {
+		case "header":
+			out.Header = prefix
+		case "error":
+			out.Error = prefix
+		case "step":
+			out.Step = prefix
+		default:
+			return out, fmt.Errorf("unknown style prefix key %q, must be one of: error, header, step", key)
+		}
+	}
+	return out, nil
+}
@@ -4,22 +4,139 @@
 package cli
 
 import (
+	"cmp"
 	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 
+	"github.com/fatih/color"
 	"github.com/hashicorp/nomad-pack/internal/pkg/cache"
+	"github.com/hashicorp/nomad-pack/internal/pkg/errors"
 	"github.com/hashicorp/nomad-pack/internal/pkg/flag"
 	"github.com/hashicorp/nomad-pack/internal/pkg/loader"
+	"github.com/hashicorp/nomad-pack/internal/pkg/renderer"
+	"github.com/hashicorp/nomad-pack/internal/pkg/variable/decoder"
 	"github.com/hashicorp/nomad-pack/internal/pkg/variable/parser"
 	"github.com/hashicorp/nomad-pack/internal/pkg/variable/parser/config"
+	"github.com/hashicorp/nomad-pack/sdk/pack"
+	"github.com/hashicorp/nomad-pack/sdk/pack/variables"
+	"github.com/hashicorp/nomad-pack/terminal"
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/jobspec2"
 	"github.com/mitchellh/go-glint"
+	"github.com/mitchellh/go-wordwrap"
 	"github.com/zclconf/go-cty/cty"
+	"golang.org/x/term"
 )
 
 type InfoCommand struct {
 	*baseCommand
 	packConfig *cache.PackConfig
+
+	// renderOutputs is a boolean flag to control whether the output template
+	// is rendered against the resolved variables and displayed.
+	renderOutputs bool
+
+	// readme is a boolean flag to control whether the pack README is
+	// displayed after the pack metadata.
+	readme bool
+
+	// resources is a boolean flag to control whether the pack is rendered
+	// and the total resources requested by its jobs are displayed.
+	resources bool
+
+	// planSummary is a boolean flag to control whether the pack is rendered
+	// and the number of jobs, task groups, and tasks it creates are displayed.
+	planSummary bool
+
+	// constraints is a boolean flag to control whether the pack is rendered
+	// and the distinct constraints and affinities of its jobs are displayed.
+	constraints bool
+
+	// exampleRun is a boolean flag to control whether a run command, with a
+	// placeholder value for each required variable, is displayed.
+	exampleRun bool
+
+	// onlyRegistryDefaults is a boolean flag to control whether only the
+	// variables which use the default shipped with the pack are displayed.
+	onlyRegistryDefaults bool
+
+	// group is the name of the group whose variables are displayed, as
+	// passed using the --group flag. When empty, the variables of every group
+	// are displayed, sectioned by their group.
+	group string
+
+	// diffDeployed is a boolean flag to control whether the variable defaults
+	// are compared with the values recorded by the deployed pack.
+	diffDeployed bool
+
+	// usage is a boolean flag to control whether the declared variables are
+	// cross-referenced against the pack templates to find unused variables.
+	usage bool
+
+	// strict is a boolean flag to control whether the command fails when
+	// usage finds any unused variables.
+	strict bool
+
+	// checkDefaults is a boolean flag to control whether the variable
+	// defaults are checked against the validations of their variable.
+	checkDefaults bool
+
+	// dumpVars is a boolean flag to control whether the resolved variables
+	// are output as a variable file, instead of the pack information.
+	dumpVars bool
+
+	// showSensitive is a boolean flag to control whether the values of
+	// sensitive variables are output by dumpVars, rather than redacted.
+	showSensitive bool
+
+	// output is the format used to output the pack information.
+	output string
+
+	// withStatus is a boolean flag to control whether the JSON output includes
+	// the variables of the pack and the status of its deployed jobs.
+	withStatus bool
+
+	// width is the number of columns the pretty output is laid out within,
+	// when set using the --width flag. When 0, the width of the terminal is
+	// used.
+	width int
 }
 
+const (
+	// infoOutputPretty outputs the pack information using styled text.
+	infoOutputPretty = "pretty"
+
+	// infoOutputPlain outputs the pack information as uncolored plain text.
+	infoOutputPlain = "plain"
+
+	// infoOutputDot outputs the dependency graph of the pack in the Graphviz
+	// DOT language.
+	infoOutputDot = "dot"
+
+	// infoOutputJSON outputs the pack metadata as JSON, or the resolved
+	// variables as a JSON variable file when used with --dump-vars.
+	infoOutputJSON = "json"
+
+	// infoOutputYAML outputs the pack metadata and variables as YAML.
+	infoOutputYAML = "yaml"
+)
+
+const (
+	// infoDefaultWidth is the number of columns the pretty output is laid out
+	// within when stdout is not a terminal, such as in CI.
+	infoDefaultWidth = 120
+
+	// infoNarrowWidth is the width below which the gap between the labels and
+	// values of the pretty output is reduced, to leave room for the values.
+	infoNarrowWidth = 60
+)
+
 func (c *InfoCommand) Run(args []string) int {
 	c.cmdKey = "info" // Add cmdKey here to print out helpUsageMessage on Init error
 	// Initialize. If we fail, we just exit since Init handles the UI.
@@ -33,30 +150,96 @@ func (c *InfoCommand) Run(args []string) int {
 		return 1
 	}
 
+	// Output any errors held back by --group-errors once the run completes.
+	defer c.flushErrors()
+
 	c.packConfig.Name = c.args[0]
 
+	cleanup, err := fetchEphemeralPack(c.baseCommand, c.packConfig)
+	if err != nil {
+		c.ui.ErrorWithContext(err, "failed to fetch pack")
+		return 1
+	}
+	defer cleanup()
+
 	// Set the packConfig defaults if necessary and generate our UI error context.
 	errorContext := initPackCommand(c.packConfig)
+	defer c.packConfig.Cleanup()
 
 	// verify packs exist before running jobs
 	if err := cache.VerifyPackExists(c.packConfig, errorContext, c.ui); err != nil {
 		return 1
 	}
 
+	if c.strict && !c.usage {
+		c.ui.Error("--strict can only be used with --usage")
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	if c.width < 0 {
+		c.ui.Error("--width must not be negative")
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	if c.dumpVars && (c.output == infoOutputDot || c.output == infoOutputYAML) {
+		c.ui.Error(fmt.Sprintf("--dump-vars cannot be used with --output=%s", c.output))
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	if c.showSensitive && !c.dumpVars {
+		c.ui.Error("--show-sensitive can only be used with --dump-vars")
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	if c.withStatus && (c.output != infoOutputJSON || c.dumpVars) {
+		c.ui.Error("--with-status can only be used with --output=json, and cannot be used with --dump-vars")
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	if c.output == infoOutputDot {
+		packManager := generatePackManager(c.baseCommand, nil, c.packConfig)
+		p, err := packManager.LoadPack()
+		if err != nil {
+			outputLoadError(c.ui, err, "failed to load pack", errorContext)
+			return 1
+		}
+		c.ui.Output(formatDependencyGraph(p))
+		return 0
+	}
+
 	packPath := c.packConfig.Path
 
-	p, err := loader.Load(packPath)
+	p, err := loader.Load(packPath, loader.AllowExternalSymlinks(c.allowExternalSymlinks))
 	if err != nil {
-		c.ui.ErrorWithContext(err, "failed to load pack from local directory", errorContext.GetAll()...)
+		outputLoadError(c.ui, err, "failed to load pack from local directory", errorContext)
 		return 1
 	}
 
+	if c.output == infoOutputJSON && !c.dumpVars && !c.withStatus {
+		return c.outputInfoJSON(p, nil, nil, errorContext)
+	}
+
 	variableParser, err := parser.NewParser(&config.ParserConfig{
 		ParentPack:        p,
 		RootVariableFiles: p.RootVariableFiles(),
+		EnvOverrides:      c.envVars,
+		FileOverrides:     c.varFiles,
+		FlagOverrides:     c.vars,
+		PatchOverrides:    c.varPatches,
+		Profile:           c.profile,
+		ProfileFile:       profileFilePath(c.baseCommand, packPath),
 		IgnoreMissingVars: c.ignoreMissingVars,
+
+		ResolveValueRefs:    c.resolveVarRefs,
+		MaxVariableFileSize: c.maxVarFileSize,
 	})
 	if err != nil {
+		c.ui.ErrorWithContext(err, "failed to instantiate parser", errorContext.GetAll()...)
 		return 1
 	}
 
@@ -65,68 +248,982 @@ func (c *InfoCommand) Run(args []string) int {
 		c.ui.Info(diags.Error())
 		return 1
 	}
+	for _, diag := range diags {
+		c.ui.Warning(diag.Detail)
+	}
 
-	// Create a new glint document to handle the outputting of information.
-	doc := glint.New()
+	if c.dumpVars {
+		return c.outputDumpVars(parsedVars, p.ID(), errorContext)
+	}
+
+	if c.withStatus {
+		return c.outputInfoWithStatus(p, parsedVars, errorContext)
+	}
 
-	doc.Append(glint.Layout(
-		glint.Style(glint.Text("Pack Name          "), glint.Bold()),
-		glint.Text(p.Metadata.Pack.Name),
-	).Row())
+	if c.output == infoOutputYAML {
+		return c.outputInfoYAML(p, parsedVars, errorContext)
+	}
 
-	doc.Append(glint.Layout(
-		glint.Style(glint.Text("Description        "), glint.Bold()),
-		glint.Text(p.Metadata.Pack.Description),
-	).Row())
+	packVars := infoVariables(parsedVars, c.onlyRegistryDefaults, c.group)
 
-	doc.Append(glint.Layout(
-		glint.Style(glint.Text("Application URL    "), glint.Bold()),
-		glint.Text(p.Metadata.App.URL),
-	).Row())
+	switch c.output {
+	case infoOutputPlain:
+		c.ui.Output(formatInfoPlain(p, packVars))
+	default:
+		stdout, _, err := c.ui.OutputWriters()
+		if err != nil {
+			c.ui.ErrorWithContext(err, "failed to output pack info", errorContext.GetAll()...)
+			return 1
+		}
+		renderInfoDoc(stdout, infoWidth(stdout, c.width), p, packVars)
+	}
 
-	for pName, variables := range parsedVars.GetVars() {
+	if custom := customMetadataValues(p.Metadata); len(custom) > 0 {
+		c.ui.Header("Metadata")
+		c.ui.NamedValues(custom)
+	}
 
-		doc.Append(glint.Layout(
-			glint.Style(glint.Text(fmt.Sprintf("Pack %q Variables:", pName)), glint.Bold()),
-		).Row())
+	c.ui.Header("Dependencies")
+	if deps := formatInfoDependencies(p.Metadata); deps != nil {
+		c.ui.Table(deps)
+	} else {
+		c.ui.Info("none")
+	}
+
+	if c.exampleRun {
+		c.ui.Header("Example Run")
+		c.ui.Output(formatExampleRun(c.packConfig, parsedVars, p.ID()))
+	}
+
+	if c.readme {
+		if code := c.outputReadme(packPath); code != 0 {
+			return code
+		}
+	}
+
+	if c.usage {
+		if code := c.outputVariableUsage(p, parsedVars, errorContext); code != 0 {
+			return code
+		}
+	}
+
+	if c.checkDefaults {
+		if code := c.outputDefaultValidations(parsedVars); code != 0 {
+			return code
+		}
+	}
+
+	// The pack is only rendered once, when outputting more than one summary
+	// of its jobs.
+	if c.resources || c.planSummary || c.constraints {
+		jobs, code := c.renderPackJobs(errorContext)
+		if code != 0 {
+			return code
+		}
+		if c.resources {
+			c.outputResources(jobs)
+		}
+		if c.planSummary {
+			c.outputPlanSummary(jobs)
+		}
+		if c.constraints {
+			c.outputConstraints(jobs)
+		}
+	}
+
+	if c.diffDeployed {
+		if code := c.outputDeployedDiff(p, parsedVars, errorContext); code != 0 {
+			return code
+		}
+	}
 
-		// to output required variables first
-		var required []string
-		var optional []string
+	if c.renderOutputs {
+		return c.renderOutputTemplate(p, errorContext)
+	}
+	return 0
+}
+
+// outputDeployedDiff outputs the variables whose default differs from the
+// value the deployed pack was rendered with.
+func (c *InfoCommand) outputDeployedDiff(p *pack.Pack, parsedVars *parser.ParsedVariables, errorContext *errors.UIErrorContext) int {
+	deploymentName := getDeploymentName(c.baseCommand, c.packConfig)
+	errorContext.Add(errors.UIContextPrefixDeploymentName, deploymentName)
 
-		for _, v := range variables {
+	client, err := c.getAPIClient()
+	if err != nil {
+		c.ui.ErrorWithContext(err, "failed to initialize client", errorContext.GetAll()...)
+		return 1
+	}
 
-			varType := "unknown"
-			if !v.Type.Equals(cty.NilType) {
-				// check the explicit "type" parameter
-				varType = v.Type.FriendlyName()
-			} else if !v.Default.IsNull() {
-				// or infer from the default
-				varType = v.Default.Type().FriendlyName()
+	jobs, err := getPackJobsByDeploy(client, c.packConfig, deploymentName)
+	if err != nil {
+		c.ui.ErrorWithContext(err, "failed to find deployed pack", errorContext.GetAll()...)
+		return 1
+	}
+
+	deployed, err := getDeployedVariables(client, jobs)
+	if err != nil {
+		c.ui.ErrorWithContext(err, "failed to read deployed variables", errorContext.GetAll()...)
+		return 1
+	}
+
+	c.ui.Header("Deployed Variables")
+	if deployed == nil {
+		c.ui.Warning(fmt.Sprintf("Deployment %q did not record its variables; redeploy it to compare them", deploymentName))
+		return 0
+	}
+
+	table := diffDeployedVariables(parsedVars, p.ID(), deployed)
+	if len(table.Rows) == 0 {
+		c.ui.Success("All deployed variables match the pack defaults")
+		return 0
+	}
+	c.ui.Table(table)
+	return 0
+}
+
+// getDeployedVariables returns the variables recorded with the submission of
+// the current version of the deployed jobs. Every job of a deployment records
+// the same variables, so the first job with a recorded submission is used. A
+// nil map is returned if no job recorded its variables.
+func getDeployedVariables(client *api.Client, jobs []*api.Job) (map[string]string, error) {
+	for _, job := range jobs {
+		sub, _, err := client.Jobs().Submission(*job.ID, int(*job.Version), &api.QueryOptions{Namespace: *job.Namespace})
+		if err != nil {
+			// Jobs registered without a submission return a not found error.
+			if strings.Contains(err.Error(), "404") {
+				continue
+			}
+			return nil, fmt.Errorf("failed to read submission of job %q: %w", *job.ID, err)
+		}
+		if len(sub.VariableFlags) > 0 {
+			return sub.VariableFlags, nil
+		}
+	}
+	return nil, nil
+}
+
+// diffDeployedVariables returns a table of the variables whose default differs
+// from the value recorded by the deployment, ordered by name. Variables are
+// named relative to the root pack, matching the keys of deployed. Variables
+// which did not exist when the pack was deployed have no recorded value.
+func diffDeployedVariables(parsedVars *parser.ParsedVariables, rootID pack.ID, deployed map[string]string) *terminal.Table {
+	defaults := make(map[string]string)
+	for pID, packVars := range parsedVars.GetVars() {
+		for vID, v := range packVars {
+			name := strings.TrimPrefix(pID.Join(pack.ID(vID)).String(), rootID.String()+".")
+			if v.Default.IsNull() {
+				defaults[name] = "(required)"
+			} else {
+				defaults[name] = variables.FormatValue(v.Default)
+			}
+		}
+	}
+
+	table := terminal.NewTable("Variable", "Default", "Deployed")
+	for _, name := range slices.Sorted(maps.Keys(defaults)) {
+		value, ok := deployed[name]
+		if !ok {
+			value = "(not recorded)"
+		}
+		if value == defaults[name] {
+			continue
+		}
+		table.Rows = append(table.Rows, []string{name, defaults[name], value})
+	}
+	return table
+}
+
+// formatExampleRun returns a run command for the pack, which sets each
+// required variable to a placeholder naming its type. Variables are named
+// relative to the root pack and ordered by name, and each is set on its own
+// line so the command can be copied and filled in.
+func formatExampleRun(cfg *cache.PackConfig, parsedVars *parser.ParsedVariables, rootID pack.ID) string {
+	args := []string{"nomad-pack run " + cfg.Name}
+	if cfg.SourcePath != "" {
+		// Packs loaded from a directory are run using the same path.
+		args[0] = "nomad-pack run " + cfg.SourcePath
+	} else {
+		if cfg.Registry != "" && cfg.Registry != cache.DefaultRegistryName {
+			args = append(args, "--registry="+cfg.Registry)
+		}
+		if cfg.Ref != "" && cfg.Ref != cache.DefaultRef {
+			args = append(args, "--ref="+cfg.Ref)
+		}
+	}
+
+	placeholders := make(map[string]string)
+	for pID, packVars := range parsedVars.GetVars() {
+		for vID, v := range packVars {
+			if !v.Default.IsNull() {
+				continue
+			}
+			placeholder := "value"
+			if v.Type != cty.NilType && v.Type != cty.DynamicPseudoType {
+				placeholder = v.Type.FriendlyName()
+			}
+			name := strings.TrimPrefix(pID.Join(pack.ID(vID)).String(), rootID.String()+".")
+			placeholders[name] = placeholder
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(placeholders)) {
+		// The placeholder is quoted, since the shell would otherwise treat
+		// the angle brackets as a redirection.
+		args = append(args, fmt.Sprintf("--var '%s=<%s>'", name, placeholders[name]))
+	}
+	return strings.Join(args, " \\\n  ")
+}
+
+// infoPackVariables holds the formatted variables of a single pack for output
+// by the info command. The variables which are not in a group are held by
+// variables, and those in a group are held by groups.
+type infoPackVariables struct {
+	pack      string
+	variables []string
+	groups    []infoVariableGroup
+}
+
+// infoVariableGroup holds the formatted variables of a single group within a
+// pack.
+type infoVariableGroup struct {
+	name      string
+	variables []string
+}
+
+// infoVariables formats the parsed variables for output. Packs are ordered by
+// name, and the variables of each pack are sectioned by their group, ordered
+// by name, following the variables which are not in a group. The variables of
+// each section are ordered with the required variables first, followed by the
+// optional variables, each ordered by name. Variables overridden by the
+// environment, a variable file, or --var are marked with the source of their
+// value. When onlyDefaults is set, only the variables which use the default
+// shipped with the pack are included. When group is set, only the variables
+// in the group are included, without sectioning them.
+func infoVariables(parsedVars *parser.ParsedVariables, onlyDefaults bool, group string) []infoPackVariables {
+	vars := parsedVars.GetVars()
+
+	out := make([]infoPackVariables, 0, len(vars))
+	for _, pName := range slices.Sorted(maps.Keys(vars)) {
+		packVars := vars[pName]
+
+		// to output required variables first, keyed by the group of the
+		// variables
+		required := make(map[string][]string)
+		optional := make(map[string][]string)
+
+		for _, vName := range slices.Sorted(maps.Keys(packVars)) {
+			v := packVars[vName]
+
+			if onlyDefaults && (v.Default.IsNull() || v.HasProvidedDefault() || v.ValueSource != "") {
+				continue
 			}
 
+			section := v.Group
+			if group != "" {
+				if v.Group != group {
+					continue
+				}
+				section = ""
+			}
+
+			varType := variableTypeName(v)
+
+			var detail string
+			switch {
+			case v.Default.IsNull():
+				detail = "required"
+			case v.HasProvidedDefault():
+				detail = "optional, default from " + v.DefaultFrom
+			case v.DynamicDefault:
+				detail = "optional, default evaluated to " + variables.FormatValue(v.Default)
+			default:
+				detail = "optional"
+			}
+			if v.ValueSource != "" {
+				detail += ", overridden by " + v.ValueSource
+			}
+			if v.ValueFrom != "" {
+				detail += " from " + v.ValueFrom
+			}
+			if v.Sensitive {
+				detail += ", sensitive"
+			}
+			if v.Deprecated != "" {
+				detail += ", deprecated: " + v.Deprecated
+			}
+
+			row := fmt.Sprintf("- %q (%s: %s) - %s", v.Name, varType, detail, v.Description)
+			for _, validation := range v.Validations {
+				row += "\n" + formatInfoValidation(validation)
+			}
 			if v.Default.IsNull() {
-				required = append(required, fmt.Sprintf("\t- %q (%s: required) - %s", v.Name, varType, v.Description))
+				required[section] = append(required[section], row)
 			} else {
-				optional = append(optional, fmt.Sprintf("\t- %q (%s: optional) - %s", v.Name, varType, v.Description))
+				optional[section] = append(optional[section], row)
 			}
+		}
+
+		pv := infoPackVariables{
+			pack:      string(pName),
+			variables: append(required[""], optional[""]...),
+		}
+		sections := maps.Clone(required)
+		maps.Copy(sections, optional)
+		for _, name := range slices.Sorted(maps.Keys(sections)) {
+			if name == "" {
+				continue
+			}
+			pv.groups = append(pv.groups, infoVariableGroup{
+				name:      name,
+				variables: append(required[name], optional[name]...),
+			})
+		}
+		out = append(out, pv)
+	}
+	return out
+}
 
+// formatInfoValidation formats a validation of a variable as a line output
+// beneath the variable, indented to align with its name.
+func formatInfoValidation(validation *variables.Validation) string {
+	if validation.ConditionSource == "" {
+		return "  Validation: " + validation.ErrorMessage
+	}
+	return fmt.Sprintf("  Validation: %s - %s", validation.ConditionSource, validation.ErrorMessage)
+}
+
+// renderInfoDoc outputs the pack information using a glint document laid out
+// within width columns. The labels are padded to the longest label, so their
+// values are aligned.
+func renderInfoDoc(w io.Writer, width uint, p *pack.Pack, packVars []infoPackVariables) {
+	doc := glint.New()
+	doc.SetRenderer(&glint.TerminalRenderer{
+		Output: w,
+		Cols:   width,
+
+		// The document is rendered as a single frame, so the number of rows
+		// only needs to be non-zero.
+		Rows: 1,
+	})
+
+	// Labels are bold, unless color is disabled.
+	bold := func(c glint.Component) glint.Component {
+		if color.NoColor {
+			return c
 		}
+		return glint.Style(c, glint.Bold())
+	}
+
+	labels := [][2]string{
+		{"Pack Name", p.Metadata.Pack.Name},
+		{"Description", p.Metadata.Pack.Description},
+		{"Application URL", p.Metadata.App.URL},
+	}
 
-		for _, row := range append(required, optional...) {
-			doc.Append(glint.Layout(glint.Style(
-				glint.Text(row),
-			)).Row())
+	gap := 4
+	if width < infoNarrowWidth {
+		gap = 1
+	}
+	var labelWidth int
+	for _, l := range labels {
+		labelWidth = max(labelWidth, len(l[0]))
+	}
+
+	// The values are wrapped before laying them out, so any lines after the
+	// first remain aligned with the first, rather than the labels.
+	indent := labelWidth + gap
+	for _, l := range labels {
+		value := l[1]
+		if int(width) > indent {
+			value = wordwrap.WrapString(value, width-uint(indent))
+			value = strings.ReplaceAll(value, "\n", "\n"+strings.Repeat(" ", indent))
+		}
+		doc.Append(glint.Layout(
+			bold(glint.Text(fmt.Sprintf("%-*s", indent, l[0]))),
+			glint.Text(value),
+		).Row())
+	}
+
+	for _, pv := range packVars {
+		doc.Append(glint.Layout(
+			bold(glint.Text(fmt.Sprintf("Pack %q Variables:", pv.pack))),
+		).Row())
+
+		// The rows are indented using padding, rather than a tab, so they
+		// are wrapped within the width and remain indented.
+		for _, row := range pv.variables {
+			doc.Append(glint.Layout(glint.Text(row)).PaddingLeft(gap).Row())
+		}
+		for _, g := range pv.groups {
+			doc.Append(glint.Layout(
+				bold(glint.Text(fmt.Sprintf("Group %q:", g.name))),
+			).PaddingLeft(gap).Row())
+			for _, row := range g.variables {
+				doc.Append(glint.Layout(glint.Text(row)).PaddingLeft(2 * gap).Row())
+			}
 		}
-		glint.Text("\n")
 	}
 
 	doc.RenderFrame()
+}
+
+// infoWidth returns the number of columns the pretty output is laid out
+// within. The width passed using --width is used when set, followed by the
+// width of the terminal, or infoDefaultWidth if stdout is not a terminal.
+func infoWidth(stdout io.Writer, override int) uint {
+	if override > 0 {
+		return uint(override)
+	}
+	if f, ok := stdout.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		if cols, _, err := term.GetSize(int(f.Fd())); err == nil && cols > 0 {
+			return uint(cols)
+		}
+	}
+	return infoDefaultWidth
+}
+
+// formatInfoPlain formats the pack information as uncolored, left-aligned
+// text. The output is deterministic, so it is suitable for committing to a
+// repository, such as when generating documentation.
+func formatInfoPlain(p *pack.Pack, packVars []infoPackVariables) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Pack Name:       %s\n", p.Metadata.Pack.Name)
+	fmt.Fprintf(&b, "Description:     %s\n", p.Metadata.Pack.Description)
+	fmt.Fprintf(&b, "Application URL: %s\n", p.Metadata.App.URL)
+
+	// Any validations of a variable are output on the lines following it,
+	// which are indented in the same way.
+	for _, pv := range packVars {
+		fmt.Fprintf(&b, "\nPack %q Variables:\n", pv.pack)
+		for _, row := range pv.variables {
+			fmt.Fprintf(&b, "  %s\n", strings.ReplaceAll(row, "\n", "\n  "))
+		}
+		for _, g := range pv.groups {
+			fmt.Fprintf(&b, "  Group %q:\n", g.name)
+			for _, row := range g.variables {
+				fmt.Fprintf(&b, "    %s\n", strings.ReplaceAll(row, "\n", "\n    "))
+			}
+		}
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// formatDependencyGraph formats the dependency graph of the pack as a DOT
+// digraph. There is a node for the pack and each of its transitive
+// dependencies, identified by their path from the pack, and an edge from each
+// pack to its dependencies labeled with the ref of the dependency.
+func formatDependencyGraph(p *pack.Pack) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %q {\n", p.Name())
+	writeDependencyGraph(&b, p, p.ID())
+	b.WriteString("}")
+	return b.String()
+}
+
+func writeDependencyGraph(b *strings.Builder, p *pack.Pack, id pack.ID) {
+	label := p.Name()
+	if p.AliasOrName() != p.Name() {
+		label = fmt.Sprintf("%s (%s)", p.AliasOrName(), p.Name())
+	}
+	fmt.Fprintf(b, "  %q [label=%q];\n", id, label)
+
+	refs := make(map[pack.ID]string, len(p.Metadata.Dependencies))
+	for _, dep := range p.Metadata.Dependencies {
+		refs[dep.ID()] = dep.Ref
+		if dep.IsLatest() {
+			refs[dep.ID()] = "latest"
+		}
+	}
+
+	for _, dep := range p.Dependencies() {
+		depID := id.Join(dep.ID())
+		fmt.Fprintf(b, "  %q -> %q [label=%q];\n", id, depID, refs[dep.ID()])
+		writeDependencyGraph(b, dep, depID)
+	}
+}
+
+// outputReadme outputs the README of the pack. When the output supports
+// color, the markdown is rendered as styled text.
+func (c *InfoCommand) outputReadme(packPath string) int {
+	readme, err := readPackReadme(packPath)
+	if err != nil {
+		c.ui.ErrorWithContext(err, "failed to read pack README", "Pack Path: "+packPath)
+		return 1
+	}
+	if readme == "" {
+		c.ui.Warning("Pack does not contain a README")
+		return 0
+	}
+
+	if !color.NoColor {
+		readme = formatMarkdown(readme)
+	}
+
+	c.ui.Header("README")
+	c.ui.Output(readme)
+	return 0
+}
+
+// readPackReadme returns the contents of the README within the pack
+// directory. The file name is matched case-insensitively, with or without a
+// markdown extension. An empty string is returned if there is no README.
+func readPackReadme(packPath string) (string, error) {
+	entries, err := os.ReadDir(packPath)
+	if err != nil {
+		return "", err
+	}
+	for _, entry := range entries {
+		name := strings.ToLower(entry.Name())
+		if entry.IsDir() || (name != "readme.md" && name != "readme") {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(packPath, entry.Name()))
+		if err != nil {
+			return "", err
+		}
+		return string(content), nil
+	}
+	return "", nil
+}
+
+// renderOutputTemplate renders the output template of the pack against the
+// resolved variables and outputs the result.
+func (c *InfoCommand) renderOutputTemplate(p *pack.Pack, errorContext *errors.UIErrorContext) int {
+	if p.OutputTemplateFile == nil {
+		c.ui.Warning("Pack does not contain an output template")
+		return 0
+	}
+
+	packManager := generatePackManager(c.baseCommand, nil, c.packConfig)
+
+	output, wErr := packManager.RenderOutputTemplate(c.ignoreMissingVars)
+	if wErr != nil {
+		errorContext.Add(errors.UIContextPrefixPackName, packManager.PackName())
+		for i := range wErr {
+			wErr[i].Context.Append(errorContext)
+			c.ui.ErrorWithContext(wErr[i].Err, "failed to render output template", wErr[i].Context.GetAll()...)
+		}
+		return 1
+	}
+
+	c.ui.Header("Output Template")
+	c.ui.Output(output)
+	return 0
+}
+
+// outputVariableUsage cross-references the declared variables against the
+// pack templates and outputs whether each is used. When strict is set, the
+// command fails if any variable is unused.
+func (c *InfoCommand) outputVariableUsage(p *pack.Pack, parsedVars *parser.ParsedVariables, errorContext *errors.UIErrorContext) int {
+	if parsedVars.IsV1() {
+		c.ui.Error("--usage is only supported for packs using the v2 syntax")
+		return 1
+	}
+
+	usage, err := renderer.ScanVariableUsage(p)
+	if err != nil {
+		c.ui.ErrorWithContext(err, "failed to scan templates", errorContext.GetAll()...)
+		return 1
+	}
+
+	tbl, unused := formatVariableUsage(parsedVars, usage)
+	c.ui.Output("")
+	c.ui.Table(tbl)
+
+	if unused == 0 {
+		c.ui.Success("All variables are referenced by the pack templates")
+		return 0
+	}
+
+	msg := fmt.Sprintf("%d variables are not referenced by any template", unused)
+	if c.strict {
+		c.ui.Error(msg)
+		return 1
+	}
+	c.ui.Warning(msg)
+	return 0
+}
+
+// outputDumpVars writes the resolved variables as a variable file to stdout,
+// using JSON when requested and HCL otherwise, so it can be redirected to a
+// file and passed to a later run.
+func (c *InfoCommand) outputDumpVars(parsedVars *parser.ParsedVariables, parentID pack.ID, errorContext *errors.UIErrorContext) int {
+	if parsedVars.IsV1() {
+		c.ui.Error("--dump-vars is only supported for packs using the v2 syntax")
+		return 1
+	}
+
+	vars := dumpedVariables(parsedVars, parentID)
+	out := formatVarFileHCL(vars, c.showSensitive)
+	if c.output == infoOutputJSON {
+		var err error
+		if out, err = formatVarFileJSON(vars, c.showSensitive); err != nil {
+			c.ui.ErrorWithContext(err, "failed to format variables", errorContext.GetAll()...)
+			return 1
+		}
+	}
+
+	stdout, _, err := c.ui.OutputWriters()
+	if err != nil {
+		c.ui.ErrorWithContext(err, "failed to output variables", errorContext.GetAll()...)
+		return 1
+	}
+	if _, err := io.WriteString(stdout, out); err != nil {
+		c.ui.ErrorWithContext(err, "failed to output variables", errorContext.GetAll()...)
+		return 1
+	}
+	return 0
+}
+
+// outputInfoWithStatus writes the pack information as JSON to stdout, along
+// with the variables of the pack and the status of its deployed jobs, so both
+// are retrieved at the same time. The jobs of every deployment of the pack are
+// included unless --name is set.
+func (c *InfoCommand) outputInfoWithStatus(p *pack.Pack, parsedVars *parser.ParsedVariables, errorContext *errors.UIErrorContext) int {
+	client, err := c.getAPIClient()
+	if err != nil {
+		c.ui.ErrorWithContext(err, "failed to initialize client", errorContext.GetAll()...)
+		return 1
+	}
+
+	packJobs, jobErrs, err := getDeployedPackJobs(client, c.packConfig, c.deploymentName, newPackMetaKeys(defaultPackMetaPrefix), c.failFast)
+	if err != nil {
+		c.ui.ErrorWithContext(err, "error retrieving jobs", errorContext.GetAll()...)
+		return 1
+	}
+
+	status := newStatusSummary(c.packConfig.Name, c.deploymentName, packJobs, jobErrs)
+	return c.outputInfoJSON(p, packInfoVariables(parsedVars), status, errorContext)
+}
+
+// outputInfoJSON writes the pack information as JSON to stdout. The variables
+// and status are only included when set.
+func (c *InfoCommand) outputInfoJSON(p *pack.Pack, variables []packInfoVariable, status *statusSummary, errorContext *errors.UIErrorContext) int {
+	out, err := formatInfoJSON(p.Metadata, variables, status)
+	if err != nil {
+		c.ui.ErrorWithContext(err, "failed to format pack info", errorContext.GetAll()...)
+		return 1
+	}
+
+	stdout, _, err := c.ui.OutputWriters()
+	if err != nil {
+		c.ui.ErrorWithContext(err, "failed to output pack info", errorContext.GetAll()...)
+		return 1
+	}
+	if _, err := io.WriteString(stdout, out); err != nil {
+		c.ui.ErrorWithContext(err, "failed to output pack info", errorContext.GetAll()...)
+		return 1
+	}
+	return 0
+}
+
+// outputInfoYAML writes the pack metadata and every parsed variable as YAML to
+// stdout.
+func (c *InfoCommand) outputInfoYAML(p *pack.Pack, parsedVars *parser.ParsedVariables, errorContext *errors.UIErrorContext) int {
+	out, err := formatInfoYAML(p.Metadata, parsedVars)
+	if err != nil {
+		c.ui.ErrorWithContext(err, "failed to format pack info", errorContext.GetAll()...)
+		return 1
+	}
+
+	stdout, _, err := c.ui.OutputWriters()
+	if err != nil {
+		c.ui.ErrorWithContext(err, "failed to output pack info", errorContext.GetAll()...)
+		return 1
+	}
+	if _, err := io.WriteString(stdout, out); err != nil {
+		c.ui.ErrorWithContext(err, "failed to output pack info", errorContext.GetAll()...)
+		return 1
+	}
 	return 0
 }
 
+// outputDefaultValidations evaluates the validations of each variable against
+// its default and outputs those which fail. The command fails if any default
+// is invalid, as the pack cannot be run without overriding it.
+func (c *InfoCommand) outputDefaultValidations(parsedVars *parser.ParsedVariables) int {
+	tbl, checked := formatDefaultValidations(parsedVars)
+	c.ui.Output("")
+
+	if len(tbl.Rows) == 0 {
+		c.ui.Success(fmt.Sprintf("All %d validated variable defaults satisfy their validations", checked))
+		return 0
+	}
+
+	c.ui.Table(tbl)
+	c.ui.Error(fmt.Sprintf("%d variable defaults do not satisfy their validations", len(tbl.Rows)))
+	return 1
+}
+
+// formatDefaultValidations returns a table of the validations which fail for
+// the default of their variable, ordered by pack and variable name, along with
+// the number of variables checked. Variables without a default or without any
+// validations are not checked.
+func formatDefaultValidations(parsedVars *parser.ParsedVariables) (*terminal.Table, int) {
+	var checked int
+	tbl := terminal.NewTable("Pack", "Variable", "Location", "Error")
+
+	vars := parsedVars.GetVars()
+	for _, pID := range slices.Sorted(maps.Keys(vars)) {
+		for _, vID := range slices.Sorted(maps.Keys(vars[pID])) {
+			v := vars[pID][vID]
+			if v.Default.IsNull() || len(v.Validations) == 0 {
+				continue
+			}
+			checked++
+
+			for _, diag := range decoder.CheckValidations(v, v.Default) {
+				var location string
+				if diag.Subject != nil {
+					location = fmt.Sprintf("%s:%d", diag.Subject.Filename, diag.Subject.Start.Line)
+				}
+				tbl.Rows = append(tbl.Rows, []string{pID.String(), string(vID), location, diag.Detail})
+			}
+		}
+	}
+	return tbl, checked
+}
+
+// formatVariableUsage returns a table of whether each declared variable is
+// referenced by the pack templates, ordered by pack and variable name, along
+// with the number of unused variables. The variables of packs which access
+// them dynamically cannot be checked, and are not counted as unused.
+func formatVariableUsage(parsedVars *parser.ParsedVariables, usage *renderer.VariableUsage) (*terminal.Table, int) {
+	var unused int
+	tbl := terminal.NewTable("Pack", "Variable", "Usage")
+
+	vars := parsedVars.GetVars()
+	for _, pID := range slices.Sorted(maps.Keys(vars)) {
+		for _, vID := range slices.Sorted(maps.Keys(vars[pID])) {
+			var result string
+			switch {
+			case usage.Dynamic(pID):
+				result = "unknown, variables accessed dynamically"
+			case usage.Used(pID, vID):
+				result = "used"
+			default:
+				result = color.RedString("unused")
+				unused++
+			}
+			tbl.Rows = append(tbl.Rows, []string{pID.String(), vID.String(), result})
+		}
+	}
+	return tbl, unused
+}
+
+// renderPackJobs renders the pack templates against the resolved variables
+// and parses the rendered jobs, ordered by template name.
+func (c *InfoCommand) renderPackJobs(errorContext *errors.UIErrorContext) ([]*api.Job, int) {
+	packManager := generatePackManager(c.baseCommand, nil, c.packConfig)
+
+	r, wErr := packManager.ProcessTemplates(false, false, c.ignoreMissingVars)
+	if wErr != nil {
+		errorContext.Add(errors.UIContextPrefixPackName, packManager.PackName())
+		for i := range wErr {
+			wErr[i].Context.Append(errorContext)
+			c.ui.ErrorWithContext(wErr[i].Err, "failed to render pack", wErr[i].Context.GetAll()...)
+		}
+		return nil, 1
+	}
+
+	templates := make(map[string]string, r.LenDependentRenders()+r.LenParentRenders())
+	maps.Copy(templates, r.DependentRenders())
+	maps.Copy(templates, r.ParentRenders())
+
+	jobs := make([]*api.Job, 0, len(templates))
+	for _, tplName := range slices.Sorted(maps.Keys(templates)) {
+		job, err := jobspec2.ParseWithConfig(&jobspec2.ParseConfig{
+			Path: tplName,
+			Body: []byte(templates[tplName]),
+		})
+		if err != nil {
+			tplErrorContext := errorContext.Copy()
+			tplErrorContext.Add(errors.UIContextPrefixTemplateName, tplName)
+			c.ui.ErrorWithContext(err, "failed to parse job specification", tplErrorContext.GetAll()...)
+			return nil, 1
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, 0
+}
+
+// outputResources outputs the total resources requested by the rendered jobs.
+func (c *InfoCommand) outputResources(jobs []*api.Job) {
+	total := sumJobResources(jobs)
+
+	c.ui.Header("Resource Requests")
+	c.ui.NamedValues([]terminal.NamedValue{
+		{Name: "Jobs", Value: len(jobs)},
+		{Name: "CPU (MHz)", Value: total.cpu},
+		{Name: "Memory (MB)", Value: total.memoryMB},
+		{Name: "Disk (MB)", Value: total.diskMB},
+	})
+}
+
+// outputPlanSummary outputs the number of jobs, task groups, and tasks the
+// rendered jobs create, along with the IDs of the jobs.
+func (c *InfoCommand) outputPlanSummary(jobs []*api.Job) {
+	summary := summarizeJobs(jobs)
+
+	c.ui.Header("Plan Summary")
+	c.ui.NamedValues([]terminal.NamedValue{
+		{Name: "Jobs", Value: len(summary.jobIDs)},
+		{Name: "Task Groups", Value: summary.groups},
+		{Name: "Tasks", Value: summary.tasks},
+		{Name: "Job IDs", Value: strings.Join(summary.jobIDs, ", ")},
+	})
+}
+
+// outputConstraints outputs the distinct constraints and affinities of the
+// rendered jobs, along with where each is set.
+func (c *InfoCommand) outputConstraints(jobs []*api.Job) {
+	c.ui.Header("Constraints")
+	tbl := formatJobConstraints(jobs)
+	if len(tbl.Rows) == 0 {
+		c.ui.Output("The pack jobs do not set any constraints or affinities")
+		return
+	}
+	c.ui.Table(tbl)
+}
+
+// jobPlacementRule is a constraint or affinity, which is used to group the
+// places the same rule is set.
+type jobPlacementRule struct {
+	kind      string
+	attribute string
+	operator  string
+	value     string
+	weight    string
+}
+
+// formatJobConstraints returns a table of the distinct constraints and
+// affinities set by the jobs, their task groups, and tasks, ordered by kind,
+// attribute, operator, and value. Each rule lists where it is set, named by
+// the job, task group, and task IDs joined with dots.
+func formatJobConstraints(jobs []*api.Job) *terminal.Table {
+	// The Nomad defaults are used when the operator or weight is not set, so
+	// the same rules are grouped regardless of whether the jobs have been
+	// canonicalized.
+	operator := func(op string) string {
+		if op == "" {
+			return "="
+		}
+		return op
+	}
+
+	scopes := make(map[jobPlacementRule][]string)
+	add := func(scope string, constraints []*api.Constraint, affinities []*api.Affinity) {
+		for _, con := range constraints {
+			rule := jobPlacementRule{kind: "constraint", attribute: con.LTarget, operator: operator(con.Operand), value: con.RTarget}
+			scopes[rule] = append(scopes[rule], scope)
+		}
+		for _, aff := range affinities {
+			weight := 50
+			if aff.Weight != nil {
+				weight = int(*aff.Weight)
+			}
+			rule := jobPlacementRule{kind: "affinity", attribute: aff.LTarget, operator: operator(aff.Operand), value: aff.RTarget, weight: strconv.Itoa(weight)}
+			scopes[rule] = append(scopes[rule], scope)
+		}
+	}
+
+	for _, job := range jobs {
+		jobID := *job.ID
+		add(jobID, job.Constraints, job.Affinities)
+		for _, tg := range job.TaskGroups {
+			groupID := jobID + "." + *tg.Name
+			add(groupID, tg.Constraints, tg.Affinities)
+			for _, task := range tg.Tasks {
+				add(groupID+"."+task.Name, task.Constraints, task.Affinities)
+			}
+		}
+	}
+
+	rules := slices.SortedFunc(maps.Keys(scopes), func(a, b jobPlacementRule) int {
+		return cmp.Or(
+			// Constraints are output before affinities, as they must be met.
+			cmp.Compare(b.kind, a.kind),
+			cmp.Compare(a.attribute, b.attribute),
+			cmp.Compare(a.operator, b.operator),
+			cmp.Compare(a.value, b.value),
+			cmp.Compare(a.weight, b.weight),
+		)
+	})
+
+	tbl := terminal.NewTable("Kind", "Attribute", "Operator", "Value", "Weight", "Set On")
+	for _, rule := range rules {
+		tbl.Rows = append(tbl.Rows, []string{
+			rule.kind, rule.attribute, rule.operator, rule.value, rule.weight,
+			strings.Join(scopes[rule], ", "),
+		})
+	}
+	return tbl
+}
+
+// jobPlanSummary holds the number of task groups and tasks created by one or
+// more jobs, along with the IDs of the jobs.
+type jobPlanSummary struct {
+	jobIDs []string
+	groups int
+	tasks  int
+}
+
+// summarizeJobs returns the IDs of the jobs, in order, and the number of task
+// groups and tasks they contain. Task groups are counted once, regardless of
+// their count, as each is a single group of the job.
+func summarizeJobs(jobs []*api.Job) jobPlanSummary {
+	summary := jobPlanSummary{jobIDs: make([]string, 0, len(jobs))}
+	for _, job := range jobs {
+		summary.jobIDs = append(summary.jobIDs, *job.ID)
+		summary.groups += len(job.TaskGroups)
+		for _, tg := range job.TaskGroups {
+			summary.tasks += len(tg.Tasks)
+		}
+	}
+	return summary
+}
+
+// jobResources holds the resources requested by one or more jobs.
+type jobResources struct {
+	cpu      int
+	memoryMB int
+	diskMB   int
+}
+
+// sumJobResources returns the total resources requested by the jobs. The
+// resources of each task group are multiplied by its count, and the Nomad
+// defaults are used for resources which are not set.
+func sumJobResources(jobs []*api.Job) jobResources {
+	var total jobResources
+
+	for _, job := range jobs {
+		job.Canonicalize()
+
+		for _, tg := range job.TaskGroups {
+			count := *tg.Count
+
+			var group jobResources
+			for _, task := range tg.Tasks {
+				group.cpu += *task.Resources.CPU
+				group.memoryMB += *task.Resources.MemoryMB
+			}
+			if tg.EphemeralDisk != nil && tg.EphemeralDisk.SizeMB != nil {
+				group.diskMB = *tg.EphemeralDisk.SizeMB
+			}
+
+			total.cpu += group.cpu * count
+			total.memoryMB += group.memoryMB * count
+			total.diskMB += group.diskMB * count
+		}
+	}
+
+	return total
+}
+
 func (c *InfoCommand) Flags() *flag.Sets {
-	return c.flagSet(flagSetOperation, func(set *flag.Sets) {
+	return c.flagSet(flagSetOperation|flagSetNomadClient, func(set *flag.Sets) {
 		c.packConfig = &cache.PackConfig{}
 
 		f := set.NewSet("Render Options")
@@ -149,6 +1246,181 @@ func (c *InfoCommand) Flags() *flag.Sets {
 
 					Using ref with a file path is not supported.`,
 		})
+
+		f.EnumSingleVar(&flag.EnumSingleVar{
+			Name:    "output",
+			Target:  &c.output,
+			Aliases: []string{"format"},
+			Values:  []string{infoOutputPretty, infoOutputPlain, infoOutputDot, infoOutputJSON, infoOutputYAML},
+			Default: infoOutputPretty,
+			Usage: `Format used to output the pack information. The plain
+					format outputs deterministic, uncolored text, which is
+					suitable for generating documentation. The dot format
+					outputs the dependency graph of the pack in the Graphviz
+					DOT language instead. The json format outputs the pack
+					metadata, including any custom attributes of the pack
+					block, as JSON, or the variables as a JSON variable file
+					when used with --dump-vars. The yaml format outputs the
+					pack metadata and every variable, with its type and
+					default, as YAML.`,
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "dump-vars",
+			Target:  &c.dumpVars,
+			Default: false,
+			Usage: `Output the variables resolved from the defaults and any
+					overrides as a variable file, instead of the pack
+					information, so they can be passed to a later run using
+					--var-file. The file is written as HCL, or as JSON with
+					--output=json. Sensitive variables are redacted.`,
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "show-sensitive",
+			Target:  &c.showSensitive,
+			Default: false,
+			Usage: `Output the values of sensitive variables, such as those
+					resolved from Vault, rather than redacting them. Must be
+					used with --dump-vars.`,
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "with-status",
+			Target:  &c.withStatus,
+			Default: false,
+			Usage: `Include the variables of the pack, and the status of its
+					deployed jobs, in the output of --output=json, so both
+					can be retrieved as a single document. The jobs of every
+					deployment of the pack are included unless --name is
+					set.`,
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "readme",
+			Target:  &c.readme,
+			Default: false,
+			Usage: `Output the README of the pack after its metadata. When the
+					output supports color, the markdown is rendered as styled
+					text.`,
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "example-run",
+			Target:  &c.exampleRun,
+			Default: false,
+			Usage: `Output an example run command for the pack, which sets each
+					required variable to a placeholder naming its type, ready
+					to be copied and filled in.`,
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "only-registry-defaults",
+			Target:  &c.onlyRegistryDefaults,
+			Default: false,
+			Usage: `Only display the variables which use the default shipped
+					with the pack, rather than being overridden by the passed
+					--var and --var-file flags or the environment.`,
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:    "group",
+			Target:  &c.group,
+			Default: "",
+			Usage: `Only display the variables in the named group, as set by
+					the group attribute of the variable definitions. If not
+					specified, the variables are sectioned by their group.`,
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "diff-deployed",
+			Target:  &c.diffDeployed,
+			Default: false,
+			Usage: `Compare the variable defaults of the pack with the values
+					the deployed pack was rendered with, and display the
+					variables which differ. The deployment is selected with
+					--name. Only packs deployed by this version of
+					nomad-pack record their variables.`,
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "usage",
+			Target:  &c.usage,
+			Default: false,
+			Usage: `Cross-reference the declared variables against the var and
+					must_var calls within the pack templates, and display the
+					variables which no template references. Variables of a
+					pack accessed dynamically, such as using vars, cannot be
+					checked.`,
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "strict",
+			Target:  &c.strict,
+			Default: false,
+			Usage: `Fail when --usage finds any variables which no template
+					references. Must be used with --usage.`,
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "check-defaults",
+			Target:  &c.checkDefaults,
+			Default: false,
+			Usage: `Check the default of each variable against the validation
+					blocks of the variable, and display the validations which
+					fail. The command fails if any default is invalid.`,
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "resources",
+			Target:  &c.resources,
+			Default: false,
+			Usage: `Render the pack against the resolved variables and display
+					the total CPU, memory, and disk requested by its jobs. The
+					resources of each task group are multiplied by its count.`,
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "plan-summary",
+			Target:  &c.planSummary,
+			Default: false,
+			Usage: `Render the pack against the resolved variables and display
+					the number of jobs, task groups, and tasks it creates,
+					along with the IDs of the jobs. This gives a sense of the
+					footprint of the pack without planning it against the
+					cluster.`,
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "constraints",
+			Target:  &c.constraints,
+			Default: false,
+			Usage: `Render the pack against the resolved variables and display
+					the distinct constraints and affinities set by its jobs,
+					task groups, and tasks, along with where each is set. This
+					helps confirm the pack can be placed on the cluster.`,
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "render-outputs",
+			Target:  &c.renderOutputs,
+			Default: false,
+			Usage: `Render the pack's output template against the resolved
+					variables and display the result. This allows previewing
+					the message displayed after the pack is deployed.`,
+		})
+
+		f.IntVar(&flag.IntVar{
+			Name:    "width",
+			Target:  &c.width,
+			Default: 0,
+			Usage: `Number of columns the pack information is laid out within,
+					which makes the output reproducible. If not specified, the
+					width of the terminal is used, or 120 columns when the
+					output is not a terminal.`,
+		})
+
+		noCacheFlag(f, &c.noCache)
 	})
 }
 
@@ -156,6 +1428,66 @@ func (c *InfoCommand) Help() string {
 	c.Example = `
 	# Get information on the "hello_world" pack
 	nomad-pack info hello_world
+
+	# Get information on the "hello_world" pack along with its README
+	nomad-pack info hello_world --readme
+
+	# Get a run command for the "hello_world" pack with a placeholder for each
+	# required variable
+	nomad-pack info hello_world --example-run
+
+	# Get the metadata of the "hello_world" pack, including its custom
+	# attributes, as JSON
+	nomad-pack info hello_world --format=json
+
+	# Get the metadata and variables of the "hello_world" pack as YAML
+	nomad-pack info hello_world --format=yaml
+
+	# Get the metadata and variables of the "hello_world" pack, along with the
+	# status of its jobs deployed as "dev", as a single JSON document
+	nomad-pack info hello_world --format=json --with-status --name=dev
+
+	# Render the dependency graph of the "hello_world" pack as an image
+	nomad-pack info hello_world --format=dot | dot -Tpng -o hello_world.png
+
+	# Get information on a local pack within a .tar.gz or .zip archive
+	nomad-pack info ./hello_world-1.0.0.tar.gz
+
+	# Get information on the "hello_world" pack laid out within 80 columns
+	nomad-pack info hello_world --width=80
+
+	# Show the variables of the "hello_world" pack not overridden by a file
+	nomad-pack info hello_world --only-registry-defaults --var-file=./overrides.hcl
+
+	# Show the variables of the "hello_world" pack in the "networking" group
+	nomad-pack info hello_world --group=networking
+
+	# Find the variables of the "hello_world" pack no template references
+	nomad-pack info hello_world --usage --strict
+
+	# Check the variable defaults of the "hello_world" pack satisfy their own
+	# validations
+	nomad-pack info hello_world --check-defaults
+
+	# Save the variables the "hello_world" pack resolves with the overrides
+	# of a profile, to pass to a later run
+	nomad-pack info hello_world --profile=prod --dump-vars > vars.hcl
+
+	# Show the total resources requested by the "hello_world" pack
+	nomad-pack info hello_world --resources --var count=3
+
+	# Show the jobs, task groups, and tasks the "hello_world" pack creates
+	nomad-pack info hello_world --plan-summary
+
+	# Show the constraints and affinities of the "hello_world" pack jobs
+	nomad-pack info hello_world --constraints
+
+	# Show the variables of the "hello_world" pack deployed as "dev" which
+	# differ from the pack defaults
+	nomad-pack info hello_world --diff-deployed --name=dev
+
+	# Preview the output template of the "hello_world" pack
+	nomad-pack info hello_world --render-outputs --var greeting=hola
 	`
 
 	return formatHelp(`
@@ -0,0 +1,241 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+	"gopkg.in/yaml.v3"
+
+	"github.com/hashicorp/nomad-pack/internal/pkg/variable/parser"
+	"github.com/hashicorp/nomad-pack/sdk/pack"
+	"github.com/hashicorp/nomad-pack/sdk/pack/variables"
+	"github.com/hashicorp/nomad-pack/terminal"
+)
+
+// customMetadataValues returns the custom attributes of the pack block, which
+// have no dedicated output, as named values ordered by name.
+func customMetadataValues(md *pack.Metadata) []terminal.NamedValue {
+	if md == nil || md.Pack == nil {
+		return nil
+	}
+
+	var out []terminal.NamedValue
+	for _, name := range slices.Sorted(maps.Keys(md.Pack.Custom)) {
+		out = append(out, terminal.NamedValue{Name: name, Value: formatMetadataValue(md.Pack.Custom[name])})
+	}
+	return out
+}
+
+// formatInfoDependencies returns the table of the dependencies declared by
+// the pack metadata, in the order they are declared. It returns nil when the
+// pack has no dependencies.
+func formatInfoDependencies(md *pack.Metadata) *terminal.Table {
+	if md == nil || len(md.Dependencies) == 0 {
+		return nil
+	}
+
+	tbl := terminal.NewTable("Name", "Alias", "Source", "Ref")
+	for _, dep := range md.Dependencies {
+		tbl.Rows = append(tbl.Rows, []string{dep.Name, dep.Alias, dep.Source, dep.Ref})
+	}
+	return tbl
+}
+
+// formatMetadataValue formats a custom metadata value as text. Lists of
+// primitive values, such as tags, are joined using commas, while any other
+// value which is not primitive is formatted as HCL.
+func formatMetadataValue(v cty.Value) string {
+	if v.IsNull() || !v.IsWhollyKnown() {
+		return ""
+	}
+
+	if s, ok := metadataValueString(v); ok {
+		return s
+	}
+
+	ty := v.Type()
+	if ty.IsListType() || ty.IsSetType() || ty.IsTupleType() {
+		elems := make([]string, 0, v.LengthInt())
+		for it := v.ElementIterator(); it.Next(); {
+			_, ev := it.Element()
+			s, ok := metadataValueString(ev)
+			if !ok {
+				return string(hclwrite.TokensForValue(v).Bytes())
+			}
+			elems = append(elems, s)
+		}
+		return strings.Join(elems, ", ")
+	}
+	return string(hclwrite.TokensForValue(v).Bytes())
+}
+
+// metadataValueString returns the primitive value as a string. The returned
+// bool is false when the value is not primitive.
+func metadataValueString(v cty.Value) (string, bool) {
+	if !v.Type().IsPrimitiveType() || v.IsNull() {
+		return "", false
+	}
+	s, err := convert.Convert(v, cty.String)
+	if err != nil {
+		return "", false
+	}
+	return s.AsString(), true
+}
+
+// infoJSON is the pack information output by info --output=json. The
+// variables and status are only set when using --with-status.
+type infoJSON struct {
+	Metadata  map[string]any     `json:"metadata"`
+	Variables []packInfoVariable `json:"variables,omitempty"`
+	Status    *statusSummary     `json:"status,omitempty"`
+}
+
+// formatInfoJSON formats the pack information as JSON. The metadata includes
+// the custom attributes of the pack block alongside the known fields.
+func formatInfoJSON(md *pack.Metadata, variables []packInfoVariable, status *statusSummary) (string, error) {
+	metadata := md.ConvertToMapInterface()
+
+	packMeta := metadata["pack"].(map[string]any)
+	for name, v := range md.Pack.Custom {
+		b, err := ctyjson.Marshal(v, v.Type())
+		if err != nil {
+			return "", fmt.Errorf("failed to encode metadata %q: %w", name, err)
+		}
+		packMeta[name] = json.RawMessage(b)
+	}
+
+	b, err := json.MarshalIndent(infoJSON{Metadata: metadata, Variables: variables, Status: status}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(b) + "\n", nil
+}
+
+// infoYAML is the pack information output by info --output=yaml.
+type infoYAML struct {
+	Metadata  infoYAMLMetadata   `yaml:"metadata"`
+	Variables []infoYAMLVariable `yaml:"variables"`
+}
+
+type infoYAMLMetadata struct {
+	Name         string               `yaml:"name"`
+	Description  string               `yaml:"description"`
+	URL          string               `yaml:"url"`
+	Dependencies []infoYAMLDependency `yaml:"dependencies"`
+}
+
+// infoYAMLDependency is a dependency declared by the pack metadata, output by
+// info --output=yaml.
+type infoYAMLDependency struct {
+	Name   string `yaml:"name"`
+	Alias  string `yaml:"alias,omitempty"`
+	Source string `yaml:"source"`
+	Ref    string `yaml:"ref"`
+}
+
+// infoYAMLVariable is a single variable output by info --output=yaml. Default
+// holds the default converted to a native value, so it is written as YAML
+// rather than as HCL within a string.
+type infoYAMLVariable struct {
+	Pack        string `yaml:"pack"`
+	Name        string `yaml:"name"`
+	Type        string `yaml:"type"`
+	Required    bool   `yaml:"required"`
+	Description string `yaml:"description"`
+	Default     any    `yaml:"default,omitempty"`
+}
+
+// yamlInfoVariables returns the variables of the pack and its dependencies,
+// ordered by pack and then, as in the pretty output, with the required
+// variables before the optional ones, each ordered by name. The defaults of
+// sensitive variables are omitted.
+func yamlInfoVariables(parsedVars *parser.ParsedVariables) ([]infoYAMLVariable, error) {
+	var out []infoYAMLVariable
+
+	vars := parsedVars.GetVars()
+	for _, pID := range slices.Sorted(maps.Keys(vars)) {
+		ids := slices.SortedStableFunc(slices.Values(slices.Sorted(maps.Keys(vars[pID]))), func(a, b variables.ID) int {
+			return compareRequired(vars[pID][a].Default.IsNull(), vars[pID][b].Default.IsNull())
+		})
+
+		for _, vID := range ids {
+			v := vars[pID][vID]
+
+			info := infoYAMLVariable{
+				Pack:        pID.String(),
+				Name:        string(vID),
+				Type:        variableTypeName(v),
+				Description: strings.TrimSpace(v.Description),
+				Required:    v.Default.IsNull(),
+			}
+			if !info.Required && !v.Sensitive && v.Default.IsWhollyKnown() {
+				def, err := variables.ConvertCtyToInterface(v.Default)
+				if err != nil {
+					return nil, fmt.Errorf("failed to convert default of variable %q: %w", info.Name, err)
+				}
+				info.Default = def
+			}
+			out = append(out, info)
+		}
+	}
+	return out, nil
+}
+
+// compareRequired orders required variables before optional ones.
+func compareRequired(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// formatInfoYAML formats the pack metadata and variables as YAML.
+func formatInfoYAML(md *pack.Metadata, parsedVars *parser.ParsedVariables) (string, error) {
+	vars, err := yamlInfoVariables(parsedVars)
+	if err != nil {
+		return "", err
+	}
+
+	out := infoYAML{Variables: vars}
+	out.Metadata.Dependencies = []infoYAMLDependency{}
+	for _, dep := range md.Dependencies {
+		out.Metadata.Dependencies = append(out.Metadata.Dependencies, infoYAMLDependency{
+			Name:   dep.Name,
+			Alias:  dep.Alias,
+			Source: dep.Source,
+			Ref:    dep.Ref,
+		})
+	}
+	if md.Pack != nil {
+		out.Metadata.Name = md.Pack.Name
+		out.Metadata.Description = md.Pack.Description
+	}
+	if md.App != nil {
+		out.Metadata.URL = md.App.URL
+	}
+
+	var b strings.Builder
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(2)
+	if err := enc.Encode(out); err != nil {
+		return "", err
+	}
+	if err := enc.Close(); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
@@ -32,6 +32,9 @@ func (c *ListCommand) Run(args []string) int {
 		return 1
 	}
 
+	// Output any errors held back by --group-errors once the run completes.
+	defer c.flushErrors()
+
 	// Get the global cache dir - may be configurable in the future, so using this
 	// helper function rather than a direct reference to the CONST.
 	globalCache, err := cache.NewCache(&cache.CacheConfig{
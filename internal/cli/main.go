@@ -6,8 +6,12 @@ package cli
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"os"
+	"slices"
 	"sort"
+	"strings"
 	"text/tabwriter"
 
 	"github.com/mitchellh/cli"
@@ -42,6 +46,7 @@ var (
 		"registry add",
 		"registry delete",
 		"registry list",
+		"registry validate",
 	}
 
 	// Initialize hidden commands. Anything we add here will be ignored when
@@ -60,6 +65,21 @@ func Main(args []string) int {
 		args[1] = "--version"
 	}
 
+	// The --chdir option must be handled before anything else, so that all
+	// relative paths, such as local packs and variable files, are resolved
+	// from the new working directory.
+	dir, args, err := extractChdirOption(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error handling global --chdir option: %s\n", err)
+		return 1
+	}
+	if dir != "" {
+		if err := os.Chdir(dir); err != nil {
+			fmt.Fprintf(os.Stderr, "Error handling global --chdir option: %s\n", err)
+			return 1
+		}
+	}
+
 	// Build our cancellation context
 	ctx, closer := helper.WithInterrupt(context.Background())
 	defer closer()
@@ -100,9 +120,45 @@ func Main(args []string) int {
 		panic(err)
 	}
 
+	// Write the summary requested by --summary-fd, now the exit code is known.
+	base.writeSummary(exitCode)
+
 	return exitCode
 }
 
+// extractChdirOption removes the global --chdir option from the arguments,
+// returning its value along with the remaining arguments. The option is only
+// recognized before the command name, in the same way as other HashiCorp
+// CLIs. The arguments MUST include argv[0] as the program name.
+func extractChdirOption(args []string) (string, []string, error) {
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") {
+			// The command name has been reached.
+			break
+		}
+
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if name != "chdir" {
+			continue
+		}
+
+		rest := slices.Clone(args[:i])
+		if !hasValue {
+			if i+1 >= len(args) {
+				return "", nil, errors.New("must include a directory path")
+			}
+			value = args[i+1]
+			i++
+		}
+		if value == "" {
+			return "", nil, errors.New("must include a directory path")
+		}
+		return value, append(rest, args[i+1:]...), nil
+	}
+	return "", args, nil
+}
+
 // Commands returns the map of commands that can be used to initialize a CLI.
 func Commands(
 	ctx context.Context,
@@ -166,6 +222,11 @@ func Commands(
 				baseCommand: baseCommand,
 			}, nil
 		},
+		"verify-deployment": func() (cli.Command, error) {
+			return &VerifyDeploymentCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
 		"registry": func() (cli.Command, error) {
 			return &RegistryHelpCommand{
 				baseCommand: baseCommand,
@@ -186,6 +247,16 @@ func Commands(
 				baseCommand: baseCommand,
 			}, nil
 		},
+		"registry validate": func() (cli.Command, error) {
+			return &RegistryValidateCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+		"registry info-all": func() (cli.Command, error) {
+			return &RegistryInfoAllCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
 		"generate": func() (cli.Command, error) {
 			return &GenerateHelpCommand{
 				baseCommand: baseCommand,
@@ -282,7 +353,7 @@ func GroupedHelpFunc(f cli.HelpFunc) cli.HelpFunc {
 			glint.Text(" "),
 			glint.Text(cliName),
 			glint.Text(" "),
-			glint.Text("[--version] [--help] [--autocomplete-(un)install] <command> [args]"),
+			glint.Text("[--version] [--help] [--autocomplete-(un)install] [--chdir=<dir>] <command> [args]"),
 		).Row())
 		d.Append(glint.Text(""))
 
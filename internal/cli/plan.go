@@ -38,10 +38,14 @@ func (c *PlanCommand) Run(args []string) int {
 		return c.exitCodeError
 	}
 
+	// Output any errors held back by --group-errors once the run completes.
+	defer c.flushErrors()
+
 	c.packConfig.Name = c.args[0]
 
 	// Set the packConfig defaults if necessary and generate our UI error context.
 	errorContext := initPackCommand(c.packConfig)
+	defer c.packConfig.Cleanup()
 
 	// verify packs exist before planning jobs
 	if err := cache.VerifyPackExists(c.packConfig, errorContext, c.ui); err != nil {
@@ -115,6 +119,18 @@ func (c *PlanCommand) Run(args []string) int {
 		return c.exitCodeError
 	}
 
+	// Evaluate any validation rules declared by the pack against the rendered
+	// jobs. If any rule is not satisfied, output this and exit.
+	if md := packManager.Metadata(); md != nil {
+		if validationErrs := jobRunner.ValidateTemplates(md.Validations); validationErrs != nil {
+			for _, validationErr := range validationErrs {
+				validationErr.Context.Append(errorContext)
+				c.ui.ErrorWithContext(validationErr.Err, validationErr.Subject, validationErr.Context.GetAll()...)
+			}
+			return c.exitCodeError
+		}
+	}
+
 	if conflictErrs := jobRunner.CheckForConflicts(errorContext); conflictErrs != nil {
 		for _, conflictErr := range conflictErrs {
 			c.ui.ErrorWithContext(conflictErr.Err, conflictErr.Subject, conflictErr.Context.GetAll()...)
@@ -22,6 +22,7 @@ type RegistryAddCommand struct {
 	name   string
 	target string
 	ref    string
+	sha256 string
 }
 
 func (c *RegistryAddCommand) Run(args []string) int {
@@ -62,11 +63,12 @@ func (c *RegistryAddCommand) Run(args []string) int {
 		return 1
 	}
 
-	newRegistry, err := globalCache.Add(&cache.AddOpts{
+	newRegistry, err := globalCache.Add(c.Ctx, &cache.AddOpts{
 		RegistryName: c.name,
 		Source:       c.source,
 		PackName:     c.target,
 		Ref:          c.ref,
+		SHA256:       c.sha256,
 	})
 	if err != nil {
 		return 1
@@ -134,7 +136,17 @@ func (c *RegistryAddCommand) Flags() *flag.Sets {
 					specifying @latest, is destructive, and will overwrite
 					current @latest in the global cache.
 
-					Using ref with a file path is not supported.`,
+					Using ref with a file path is not supported. The ref is
+					ignored for tarball sources, which are pinned by their URL.`,
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:    "sha256",
+			Target:  &c.sha256,
+			Default: "",
+			Usage: `The expected SHA256 checksum of a tarball source. If set,
+					the downloaded tarball is verified against the checksum
+					before any packs are added to the cache.`,
 		})
 	})
 }
@@ -161,11 +173,15 @@ func (c *RegistryAddCommand) Help() string {
 
 	# Download packs from a registry at a specific tag/release/SHA.
 	nomad-pack registry add community github.com/hashicorp/nomad-pack-community-registry  --ref=v0.1.0
+
+	# Download a pack published as a tarball and verify its checksum.
+	nomad-pack registry add artifacts https://example.com/packs/mypack-1.2.tar.gz --sha256=<checksum>
 	`
 	return formatHelp(`
 	Usage: nomad-pack registry add <name> <source> [options]
 
-	Add nomad pack registries.
+	Add nomad pack registries. The source may be a git repository or an HTTP(S)
+	URL to a .tar.gz or .tgz tarball containing a registry or a single pack.
 
 ` + c.GetExample() + c.Flags().Help())
 }
@@ -0,0 +1,300 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/posener/complete"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/hashicorp/nomad-pack/internal/pkg/cache"
+	"github.com/hashicorp/nomad-pack/internal/pkg/flag"
+	"github.com/hashicorp/nomad-pack/internal/pkg/manager"
+	"github.com/hashicorp/nomad-pack/internal/pkg/variable/parser"
+	"github.com/hashicorp/nomad-pack/sdk/pack/variables"
+)
+
+// RegistryInfoAllCommand outputs the information of every pack within the
+// cached registries as a single JSON array, parsing the packs in parallel.
+type RegistryInfoAllCommand struct {
+	*baseCommand
+
+	// registry is the name of the registry whose packs are output, as passed
+	// using the --registry flag. If empty, the packs of every registry are
+	// output.
+	registry string
+
+	// ref is the ref of the packs which are output, as passed using the
+	// --ref flag. If empty, the packs at every ref are output.
+	ref string
+
+	// parallelism is the maximum number of packs parsed at the same time.
+	parallelism int
+}
+
+// packInfoDocument is the information of a single pack output by the
+// registry info-all command. A pack which could not be parsed has an error,
+// along with whatever information was available.
+type packInfoDocument struct {
+	Registry    string             `json:"registry"`
+	Name        string             `json:"name"`
+	Ref         string             `json:"ref"`
+	Version     string             `json:"version,omitempty"`
+	Description string             `json:"description,omitempty"`
+	URL         string             `json:"url,omitempty"`
+	Variables   []packInfoVariable `json:"variables,omitempty"`
+	Error       string             `json:"error,omitempty"`
+}
+
+// packInfoVariable is a single variable within a packInfoDocument.
+type packInfoVariable struct {
+	Pack        string `json:"pack"`
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required"`
+	Default     string `json:"default,omitempty"`
+	Group       string `json:"group,omitempty"`
+}
+
+func (c *RegistryInfoAllCommand) Run(args []string) int {
+	c.cmdKey = "registry info-all"
+
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithNoArgs(args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+		WithClient(false),
+	); err != nil {
+		c.ui.ErrorWithContext(err, ErrParsingArgsOrFlags)
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	if c.parallelism < 1 {
+		c.ui.Error("--parallelism must be at least 1")
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	globalCache, err := cache.NewCache(&cache.CacheConfig{
+		Path:   cache.DefaultCachePath(),
+		Logger: c.ui,
+	})
+	if err != nil {
+		return 1
+	}
+	if err = globalCache.Load(); err != nil {
+		return 1
+	}
+
+	var docs []packInfoDocument
+	var packDirs []string
+	for _, cachedRegistry := range globalCache.Registries() {
+		if c.registry != "" && cachedRegistry.Name != c.registry {
+			continue
+		}
+		for _, registryPack := range cachedRegistry.Packs {
+			if c.ref != "" && registryPack.Ref != c.ref {
+				continue
+			}
+			// Packs are named by their directory, which is the name used to
+			// run them, rather than the name within their metadata.
+			name, _, _ := strings.Cut(path.Base(registryPack.Path), "@")
+			docs = append(docs, packInfoDocument{
+				Registry: cachedRegistry.Name,
+				Name:     name,
+				Ref:      registryPack.Ref,
+			})
+			packDirs = append(packDirs, registryPack.Path)
+		}
+	}
+
+	if len(docs) == 0 {
+		if c.registry != "" {
+			c.ui.Error(fmt.Sprintf("No packs found in registry %q", c.registry))
+		} else {
+			c.ui.Error("No packs present in the cache.")
+		}
+		return 1
+	}
+
+	// The packs are parsed by a bounded pool of workers. Each worker only
+	// writes the document at the index it was sent, so the output retains
+	// the order of the registries and packs.
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range min(c.parallelism, len(docs)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				c.describePack(&docs[i], packDirs[i])
+			}
+		}()
+	}
+	for i := range docs {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	b, err := json.MarshalIndent(docs, "", "  ")
+	if err != nil {
+		c.ui.ErrorWithContext(err, "failed to encode pack information")
+		return 1
+	}
+	c.ui.Output(string(b))
+	return 0
+}
+
+// describePack loads the pack within packDir and parses its variables, adding
+// its metadata and variables to doc. Failing to load or parse the pack is
+// recorded as the error of doc, rather than failing the command.
+func (c *RegistryInfoAllCommand) describePack(doc *packInfoDocument, packDir string) {
+	pm := manager.NewPackManager(&manager.Config{
+		Path:                  packDir,
+		AllowUnsetVars:        true,
+		AllowExternalSymlinks: c.allowExternalSymlinks,
+	}, nil)
+
+	parsedVars, wErrs := pm.ProcessVariableFiles()
+	if len(wErrs) > 0 {
+		errs := make([]error, 0, len(wErrs))
+		for _, wErr := range wErrs {
+			errs = append(errs, wErr.Err)
+		}
+		doc.Error = errors.Join(errs...).Error()
+		return
+	}
+
+	if md := pm.Metadata(); md != nil {
+		if md.Pack != nil {
+			doc.Version = md.Pack.Version
+			doc.Description = md.Pack.Description
+		}
+		if md.App != nil {
+			doc.URL = md.App.URL
+		}
+	}
+	doc.Variables = packInfoVariables(parsedVars)
+}
+
+// packInfoVariables returns the variables of the pack and its dependencies,
+// ordered by pack and variable name.
+func packInfoVariables(parsedVars *parser.ParsedVariables) []packInfoVariable {
+	var out []packInfoVariable
+
+	vars := parsedVars.GetVars()
+	for _, pID := range slices.Sorted(maps.Keys(vars)) {
+		for _, vID := range slices.Sorted(maps.Keys(vars[pID])) {
+			v := vars[pID][vID]
+
+			info := packInfoVariable{
+				Pack:        pID.String(),
+				Name:        string(vID),
+				Type:        variableTypeName(v),
+				Description: strings.TrimSpace(v.Description),
+				Required:    v.Default.IsNull(),
+				Group:       v.Group,
+			}
+			if !info.Required {
+				info.Default = variables.FormatValue(v.Default)
+			}
+			out = append(out, info)
+		}
+	}
+	return out
+}
+
+// variableTypeName returns the friendly name of the type of the variable. When
+// the variable declares no type, the type of its default is used instead.
+func variableTypeName(v *variables.Variable) string {
+	switch {
+	case !v.Type.Equals(cty.NilType):
+		return v.Type.FriendlyName()
+	case !v.Default.IsNull():
+		return v.Default.Type().FriendlyName()
+	default:
+		return "unknown"
+	}
+}
+
+func (c *RegistryInfoAllCommand) Flags() *flag.Sets {
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Info Options")
+
+		f.StringVar(&flag.StringVar{
+			Name:    "registry",
+			Target:  &c.registry,
+			Default: "",
+			Usage: `Name of the registry whose packs are output. If not
+					specified, the packs of every registry are output.`,
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:    "ref",
+			Target:  &c.ref,
+			Default: "",
+			Usage: `Ref of the packs which are output. If not specified, the
+					packs at every ref are output.`,
+		})
+
+		f.IntVar(&flag.IntVar{
+			Name:    "parallelism",
+			Target:  &c.parallelism,
+			Default: 4,
+			Usage:   `Maximum number of packs parsed at the same time.`,
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "allow-external-symlinks",
+			Target:  &c.allowExternalSymlinks,
+			Default: false,
+			Usage: `Allow the packs to contain symlinks which resolve outside
+					of the pack directory.`,
+		})
+	})
+}
+
+func (c *RegistryInfoAllCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *RegistryInfoAllCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *RegistryInfoAllCommand) Synopsis() string {
+	return "Output the information of every pack within the registries as JSON."
+}
+
+func (c *RegistryInfoAllCommand) Help() string {
+	c.Example = `
+	# Output the information of every pack in the "community" registry
+	nomad-pack registry info-all --registry=community
+
+	# Output the information of every pack at the latest ref, parsing up to
+	# eight packs at a time
+	nomad-pack registry info-all --ref=latest --parallelism=8
+	`
+	return formatHelp(`
+	Usage: nomad-pack registry info-all [options]
+
+	Output the information of every pack within the cached registries as a
+	JSON array, such as to generate a catalog of the packs. The packs are
+	parsed in parallel. A pack which cannot be parsed is output with an error,
+	rather than failing the command.
+
+` + c.GetExample() + c.Flags().Help())
+}
@@ -0,0 +1,230 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/posener/complete"
+
+	"github.com/hashicorp/nomad-pack/internal/pkg/errors"
+	"github.com/hashicorp/nomad-pack/internal/pkg/flag"
+	"github.com/hashicorp/nomad-pack/internal/pkg/manager"
+	"github.com/hashicorp/nomad-pack/internal/pkg/variable/parser"
+	"github.com/hashicorp/nomad-pack/sdk/pack"
+	"github.com/hashicorp/nomad-pack/terminal"
+)
+
+// RegistryValidateCommand validates that every pack within a registry
+// directory can be loaded and its variables parsed.
+type RegistryValidateCommand struct {
+	*baseCommand
+
+	// strict is true when the user supplies the --strict flag and packs
+	// missing documentation should also fail validation.
+	strict bool
+}
+
+// packValidation is the result of validating a single pack.
+type packValidation struct {
+	path     string
+	problems []string
+}
+
+func (c *RegistryValidateCommand) Run(args []string) int {
+	c.cmdKey = "registry validate"
+
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithExactArgs(1, args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+		WithClient(false),
+	); err != nil {
+		c.ui.ErrorWithContext(err, ErrParsingArgsOrFlags)
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	dir := c.args[0]
+
+	packDirs, err := findPackDirs(dir)
+	if err != nil {
+		c.ui.ErrorWithContext(err, "failed to find packs", errors.UIContextPrefixPackPath+dir)
+		return 1
+	}
+	if len(packDirs) == 0 {
+		c.ui.Error(fmt.Sprintf("No packs found within %s", dir))
+		return 1
+	}
+
+	results := make([]packValidation, 0, len(packDirs))
+	for _, packDir := range packDirs {
+		results = append(results, packValidation{
+			path:     packDir,
+			problems: c.validatePack(packDir),
+		})
+	}
+
+	c.ui.Table(formatPackValidations(dir, results))
+
+	var failed int
+	for _, result := range results {
+		if len(result.problems) > 0 {
+			failed++
+		}
+	}
+	if failed > 0 {
+		c.ui.Error(fmt.Sprintf("%d of %d packs failed validation", failed, len(results)))
+		return 1
+	}
+
+	c.ui.Success(fmt.Sprintf("All %d packs are valid", len(results)))
+	return 0
+}
+
+// validatePack loads the pack, along with its dependencies, and parses its
+// variables, returning the problems found.
+func (c *RegistryValidateCommand) validatePack(packDir string) []string {
+	pm := manager.NewPackManager(&manager.Config{
+		Path:                  packDir,
+		AllowUnsetVars:        true,
+		AllowExternalSymlinks: c.allowExternalSymlinks,
+	}, nil)
+
+	var problems []string
+	parsedVars, wErrs := pm.ProcessVariableFiles()
+	for _, wErr := range wErrs {
+		problems = append(problems, wErr.Err.Error())
+	}
+	if len(problems) > 0 || !c.strict {
+		return problems
+	}
+
+	return strictPackProblems(packDir, pm.Metadata().Pack.Description, parsedVars)
+}
+
+// strictPackProblems returns the documentation missing from an otherwise valid
+// pack, which only fails validation when --strict is set.
+func strictPackProblems(packDir, description string, parsedVars *parser.ParsedVariables) []string {
+	var problems []string
+
+	if readme, err := readPackReadme(packDir); err != nil || readme == "" {
+		problems = append(problems, "missing README")
+	}
+	if description == "" {
+		problems = append(problems, "missing pack description")
+	}
+
+	vars := parsedVars.GetVars()
+	for _, pID := range slices.Sorted(maps.Keys(vars)) {
+		for _, vID := range slices.Sorted(maps.Keys(vars[pID])) {
+			if vars[pID][vID].Description == "" {
+				problems = append(problems, fmt.Sprintf("variable %q missing description", pID.Join(pack.ID(vID))))
+			}
+		}
+	}
+	return problems
+}
+
+// findPackDirs returns the directories containing a pack within dir, in
+// lexical order. The directories within a pack, such as its dependencies, are
+// not searched, since they are validated along with the pack.
+func findPackDirs(dir string) ([]string, error) {
+	var out []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != dir && strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
+		if _, err := os.Stat(filepath.Join(path, "metadata.hcl")); err == nil {
+			out = append(out, path)
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	return out, err
+}
+
+// formatPackValidations returns a table of the validation results, with the
+// packs named by their path relative to the registry directory.
+func formatPackValidations(dir string, results []packValidation) *terminal.Table {
+	tbl := terminal.NewTable("Pack", "Status", "Problems")
+	for _, result := range results {
+		name, err := filepath.Rel(dir, result.path)
+		if err != nil || name == "." {
+			name = result.path
+		}
+
+		status := "valid"
+		if len(result.problems) > 0 {
+			status = "invalid"
+		}
+		tbl.Rows = append(tbl.Rows, []string{name, status, strings.Join(result.problems, "; ")})
+	}
+	return tbl
+}
+
+func (c *RegistryValidateCommand) Flags() *flag.Sets {
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Validate Options")
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "strict",
+			Target:  &c.strict,
+			Default: false,
+			Usage: `Also fail packs which are missing documentation: a README,
+					a pack description, or a description for any variable.`,
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "allow-external-symlinks",
+			Target:  &c.allowExternalSymlinks,
+			Default: false,
+			Usage: `Allow the packs to contain symlinks which resolve outside
+					of the pack directory.`,
+		})
+	})
+}
+
+func (c *RegistryValidateCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictDirs("")
+}
+
+func (c *RegistryValidateCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *RegistryValidateCommand) Synopsis() string {
+	return "Validate every pack within a registry directory."
+}
+
+func (c *RegistryValidateCommand) Help() string {
+	c.Example = `
+	# Validate every pack within a local registry before pushing it
+	nomad-pack registry validate ./my-registry
+
+	# Also require every pack and variable to be documented
+	nomad-pack registry validate ./my-registry --strict
+	`
+	return formatHelp(`
+	Usage: nomad-pack registry validate <dir> [options]
+
+	Validate that every pack within the directory can be loaded, along with its
+	dependencies, and that its variables can be parsed. The result of each pack
+	is output in a summary table, and the command fails if any pack is invalid.
+
+` + c.GetExample() + c.Flags().Help())
+}
@@ -259,10 +259,21 @@ func (c *RenderCommand) Run(args []string) int {
 		return 1
 	}
 
+	// Output any errors held back by --group-errors once the run completes.
+	defer c.flushErrors()
+
 	c.packConfig.Name = c.args[0]
 
+	cleanup, err := fetchEphemeralPack(c.baseCommand, c.packConfig)
+	if err != nil {
+		c.ui.ErrorWithContext(err, "failed to fetch pack")
+		return 1
+	}
+	defer cleanup()
+
 	// Set the packConfig defaults if necessary and generate our UI error context.
 	errorContext := initPackCommand(c.packConfig)
+	defer c.packConfig.Cleanup()
 
 	if err := cache.VerifyPackExists(c.packConfig, errorContext, c.ui); err != nil {
 		return 1
@@ -366,6 +377,8 @@ func (c *RenderCommand) Flags() *flag.Sets {
 					Using ref with a file path is not supported.`,
 		})
 
+		noCacheFlag(f, &c.noCache)
+
 		f.BoolVar(&flag.BoolVar{
 			Name:    "render-output-template",
 			Target:  &c.renderOutputTemplate,
@@ -35,6 +35,10 @@ func (c *RunCommand) Run(args []string) int {
 		c.ui.Info(c.helpUsageMessage())
 		return 1
 	}
+
+	// Output any errors held back by --group-errors once the run completes.
+	defer c.flushErrors()
+
 	return c.run()
 }
 
@@ -46,6 +50,7 @@ func (c *RunCommand) run() int {
 
 	// Set the packConfig defaults if necessary and generate our UI error context.
 	errorContext := initPackCommand(c.packConfig)
+	defer c.packConfig.Cleanup()
 
 	// verify packs exist before running jobs
 	err := cache.VerifyPackExists(c.packConfig, errorContext, c.ui)
@@ -92,6 +97,7 @@ func (c *RunCommand) run() int {
 		PackRef:        c.packConfig.Ref,
 		DeploymentName: c.deploymentName,
 		RegistryName:   c.packConfig.Registry,
+		Variables:      packManager.VariableValues(),
 	}
 
 	// TODO(jrasell) come up with a better way to pass the appropriate config.
@@ -129,6 +135,18 @@ func (c *RunCommand) run() int {
 		return 1
 	}
 
+	// Evaluate any validation rules declared by the pack against the rendered
+	// jobs. If any rule is not satisfied, output this and exit.
+	if md := packManager.Metadata(); md != nil {
+		if validationErrs := runDeployer.ValidateTemplates(md.Validations); validationErrs != nil {
+			for _, validationErr := range validationErrs {
+				validationErr.Context.Append(errorContext)
+				c.ui.ErrorWithContext(validationErr.Err, validationErr.Subject, validationErr.Context.GetAll()...)
+			}
+			return 1
+		}
+	}
+
 	if conflictErrs := runDeployer.CheckForConflicts(errorContext); conflictErrs != nil {
 		for _, conflictErr := range conflictErrs {
 			c.ui.ErrorWithContext(conflictErr.Err, conflictErr.Subject, conflictErr.Context.GetAll()...)
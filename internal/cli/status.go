@@ -4,7 +4,15 @@
 package cli
 
 import (
+	"cmp"
 	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/hashicorp/nomad/api"
@@ -13,12 +21,240 @@ import (
 	"github.com/hashicorp/nomad-pack/internal/pkg/cache"
 	"github.com/hashicorp/nomad-pack/internal/pkg/errors"
 	flag "github.com/hashicorp/nomad-pack/internal/pkg/flag"
+	"github.com/hashicorp/nomad-pack/internal/pkg/helper/pointer"
+	"github.com/hashicorp/nomad-pack/internal/runner"
+	"github.com/hashicorp/nomad-pack/internal/runner/job"
 	"github.com/hashicorp/nomad-pack/terminal"
 )
 
+const (
+	// statusSortJob outputs the jobs in the order they are listed by Nomad.
+	statusSortJob = "job"
+
+	// statusSortSeverity outputs the jobs ordered by the severity of their
+	// health, so that problems are output first.
+	statusSortSeverity = "severity"
+)
+
 type StatusCommand struct {
 	*baseCommand
 	packConfig *cache.PackConfig
+
+	// separator is output between the table columns instead of aligning them
+	// when set using the --separator flag.
+	separator string
+
+	// csv is true when the user supplies the --csv flag and the tables should
+	// be output as CSV, formatted using csvDialect.
+	csv bool
+
+	// csvDelimiter, csvAlwaysQuote, and csvCRLF are passed using the flags of
+	// the same name, and are parsed into csvDialect.
+	csvDelimiter   string
+	csvAlwaysQuote bool
+	csvCRLF        bool
+	csvDialect     terminal.CSVDialect
+
+	// markdown is true when the user supplies --format=markdown and the
+	// tables should be output as Markdown.
+	markdown bool
+
+	// stream is true when the user supplies the --stream flag and the table
+	// rows should be written as they are formatted, rather than buffered.
+	stream bool
+
+	// splitByRef is true when the user supplies the --split-by-ref flag and
+	// jobs should be separated by the pack ref they were deployed from.
+	splitByRef bool
+
+	// cached is true when the user supplies the --cached flag and the deployed
+	// packs should be read from the snapshot of the last query, when it is
+	// within cacheTTL.
+	cached bool
+
+	// refresh is true when the user supplies the --refresh flag and the
+	// deployed packs should be queried and the snapshot updated.
+	refresh bool
+
+	// tree is true when the user supplies the --tree flag and every deployed
+	// pack job should be output within a tree of the registries, packs, and
+	// deployments they belong to.
+	tree bool
+
+	// cacheTTL is the age after which the snapshot of the deployed packs is
+	// stale, and the cluster is queried again.
+	cacheTTL time.Duration
+
+	// sort is the order the jobs are output in, as passed using the --sort
+	// flag.
+	sort string
+
+	// severityRanking is the raw value of the --severity-ranking flag, which
+	// orders the health states from the most to the least severe.
+	severityRanking string
+
+	// ranking is the severity ranking parsed from severityRanking.
+	ranking severityRanking
+
+	// showVersion is true when the user supplies the --show-version flag and
+	// the version and modify index of each job should be output.
+	showVersion bool
+
+	// showAllocs is true when the user supplies the --allocs flag and the
+	// allocations of each job should be output.
+	showAllocs bool
+
+	// showScaling is true when the user supplies the --scaling flag and the
+	// scaling policy state of each job should be output.
+	showScaling bool
+
+	// showEvals is true when the user supplies the --show-evals flag and the
+	// latest evaluation of each job, along with any placement failures,
+	// should be output.
+	showEvals bool
+
+	// explainHealth is true when the user supplies the --explain-health flag
+	// and a description of why each job has its status should be output.
+	explainHealth bool
+
+	// showLogs is true when the user supplies the --logs flag and the stderr
+	// logs of the allocations of failed and dead jobs should be output.
+	showLogs bool
+
+	// tail is the number of log lines output for each task when showLogs is
+	// set.
+	tail int
+
+	// headerMap is the raw value of the --header-map flag, which renames the
+	// table column headers.
+	headerMap string
+
+	// columnHeaders are the new column headers parsed from headerMap, keyed by
+	// the default header of the column.
+	columnHeaders map[string]string
+
+	// watchEvents is true when the user supplies the --watch-events flag and
+	// the Nomad events of the pack jobs should be streamed until interrupted.
+	watchEvents bool
+
+	// jobID limits the output to the job of the pack with the ID passed using
+	// the --job flag.
+	jobID string
+
+	// follow is true when the user supplies the --follow flag and the status
+	// of the job passed using --job should be output each time it changes,
+	// until it is healthy or has failed.
+	follow bool
+
+	// repeat is the number of times the status of the pack is polled when set
+	// using the --repeat flag, after which the command exits with the last
+	// observed health of the pack.
+	repeat int
+
+	// interval is the time waited between each poll when using --repeat.
+	interval time.Duration
+
+	// health is the most severe health of the jobs output by the last call to
+	// renderDeployedPackJobs, which is reported when using --repeat.
+	health jobHealthState
+
+	// watch is true when the user supplies the --watch flag and the status of
+	// the pack should be output on an interval until every job is healthy.
+	watch bool
+
+	// watchInterval is the time waited between each poll when using --watch.
+	watchInterval time.Duration
+
+	// node limits the output to jobs with allocations on the client node with
+	// the passed ID, ID prefix, or name.
+	node string
+
+	// expectedRegistries maps each namespace to the registry its pack jobs
+	// are expected to be deployed from, as passed using the
+	// --expected-registry-map flag.
+	expectedRegistries map[string]string
+
+	// labelPrefix is the prefix of the metadata keys which identify the pack a
+	// deployed job belongs to, as passed using the --label-prefix flag.
+	labelPrefix string
+
+	// healthSummary is true when the user supplies the --health-summary flag
+	// and only a line with the number of healthy jobs of each pack should be
+	// output.
+	healthSummary bool
+
+	// failOnUnhealthy is true when the user supplies the --fail-on-unhealthy
+	// flag and the exit code should report the health of the pack jobs.
+	failOnUnhealthy bool
+
+	// failOnRegistryMismatch is true when the user supplies the
+	// --fail-on-registry-mismatch flag and the command should fail if any job
+	// was deployed from a registry other than the one expected for its
+	// namespace.
+	failOnRegistryMismatch bool
+
+	// limit is the maximum number of jobs output when set using the --limit
+	// flag. A limit of 0 outputs all jobs.
+	limit int
+
+	// pageToken continues the output from the position of the last job of a
+	// previous page, as returned when using --limit.
+	pageToken string
+
+	// hideGC is true when the user supplies the --hide-gc flag and dead jobs,
+	// which are pending garbage collection, should be omitted.
+	hideGC bool
+
+	// onlyGC is true when the user supplies the --only-gc flag and only dead
+	// jobs, which are pending garbage collection, should be output.
+	onlyGC bool
+
+	// meta limits the output to jobs whose Nomad job metadata matches all of
+	// the key=value pairs passed using the --meta flag.
+	meta map[string]string
+
+	// filterStatus limits the output to jobs with one of the statuses passed
+	// using the --filter-status flag.
+	filterStatus []string
+
+	// columns are the jobs table columns passed using the --columns flag,
+	// which are resolved to the column headers once the flags are parsed.
+	columns []string
+
+	// sinceIndex limits the output to jobs modified after the Nomad index
+	// passed using the --since-index flag. The jobs are listed using a
+	// blocking query, which waits until a job changes after the index.
+	sinceIndex uint64
+
+	// dryRun is true when the user supplies the --dry-run flag and the pack
+	// should be rendered to list the jobs it would create, along with those
+	// which already exist, rather than listing the deployed jobs.
+	dryRun bool
+
+	// syslog is true when the user supplies the --syslog flag and a JSON
+	// summary of the status should also be written to syslog.
+	syslog bool
+
+	// syslogAddress is the address of the remote syslog server, such as
+	// udp://logs.example.com:514. The local syslog server is used if empty.
+	syslogAddress string
+
+	// syslogFacility and syslogPriority are the names of the facility and
+	// severity the status summary is written to syslog with.
+	syslogFacility string
+	syslogPriority string
+
+	// format is the format used to output the status, such as a table in the
+	// terminal or an HTML report.
+	format string
+
+	// outputFile is the path the report is written to when set using the
+	// --output-file flag, instead of writing it to stdout.
+	outputFile string
+
+	// output is the output mode set using the --output flag, which matches the
+	// flag of the info command. The json output is the json report format.
+	output string
 }
 
 func (c *StatusCommand) Run(args []string) int {
@@ -34,10 +270,374 @@ func (c *StatusCommand) Run(args []string) int {
 		return 1
 	}
 
+	// Output any errors held back by --group-errors once the run completes.
+	defer c.flushErrors()
+
+	if c.output == statusFormatJSON {
+		if c.format != statusFormatTable && c.format != statusFormatJSON {
+			c.ui.Error(fmt.Sprintf("--output=json cannot be used with --format=%s", c.format))
+			c.ui.Info(c.helpUsageMessage())
+			return 1
+		}
+		c.format = statusFormatJSON
+	}
+
+	// The csv format outputs the same tables as the table format, so it is
+	// handled by --csv rather than as a report.
+	if c.format == statusFormatCSV {
+		c.format = statusFormatTable
+		c.csv = true
+	}
+
+	// The same applies to the markdown format, which is handled by the
+	// markdown field.
+	if c.format == statusFormatMarkdown {
+		c.format = statusFormatTable
+		c.markdown = true
+	}
+
+	if c.outputFile != "" && c.format == statusFormatTable {
+		c.ui.Error("--output-file can only be used with a report format, such as --format=html")
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	var err error
+	if c.columnHeaders, err = parseHeaderMap(c.headerMap); err != nil {
+		c.ui.ErrorWithContext(err, ErrParsingArgsOrFlags)
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	if c.showLogs && !c.showAllocs {
+		c.ui.Error("--logs can only be used with --allocs")
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	if c.tail < 1 {
+		c.ui.Error("--tail must be at least 1")
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	if c.watchEvents && len(c.args) == 0 {
+		c.ui.Error("--watch-events can only be used if pack name is provided")
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	if (c.format == statusFormatJUnit || c.format == statusFormatJSON || c.format == statusFormatInflux) && len(c.args) == 0 {
+		c.ui.Error(fmt.Sprintf("--format=%s can only be used if pack name is provided", c.format))
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	if c.sinceIndex > 0 && len(c.args) == 0 {
+		c.ui.Error("--since-index can only be used if pack name is provided")
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	if c.sinceIndex > 0 && (c.follow || c.repeat > 0 || c.watchEvents || c.dryRun) {
+		c.ui.Error("--since-index cannot be used with --follow, --repeat, --watch-events, or --dry-run")
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	if c.jobID != "" && len(c.args) == 0 {
+		c.ui.Error("--job can only be used if pack name is provided")
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	if c.follow && c.jobID == "" {
+		c.ui.Error("--follow can only be used with --job")
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	if c.follow && (c.watchEvents || c.dryRun || c.format != statusFormatTable) {
+		c.ui.Error("--follow cannot be used with --watch-events, --dry-run, or a report format")
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	if c.limit < 0 {
+		c.ui.Error("--limit must not be negative")
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	if (c.limit > 0 || c.pageToken != "") && len(c.args) == 0 {
+		c.ui.Error("--limit and --page-token can only be used if pack name is provided")
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	if len(c.expectedRegistries) > 0 && len(c.args) == 0 {
+		c.ui.Error("--expected-registry-map can only be used if pack name is provided")
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	if c.failOnRegistryMismatch && len(c.expectedRegistries) == 0 {
+		c.ui.Error("--fail-on-registry-mismatch can only be used with --expected-registry-map")
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	if c.repeat < 0 {
+		c.ui.Error("--repeat must not be negative")
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	if c.repeat > 0 && len(c.args) == 0 {
+		c.ui.Error("--repeat can only be used if pack name is provided")
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	if c.repeat > 0 && (c.follow || c.watchEvents || c.dryRun || c.format != statusFormatTable) {
+		c.ui.Error("--repeat cannot be used with --follow, --watch-events, --dry-run, or a report format")
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	if c.repeat > 0 && c.interval <= 0 {
+		c.ui.Error("--interval must be positive")
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	if c.watch && len(c.args) == 0 {
+		c.ui.Error("--watch can only be used if pack name is provided")
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	if c.watch && (c.follow || c.repeat > 0 || c.watchEvents || c.sinceIndex > 0 || c.dryRun || c.format != statusFormatTable) {
+		c.ui.Error("--watch cannot be used with --follow, --repeat, --watch-events, --since-index, --dry-run, or a report format")
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	if c.watch && c.watchInterval < minWatchInterval {
+		c.ui.Error(fmt.Sprintf("--watch-interval must be at least %s", minWatchInterval))
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	if c.syslog && len(c.args) == 0 {
+		c.ui.Error("--syslog can only be used if pack name is provided")
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	if _, _, err := parseSyslogAddress(c.syslogAddress); err != nil {
+		c.ui.ErrorWithContext(err, ErrParsingArgsOrFlags)
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	if (c.hideGC || c.onlyGC) && len(c.args) == 0 {
+		c.ui.Error("--hide-gc and --only-gc can only be used if pack name is provided")
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	if c.hideGC && c.onlyGC {
+		c.ui.Error("--hide-gc and --only-gc cannot be used together")
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	if (c.hideGC || c.onlyGC) && c.dryRun {
+		c.ui.Error("--hide-gc and --only-gc cannot be used with --dry-run")
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	if len(c.meta) > 0 && (len(c.args) == 0 || c.dryRun) {
+		c.ui.Error("--meta can only be used if pack name is provided, and cannot be used with --dry-run")
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	if len(c.filterStatus) > 0 && (len(c.args) == 0 || c.dryRun) {
+		c.ui.Error("--filter-status can only be used if pack name is provided, and cannot be used with --dry-run")
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	if len(c.columns) > 0 {
+		if len(c.args) == 0 || c.dryRun {
+			c.ui.Error("--columns can only be used if pack name is provided, and cannot be used with --dry-run")
+			c.ui.Info(c.helpUsageMessage())
+			return 1
+		}
+		// The valid columns depend on the optional columns enabled by the
+		// other flags, so columns are resolved using the full set of headers.
+		opts := c.jobsTableOptions()
+		opts.columns = nil
+		if c.columns, err = parseColumns(c.columns, jobTableHeaders(opts)); err != nil {
+			c.ui.ErrorWithContext(err, ErrParsingArgsOrFlags)
+			c.ui.Info(c.helpUsageMessage())
+			return 1
+		}
+	}
+
+	if c.labelPrefix == "" {
+		c.ui.Error("--label-prefix must not be empty")
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	if c.labelPrefix != defaultPackMetaPrefix && (c.cached || c.refresh || c.dryRun) {
+		c.ui.Error("--label-prefix cannot be used with --cached, --refresh, or --dry-run")
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	if c.healthSummary && (c.tree || c.dryRun || c.follow || c.repeat > 0 || c.watchEvents || c.format != statusFormatTable) {
+		c.ui.Error("--health-summary cannot be used with --tree, --dry-run, --follow, --repeat, --watch-events, or a report format")
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	if c.healthSummary && (c.showAllocs || c.showScaling || c.showEvals || c.explainHealth || c.csv || c.separator != "") {
+		c.ui.Error("--health-summary cannot be used with --allocs, --scaling, --show-evals, --explain-health, --csv, or --separator")
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	if c.failOnUnhealthy && len(c.args) == 0 && !c.healthSummary {
+		c.ui.Error("--fail-on-unhealthy can only be used if pack name is provided, or with --health-summary")
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	if c.failOnUnhealthy && (c.dryRun || c.follow || c.repeat > 0) {
+		c.ui.Error("--fail-on-unhealthy cannot be used with --dry-run, --follow, or --repeat")
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	if c.sort == statusSortSeverity && (len(c.args) == 0 || c.dryRun) {
+		c.ui.Error("--sort=severity can only be used if pack name is provided, and cannot be used with --dry-run")
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	if c.sort == statusSortSeverity && c.splitByRef {
+		c.ui.Error("--sort=severity cannot be used with --split-by-ref")
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	if c.severityRanking != "" && c.sort != statusSortSeverity {
+		c.ui.Error("--severity-ranking can only be used with --sort=severity")
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	if c.ranking, err = parseSeverityRanking(c.severityRanking); err != nil {
+		c.ui.ErrorWithContext(err, ErrParsingArgsOrFlags)
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	if (c.cached || c.refresh) && len(c.args) > 0 {
+		c.ui.Error("--cached and --refresh can only be used if pack name is not provided")
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	if c.cached && c.refresh {
+		c.ui.Error("--cached and --refresh cannot be used together")
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	if c.cacheTTL <= 0 {
+		c.ui.Error("--cache-ttl must be positive")
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	if c.tree && len(c.args) > 0 {
+		c.ui.Error("--tree can only be used if pack name is not provided")
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	if c.tree && (c.cached || c.refresh || c.format != statusFormatTable) {
+		c.ui.Error("--tree cannot be used with --cached, --refresh, or a report format")
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	if c.tree && c.csv {
+		c.ui.Error("--tree cannot be used with --csv")
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	if c.csv && (c.separator != "" || c.format != statusFormatTable) {
+		c.ui.Error("--csv cannot be used with --separator or a report format")
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	if c.markdown && (c.csv || c.separator != "" || c.tree || c.healthSummary) {
+		c.ui.Error("--format=markdown cannot be used with --csv, --separator, --tree, or --health-summary")
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	if !c.csv && (c.csvDelimiter != "," || c.csvAlwaysQuote || c.csvCRLF) {
+		c.ui.Error("--csv-delimiter, --csv-always-quote, and --csv-crlf can only be used with --csv")
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	if c.csv {
+		delim, err := parseCSVDelimiter(c.csvDelimiter)
+		if err != nil {
+			c.ui.ErrorWithContext(err, ErrParsingArgsOrFlags)
+			c.ui.Info(c.helpUsageMessage())
+			return 1
+		}
+		c.csvDialect = terminal.CSVDialect{Delimiter: delim, AlwaysQuote: c.csvAlwaysQuote, CRLF: c.csvCRLF}
+	}
+
+	if c.dryRun && len(c.args) == 0 {
+		c.ui.Error("--dry-run can only be used if pack name is provided")
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
+	if c.dryRun && c.format != statusFormatTable {
+		c.ui.Error("--dry-run can only be used with --format=table")
+		c.ui.Info(c.helpUsageMessage())
+		return 1
+	}
+
 	if len(c.args) > 0 {
 		c.packConfig.Name = c.args[0]
 	}
 
+	if c.dryRun {
+		return c.renderDryRunPackJobs()
+	}
+
+	// Status does not need the pack itself, but packs deployed with --no-cache
+	// are labelled using the name derived from the registry source.
+	if c.noCache && c.packConfig.Registry != "" {
+		c.packConfig.Registry = ephemeralRegistryName(c.packConfig.Registry)
+	}
+
 	// Set the packConfig defaults if necessary and generate our UI error context.
 	errorContext := errors.NewUIErrorContext()
 	errorContext.Add(errors.UIContextPrefixPackName, c.packConfig.Name)
@@ -50,54 +650,772 @@ func (c *StatusCommand) Run(args []string) int {
 
 	// If pack name isn't specified, return all deployed packs
 	if c.packConfig.Name == "" {
+		if c.healthSummary {
+			return c.unhealthyExitCode(c.renderAllHealthSummaries(client, errorContext))
+		}
+		if c.tree {
+			return c.renderDeployedPacksTree(client, errorContext)
+		}
 		return c.renderAllDeployedPacks(client, errorContext)
 	}
 
-	return c.renderDeployedPackJobs(client, errorContext)
+	if c.repeat > 0 {
+		return c.repeatPackStatus(client, errorContext)
+	}
+
+	if c.watch {
+		return c.unhealthyExitCode(c.watchPackStatus(client, errorContext))
+	}
+
+	return c.unhealthyExitCode(c.renderDeployedPackJobs(client, errorContext))
+}
+
+// packMetaKeys returns the metadata keys used to match deployed jobs to the
+// pack they belong to.
+func (c *StatusCommand) packMetaKeys() packMetaKeys {
+	return newPackMetaKeys(c.labelPrefix)
+}
+
+// unhealthyExitCode returns the exit code reporting the health of the pack
+// jobs output, when using --fail-on-unhealthy and the command succeeded.
+// Otherwise, the exit code of the command is returned unchanged.
+func (c *StatusCommand) unhealthyExitCode(code int) int {
+	if code != 0 || !c.failOnUnhealthy {
+		return code
+	}
+	return c.health.exitCode()
+}
+
+// renderAllHealthSummaries outputs a line for each deployed pack with the
+// number of its jobs which are healthy.
+func (c *StatusCommand) renderAllHealthSummaries(client *api.Client, errorContext *errors.UIErrorContext) int {
+	packJobs, _, err := getDeployedPackJobs(client, &cache.PackConfig{}, "", c.packMetaKeys(), c.failFast)
+	if err != nil {
+		c.ui.ErrorWithContext(err, "error retrieving jobs", errorContext.GetAll()...)
+		return 1
+	}
+
+	if len(packJobs) == 0 {
+		c.ui.Warning("no packs found")
+		return 0
+	}
+
+	c.health = packHealth(packJobs)
+	c.ui.Output(formatHealthSummaries(packJobs))
+	return 0
+}
+
+// repeatPackStatus outputs the status of the pack jobs the number of times
+// passed using --repeat, waiting for the interval between each poll. The exit
+// code reports the health of the pack observed by the last poll.
+func (c *StatusCommand) repeatPackStatus(client *api.Client, errorContext *errors.UIErrorContext) int {
+	for i := 1; i <= c.repeat; i++ {
+		if i > 1 {
+			select {
+			case <-c.Ctx.Done():
+				return c.health.exitCode()
+			case <-time.After(c.interval):
+			}
+		}
+
+		c.ui.Header(fmt.Sprintf("Poll %d of %d at %s", i, c.repeat, time.Now().Format(time.TimeOnly)))
+		if code := c.renderDeployedPackJobs(client, errorContext); code != 0 {
+			return code
+		}
+	}
+	return c.health.exitCode()
+}
+
+// minWatchInterval is the shortest interval allowed between each poll when
+// using --watch, so the Nomad API is not overloaded.
+const minWatchInterval = 2 * time.Second
+
+// watchPackStatus outputs the status of the pack jobs on the interval passed
+// using --watch-interval, until every job is healthy or the command is
+// interrupted. Interactive output is redrawn in place, while each poll is
+// output in turn otherwise.
+func (c *StatusCommand) watchPackStatus(client *api.Client, errorContext *errors.UIErrorContext) int {
+	for i := 1; ; i++ {
+		if i > 1 {
+			select {
+			case <-c.Ctx.Done():
+				return 0
+			case <-time.After(c.watchInterval):
+			}
+		}
+
+		if err := terminal.ClearScreen(c.ui); err != nil {
+			c.ui.ErrorWithContext(err, "failed to clear output", errorContext.GetAll()...)
+			return 1
+		}
+		c.ui.Header(fmt.Sprintf("Status at %s, press Ctrl-C to stop", time.Now().Format(time.TimeOnly)))
+
+		// The health is only updated when jobs are found, so the pack is not
+		// reported healthy before its jobs are registered.
+		c.health = jobPending
+		if code := c.renderDeployedPackJobs(client, errorContext); code != 0 {
+			return code
+		}
+		if c.health == jobHealthy {
+			c.ui.Success(fmt.Sprintf("All jobs of pack %q are healthy", c.packConfig.Name))
+			return 0
+		}
+	}
 }
 
 func (c *StatusCommand) renderDeployedPackJobs(client *api.Client, errorContext *errors.UIErrorContext) int {
 	var err error
-	packJobs, jobErrs, err := getDeployedPackJobs(client, c.packConfig, c.deploymentName)
+	packJobs, jobErrs, index, err := getDeployedPackJobsSince(c.Ctx, client, c.packConfig, c.deploymentName, c.packMetaKeys(), c.sinceIndex, c.failFast)
 	if err != nil {
 		c.ui.ErrorWithContext(err, "error retrieving jobs", errorContext.GetAll()...)
 		return 1
 	}
 
-	if len(packJobs) == 0 {
-		msg := fmt.Sprintf("no jobs found for pack %q", c.packConfig.Name)
-		if c.deploymentName != "" {
-			msg += fmt.Sprintf(" in deployment %q", c.deploymentName)
+	// No jobs being found is output as a warning, except by the json format,
+	// which outputs a report without jobs so it can always be parsed.
+	noJobs := func(msg string) int {
+		if c.format == statusFormatJSON {
+			return c.writeReport(&statusReport{
+				packName:       c.packConfig.Name,
+				deploymentName: c.deploymentName,
+				index:          index,
+				jobErrs:        jobErrs,
+			}, errorContext)
+		}
+		c.ui.Warning(msg)
+		return 0
+	}
+
+	if c.sinceIndex > 0 {
+		defer c.outputSinceIndex(index)
+
+		// No jobs changing since the index is expected when polling, so the
+		// empty report is still output for consumers tracking the index.
+		if len(packJobs) == 0 {
+			if c.format == statusFormatJSON {
+				return noJobs("")
+			}
+			c.ui.Info(fmt.Sprintf("no jobs of pack %q changed since index %d", c.packConfig.Name, c.sinceIndex))
+			return 0
+		}
+	}
+
+	if len(packJobs) == 0 {
+		msg := fmt.Sprintf("no jobs found for pack %q", c.packConfig.Name)
+		if c.deploymentName != "" {
+			msg += fmt.Sprintf(" in deployment %q", c.deploymentName)
+		}
+		return noJobs(msg)
+	}
+
+	if c.jobID != "" {
+		packJobs = slices.DeleteFunc(packJobs, func(info JobStatusInfo) bool { return info.jobID != c.jobID })
+		if len(packJobs) == 0 {
+			c.ui.ErrorWithContext(fmt.Errorf("job %q is not part of pack %q", c.jobID, c.packConfig.Name),
+				"error retrieving jobs", errorContext.GetAll()...)
+			return 1
+		}
+	}
+
+	if c.hideGC || c.onlyGC {
+		packJobs = slices.DeleteFunc(packJobs, func(info JobStatusInfo) bool { return info.pendingGC != c.onlyGC })
+		if len(packJobs) == 0 {
+			msg := fmt.Sprintf("no jobs found for pack %q which are not pending garbage collection", c.packConfig.Name)
+			if c.onlyGC {
+				msg = fmt.Sprintf("no jobs found for pack %q which are pending garbage collection", c.packConfig.Name)
+			}
+			return noJobs(msg)
+		}
+	}
+
+	if len(c.meta) > 0 {
+		packJobs = filterJobsByMeta(packJobs, c.meta)
+		if len(packJobs) == 0 {
+			return noJobs(fmt.Sprintf("no jobs found for pack %q with the metadata %s", c.packConfig.Name, formatMetaFilter(c.meta)))
+		}
+	}
+
+	if len(c.filterStatus) > 0 {
+		packJobs = filterJobsByStatus(packJobs, c.filterStatus)
+		if len(packJobs) == 0 {
+			msg := fmt.Sprintf("no jobs found for pack %q", c.packConfig.Name)
+			if c.deploymentName != "" {
+				msg += fmt.Sprintf(" in deployment %q", c.deploymentName)
+			}
+			return noJobs(msg + " with the status " + strings.Join(c.filterStatus, ", "))
+		}
+	}
+
+	if c.follow {
+		return c.followJob(client, c.jobID, errorContext)
+	}
+
+	// Paging is applied before the details of the jobs are retrieved, so
+	// only the jobs of the page are requested.
+	var nextPageToken string
+	if c.limit > 0 || c.pageToken != "" {
+		packJobs, nextPageToken, err = pageJobs(packJobs, c.pageToken, c.limit)
+		if err != nil {
+			c.ui.ErrorWithContext(err, ErrParsingArgsOrFlags, errorContext.GetAll()...)
+			return 1
+		}
+		if len(packJobs) == 0 {
+			return noJobs(fmt.Sprintf("no jobs found for pack %q after the page token", c.packConfig.Name))
+		}
+		defer c.outputNextPageToken(nextPageToken)
+	}
+
+	// Allocations are only needed when they are output, or used to filter the
+	// jobs by client node.
+	var jobAllocs map[string][]*api.AllocationListStub
+	if c.showAllocs || c.node != "" {
+		jobAllocs, jobErrs, err = getPackJobAllocs(client, packJobs, jobErrs, c.failFast)
+		if err != nil {
+			c.ui.ErrorWithContext(err, "error retrieving allocations", errorContext.GetAll()...)
+			return 1
+		}
+
+		if c.node != "" {
+			packJobs, jobAllocs = filterJobsByNode(packJobs, jobAllocs, c.node)
+			if len(packJobs) == 0 {
+				return noJobs(fmt.Sprintf("no jobs found for pack %q with allocations on node %q", c.packConfig.Name, c.node))
+			}
+		}
+	}
+
+	// Scaling state is only retrieved when output, as it requires several
+	// requests for each job.
+	var scaling []taskGroupScaling
+	if c.showScaling {
+		scaling, jobErrs, err = getPackJobScaling(client, packJobs, jobErrs, c.failFast)
+		if err != nil {
+			c.ui.ErrorWithContext(err, "error retrieving scaling policies", errorContext.GetAll()...)
+			return 1
+		}
+	}
+
+	// Evaluations are only retrieved when output, as they require a request
+	// for each job.
+	var evals []jobEvaluation
+	if c.showEvals {
+		evals, jobErrs, err = getPackJobEvals(client, packJobs, jobErrs, c.failFast)
+		if err != nil {
+			c.ui.ErrorWithContext(err, "error retrieving evaluations", errorContext.GetAll()...)
+			return 1
+		}
+	}
+
+	// Explanations are only retrieved when output, as they require several
+	// requests for each job.
+	var explanations map[string]string
+	if c.explainHealth {
+		explanations = getPackJobHealthExplanations(client, packJobs, jobAllocs, time.Now())
+	}
+
+	if c.sort == statusSortSeverity {
+		sortBySeverity(packJobs, c.ranking)
+	}
+
+	c.health = packHealth(packJobs)
+
+	if c.syslog {
+		c.writeSyslog(newStatusSummary(c.packConfig.Name, c.deploymentName, packJobs, jobErrs))
+	}
+
+	if c.healthSummary {
+		c.ui.Output(formatHealthSummaries(packJobs))
+		return 0
+	}
+
+	conflicts := deploymentRefConflicts(packJobs)
+	for _, name := range slices.Sorted(maps.Keys(conflicts)) {
+		c.ui.Warning(fmt.Sprintf(
+			"deployment %q contains jobs deployed from different pack refs (%s), the deployment name may have been reused",
+			name, strings.Join(conflicts[name], ", ")))
+	}
+
+	jobsTbl := formatDeployedPackJobs(packJobs, c.jobsTableOptions())
+
+	// Jobs deployed from a registry other than the one expected for their
+	// namespace are marked in the jobs table, and optionally fail the command.
+	mismatches := registryMismatches(packJobs, c.expectedRegistries)
+	failMismatches := c.failOnRegistryMismatch && mismatches > 0
+
+	if c.format != statusFormatTable {
+		report := &statusReport{
+			title:          fmt.Sprintf("Pack %q Status", c.packConfig.Name),
+			packName:       c.packConfig.Name,
+			deploymentName: c.deploymentName,
+			index:          index,
+			packJobs:       packJobs,
+			jobErrs:        jobErrs,
+			tables:         []statusReportTable{{title: "Jobs", tbl: jobsTbl}},
+			headerMap:      c.columnHeaders,
+		}
+		if c.showAllocs {
+			report.tables = append(report.tables, statusReportTable{title: "Allocations", tbl: formatPackJobAllocs(packJobs, jobAllocs)})
+		}
+		if c.showScaling && len(scaling) > 0 {
+			report.tables = append(report.tables, statusReportTable{title: "Scaling", tbl: formatPackJobScaling(scaling)})
+		}
+		if c.showEvals && len(evals) > 0 {
+			report.tables = append(report.tables, statusReportTable{title: "Evaluations", tbl: formatPackJobEvals(evals)})
+		}
+		if c.explainHealth {
+			report.tables = append(report.tables, statusReportTable{title: "Health", tbl: formatPackJobHealth(packJobs, explanations)})
+		}
+		if len(jobErrs) > 0 {
+			report.tables = append(report.tables, statusReportTable{title: "Errors", tbl: formatDeployedPackErrs(jobErrs)})
+		}
+		if code := c.writeReport(report, errorContext); code != 0 || failMismatches {
+			return 1
+		}
+		return 0
+	}
+
+	c.renderTable(jobsTbl)
+
+	if c.showAllocs {
+		c.ui.Output("")
+		c.renderTable(formatPackJobAllocs(packJobs, jobAllocs))
+	}
+
+	if c.showScaling && len(scaling) > 0 {
+		c.ui.Output("")
+		c.renderTable(formatPackJobScaling(scaling))
+	}
+
+	if c.showEvals && len(evals) > 0 {
+		c.ui.Output("")
+		c.renderTable(formatPackJobEvals(evals))
+	}
+
+	if c.explainHealth {
+		c.ui.Output("")
+		c.renderTable(formatPackJobHealth(packJobs, explanations))
+	}
+
+	if c.showLogs {
+		if code := c.renderFailedJobLogs(client, packJobs, jobAllocs, errorContext); code != 0 {
+			return code
+		}
+	}
+
+	if len(jobErrs) > 0 {
+		c.ui.WarningBold("error retrieving job status for the following jobs:")
+		c.renderTable(formatDeployedPackErrs(jobErrs))
+	}
+
+	if mismatches > 0 {
+		c.ui.Warning(fmt.Sprintf("%d of %d jobs were deployed from a registry other than the one expected for their namespace", mismatches, len(packJobs)))
+		if failMismatches {
+			return 1
+		}
+	}
+
+	if c.watchEvents {
+		return c.streamPackEvents(client, packJobs, errorContext)
+	}
+
+	return 0
+}
+
+// renderDryRunPackJobs renders the pack, without deploying it, and outputs the
+// jobs it would create, along with whether a job with the same ID already
+// exists in the cluster and would be updated or conflict with the deployment.
+func (c *StatusCommand) renderDryRunPackJobs() int {
+	cleanup, err := fetchEphemeralPack(c.baseCommand, c.packConfig)
+	if err != nil {
+		c.ui.ErrorWithContext(err, "failed to fetch pack")
+		return 1
+	}
+	defer cleanup()
+
+	// Set the packConfig defaults if necessary and generate our UI error context.
+	errorContext := initPackCommand(c.packConfig)
+	defer c.packConfig.Cleanup()
+
+	if err := cache.VerifyPackExists(c.packConfig, errorContext, c.ui); err != nil {
+		return 1
+	}
+
+	// If no deploymentName set default to pack@ref
+	c.deploymentName = getDeploymentName(c.baseCommand, c.packConfig)
+	errorContext.Add(errors.UIContextPrefixDeploymentName, c.deploymentName)
+
+	client, err := c.getAPIClient()
+	if err != nil {
+		c.ui.ErrorWithContext(err, "failed to initialize client", errorContext.GetAll()...)
+		return 1
+	}
+
+	packManager := generatePackManager(c.baseCommand, client, c.packConfig)
+
+	r, err := renderPack(packManager, c.ui, false, false, c.ignoreMissingVars, errorContext)
+	if err != nil {
+		return 1
+	}
+	if r.LenParentRenders() < 1 {
+		c.ui.ErrorWithContext(errors.ErrNoTemplatesRendered, "no templates rendered", errorContext.GetAll()...)
+		return 1
+	}
+
+	jobRunner, err := generateRunner(client, "job", &job.CLIConfig{}, &runner.Config{
+		PackName:       c.packConfig.Name,
+		PathPath:       c.packConfig.Path,
+		PackRef:        c.packConfig.Ref,
+		DeploymentName: c.deploymentName,
+		RegistryName:   c.packConfig.Registry,
+	})
+	if err != nil {
+		c.ui.ErrorWithContext(err, "failed to generate deployer", errorContext.GetAll()...)
+		return 1
+	}
+
+	jobRunner.SetTemplates(r.ParentRenders())
+	if parseErrs := jobRunner.ParseTemplates(); parseErrs != nil {
+		for _, parseErr := range parseErrs {
+			parseErr.Context.Append(errorContext)
+			c.ui.ErrorWithContext(parseErr.Err, parseErr.Subject, parseErr.Context.GetAll()...)
+		}
+		return 1
+	}
+
+	parsed, _ := jobRunner.ParsedTemplates().(map[string]job.ParsedTemplate)
+
+	dryRunJobs := make([]dryRunJob, 0, len(parsed))
+	for _, tplName := range slices.Sorted(maps.Keys(parsed)) {
+		tpl := parsed[tplName]
+		nomadJob := tpl.Job()
+
+		opts := &api.QueryOptions{}
+		if tpl.HasNamespace() {
+			opts.Namespace = pointer.Value(nomadJob.Namespace)
+		}
+		if tpl.HasRegion() {
+			opts.Region = pointer.Value(nomadJob.Region)
+		}
+
+		// Jobs which do not exist yet are not found.
+		existing, _, err := client.Jobs().Info(pointer.Value(nomadJob.ID), opts)
+		if err != nil {
+			if !strings.Contains(err.Error(), "404") {
+				c.ui.ErrorWithContext(err, "error retrieving job", errorContext.GetAll()...)
+				return 1
+			}
+			existing = nil
+		}
+		dryRunJobs = append(dryRunJobs, dryRunJob{
+			tplName:   tplName,
+			jobID:     pointer.Value(nomadJob.ID),
+			namespace: pointer.Value(nomadJob.Namespace),
+			existing:  existing,
+		})
+	}
+
+	tbl, conflicts := formatDryRunJobs(dryRunJobs, c.deploymentName)
+	c.renderTable(tbl)
+
+	if conflicts > 0 {
+		c.ui.Output("")
+		c.ui.Warning(fmt.Sprintf("%d of %d jobs conflict with existing jobs, deploying pack %q as deployment %q would fail",
+			conflicts, len(dryRunJobs), c.packConfig.Name, c.deploymentName))
+	}
+	return 0
+}
+
+// streamPackEvents outputs the job, deployment, evaluation, and allocation
+// events of the pack jobs as they are received from the Nomad event stream,
+// until the command is interrupted.
+func (c *StatusCommand) streamPackEvents(client *api.Client, packJobs []JobStatusInfo, errorContext *errors.UIErrorContext) int {
+	jobIDs := make([]string, 0, len(packJobs))
+	for _, info := range packJobs {
+		jobIDs = append(jobIDs, info.jobID)
+	}
+
+	// Events of all the topics can be filtered by job ID.
+	topics := map[api.Topic][]string{
+		api.TopicJob:        jobIDs,
+		api.TopicDeployment: jobIDs,
+		api.TopicEvaluation: jobIDs,
+		api.TopicAllocation: jobIDs,
+	}
+
+	stream, err := client.EventStream().Stream(c.Ctx, topics, 0, &api.QueryOptions{})
+	if err != nil {
+		c.ui.ErrorWithContext(err, "error subscribing to events", errorContext.GetAll()...)
+		return 1
+	}
+
+	c.ui.Output("")
+	c.ui.Info(fmt.Sprintf("Watching events of the jobs in pack %q, press Ctrl-C to stop", c.packConfig.Name))
+
+	for {
+		select {
+		case <-c.Ctx.Done():
+			return 0
+		case events, ok := <-stream:
+			if !ok {
+				return 0
+			}
+			if events.Err != nil {
+				if c.Ctx.Err() != nil {
+					return 0
+				}
+				c.ui.ErrorWithContext(events.Err, "error streaming events", errorContext.GetAll()...)
+				return 1
+			}
+			for i := range events.Events {
+				c.ui.Output(time.Now().Format(time.TimeOnly) + " " + formatEvent(&events.Events[i]))
+			}
+		}
+	}
+}
+
+// followPollInterval is how often a followed job is checked when no events
+// have been received, in case the event stream is unavailable.
+const followPollInterval = 5 * time.Second
+
+// followJob outputs the status and deployment progress of the job each time
+// it changes, until the job is healthy or has failed. The job is checked each
+// time one of its events is received from the Nomad event stream, and
+// periodically otherwise. The exit code is non-zero if the job failed.
+func (c *StatusCommand) followJob(client *api.Client, jobID string, errorContext *errors.UIErrorContext) int {
+	topics := map[api.Topic][]string{
+		api.TopicJob:        {jobID},
+		api.TopicDeployment: {jobID},
+		api.TopicAllocation: {jobID},
+	}
+
+	stream, err := client.EventStream().Stream(c.Ctx, topics, 0, &api.QueryOptions{})
+	if err != nil {
+		c.ui.Warning(fmt.Sprintf("failed to subscribe to events, polling instead: %s", err))
+		stream = nil
+	}
+
+	ticker := time.NewTicker(followPollInterval)
+	defer ticker.Stop()
+
+	c.ui.Info(fmt.Sprintf("Following job %q, press Ctrl-C to stop", jobID))
+
+	var last string
+	for {
+		state, err := getJobFollowState(client, jobID)
+		if err != nil {
+			if c.Ctx.Err() != nil {
+				return 0
+			}
+			c.ui.ErrorWithContext(err, "error retrieving job", errorContext.GetAll()...)
+			return 1
+		}
+
+		if line := state.String(); line != last {
+			c.ui.Output(time.Now().Format(time.TimeOnly) + " " + line)
+			last = line
+		}
+
+		if done, health, reason := state.terminal(); done {
+			if health == jobHealthy {
+				c.ui.Success(fmt.Sprintf("Job %q is healthy: %s", jobID, reason))
+				return 0
+			}
+			c.ui.Error(fmt.Sprintf("Job %q is %s: %s", jobID, health, reason))
+			return 1
+		}
+
+		select {
+		case <-c.Ctx.Done():
+			return 0
+		case <-ticker.C:
+		case events, ok := <-stream:
+			if !ok {
+				stream = nil
+				continue
+			}
+			if events.Err != nil {
+				if c.Ctx.Err() != nil {
+					return 0
+				}
+				c.ui.Warning(fmt.Sprintf("error streaming events, polling instead: %s", events.Err))
+				stream = nil
+			}
+		}
+	}
+}
+
+// getJobFollowState retrieves the job, the summary of its allocations, and
+// its latest deployment.
+func getJobFollowState(client *api.Client, jobID string) (jobFollowState, error) {
+	nomadJob, _, err := client.Jobs().Info(jobID, &api.QueryOptions{})
+	if err != nil {
+		return jobFollowState{}, err
+	}
+	summary, _, err := client.Jobs().Summary(jobID, &api.QueryOptions{})
+	if err != nil {
+		return jobFollowState{}, err
+	}
+	deployment, _, err := client.Jobs().LatestDeployment(jobID, &api.QueryOptions{})
+	if err != nil {
+		return jobFollowState{}, err
+	}
+	return jobFollowState{job: nomadJob, summary: summary, deployment: deployment}, nil
+}
+
+func (c *StatusCommand) renderAllDeployedPacks(client *api.Client, errorContext *errors.UIErrorContext) int {
+	packRegistryMap, err := c.getDeployedPacks(client)
+	if err != nil {
+		c.ui.ErrorWithContext(err, "error retrieving packs", errorContext.GetAll()...)
+		return 1
+	}
+
+	if len(packRegistryMap) == 0 {
+		c.ui.Warning("no packs found")
+		return 0
+	}
+
+	tbl := formatDeployedPacks(packRegistryMap)
+	if c.format != statusFormatTable {
+		return c.writeReport(&statusReport{
+			title:     "Deployed Packs",
+			tables:    []statusReportTable{{title: "Packs", tbl: tbl}},
+			headerMap: c.columnHeaders,
+		}, errorContext)
+	}
+
+	c.renderTable(tbl)
+
+	return 0
+}
+
+// outputNextPageToken writes the token of the next page to stderr, so it is
+// kept separate from the status written to stdout. Nothing is written for the
+// last page.
+func (c *StatusCommand) outputNextPageToken(token string) {
+	if token == "" {
+		return
+	}
+	if _, stderr, err := c.ui.OutputWriters(); err == nil {
+		fmt.Fprintf(stderr, "More jobs are available, continue with --page-token=%s\n", token)
+	}
+}
+
+// outputSinceIndex writes the index the status is current as of to stderr, so
+// it is kept separate from the status written to stdout.
+func (c *StatusCommand) outputSinceIndex(index uint64) {
+	if _, stderr, err := c.ui.OutputWriters(); err == nil {
+		fmt.Fprintf(stderr, "Jobs are current as of index %d, continue with --since-index=%d\n", index, index)
+	}
+}
+
+// writeReport formats the report using the report format requested by the
+// user, and writes it to the output file, or stdout if not set.
+func (c *StatusCommand) writeReport(r *statusReport, errorContext *errors.UIErrorContext) int {
+	report, err := formatStatusReport(c.format, r, time.Now())
+	if err != nil {
+		c.ui.ErrorWithContext(err, "failed to format status report", errorContext.GetAll()...)
+		return 1
+	}
+
+	if c.outputFile != "" {
+		if err := os.WriteFile(c.outputFile, []byte(report), 0o644); err != nil {
+			c.ui.ErrorWithContext(err, "failed to write status report", errorContext.GetAll()...)
+			return 1
 		}
-		c.ui.Warning(msg)
+		c.ui.Success(fmt.Sprintf("Status report written to %q", c.outputFile))
 		return 0
 	}
 
-	c.ui.Table(formatDeployedPackJobs(packJobs))
-
-	if len(jobErrs) > 0 {
-		c.ui.WarningBold("error retrieving job status for the following jobs:")
-		c.ui.Table(formatDeployedPackErrs(jobErrs))
+	stdout, _, err := c.ui.OutputWriters()
+	if err != nil {
+		c.ui.ErrorWithContext(err, "failed to write status report", errorContext.GetAll()...)
+		return 1
+	}
+	if _, err := io.WriteString(stdout, report); err != nil {
+		c.ui.ErrorWithContext(err, "failed to write status report", errorContext.GetAll()...)
+		return 1
 	}
-
 	return 0
 }
 
-func (c *StatusCommand) renderAllDeployedPacks(client *api.Client, errorContext *errors.UIErrorContext) int {
-	packRegistryMap, err := getDeployedPacks(client)
-	if err != nil {
-		c.ui.ErrorWithContext(err, "error retrieving packs", errorContext.GetAll()...)
-		return 1
+// renderFailedJobLogs outputs the last lines of the stderr logs of the
+// allocations of each failed or dead job. Logs which cannot be retrieved are
+// output as warnings, unless --fail-fast is set.
+func (c *StatusCommand) renderFailedJobLogs(client *api.Client, packJobs []JobStatusInfo, jobAllocs map[string][]*api.AllocationListStub, errorContext *errors.UIErrorContext) int {
+	for _, info := range packJobs {
+		if info.health != jobDead && info.health != jobFailed {
+			continue
+		}
+
+		for _, alloc := range failedJobLogAllocs(jobAllocs[info.jobID]) {
+			logs, err := getAllocLogTail(client, alloc, c.tail)
+			if err != nil {
+				if c.failFast {
+					c.ui.ErrorWithContext(err, "error retrieving logs", errorContext.GetAll()...)
+					return 1
+				}
+				c.ui.Warning(fmt.Sprintf("failed to retrieve logs of job %q: %s", info.jobID, err))
+				continue
+			}
+
+			for _, task := range slices.Sorted(maps.Keys(logs)) {
+				c.ui.Header(fmt.Sprintf("Job %q allocation %s task %q stderr", info.jobID, shortID(alloc.ID), task))
+				if logs[task] == "" {
+					c.ui.Output("(no output)")
+					continue
+				}
+				c.ui.Output(logs[task])
+			}
+		}
 	}
+	return 0
+}
 
-	if len(packRegistryMap) == 0 {
-		c.ui.Warning("no packs found")
-		return 0
+// renderTable outputs the table with numeric columns right aligned and all
+// other columns left aligned, unless a column separator has been set or the
+// table is output as CSV. Markdown tables mark the same alignments in their
+// separator row. The headers are renamed using the --header-map flag.
+func (c *StatusCommand) renderTable(tbl *terminal.Table) {
+	if c.columnHeaders != nil {
+		tbl = &terminal.Table{Headers: mapHeaders(tbl.Headers, c.columnHeaders), Rows: tbl.Rows}
+	}
+	opts := []terminal.Option{
+		terminal.WithColumnAlignment(terminal.DefaultColumnAlignment(tbl)),
+		terminal.WithColumnSeparator(unescapeSeparator(c.separator)),
+	}
+	if c.stream {
+		opts = append(opts, terminal.WithStreaming())
 	}
+	if c.csv {
+		opts = append(opts, terminal.WithCSV(c.csvDialect))
+	}
+	if c.markdown {
+		opts = append(opts, terminal.WithMarkdownOutput())
+	}
+	c.ui.Table(tbl, opts...)
+}
 
-	c.ui.Table(formatDeployedPacks(packRegistryMap))
+// unescapeSeparator interprets escape sequences, such as \t, within the
+// separator passed by the user. The separator is used as is if it cannot be
+// interpreted.
+func unescapeSeparator(sep string) string {
+	if out, err := strconv.Unquote(`"` + sep + `"`); err == nil {
+		return out
+	}
+	return sep
+}
 
-	return 0
+// parseCSVDelimiter returns the single character passed using the
+// --csv-delimiter flag, interpreting escape sequences such as \t. Quotes and
+// line breaks cannot be used, as they would make the fields ambiguous.
+func parseCSVDelimiter(s string) (rune, error) {
+	delim := []rune(unescapeSeparator(s))
+	if len(delim) != 1 {
+		return 0, fmt.Errorf("--csv-delimiter must be a single character, got %q", s)
+	}
+	if strings.ContainsRune("\"\r\n", delim[0]) {
+		return 0, fmt.Errorf("--csv-delimiter cannot be a quote or line break, got %q", s)
+	}
+	return delim[0], nil
 }
 
 func (c *StatusCommand) Flags() *flag.Sets {
@@ -124,6 +1442,507 @@ func (c *StatusCommand) Flags() *flag.Sets {
 
 					Using ref with a file path is not supported.`,
 		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "split-by-ref",
+			Target:  &c.splitByRef,
+			Default: false,
+			Usage: `Include the pack ref of each job and output the jobs of each
+					deployment grouped by the ref they were deployed from.
+					This helps identify stale jobs when a deployment name has
+					been reused for a different pack ref.`,
+		})
+
+		f.EnumSingleVar(&flag.EnumSingleVar{
+			Name:    "sort",
+			Target:  &c.sort,
+			Values:  []string{statusSortJob, statusSortSeverity},
+			Default: statusSortJob,
+			Usage: `Order the jobs are output in. The job order is the order
+					Nomad lists them in. The severity order outputs the most
+					severe health first, failed then dead, pending, and
+					healthy, so problems are at the top of the table.`,
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:    "severity-ranking",
+			Target:  &c.severityRanking,
+			Default: "",
+			Usage: `Comma separated list of health states ordered from the most
+					to the least severe, used by --sort=severity, such as
+					"dead,failed,pending,healthy". States which are not
+					listed are ranked after those which are.`,
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "show-version",
+			Target:  &c.showVersion,
+			Default: false,
+			Usage: `Include the version and modify index of each job. This helps
+					identify jobs which are part way through a rolling update.`,
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "allocs",
+			Target:  &c.showAllocs,
+			Default: false,
+			Usage: `Output the allocations of each job, including the client
+					node they are placed on.`,
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "scaling",
+			Target:  &c.showScaling,
+			Default: false,
+			Usage: `Output the scaling policy of each task group, along with its
+					current count. This helps verify that autoscaling is
+					enabled and the count is within the policy bounds. Task
+					groups without a scaling policy are omitted.`,
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "show-evals",
+			Target:  &c.showEvals,
+			Default: false,
+			Usage: `Output the latest evaluation of each job, along with the
+					reasons any task group could not be placed, such as
+					unsatisfied constraints or exhausted resources. This
+					explains jobs which remain pending.`,
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "explain-health",
+			Target:  &c.explainHealth,
+			Default: false,
+			Usage: `Output a description of why each job has its status, such
+					as "running: 3/3 allocations healthy, last deployment
+					succeeded 5m ago", from its allocations and latest
+					deployment. Details which cannot be retrieved are
+					omitted from the description.`,
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "logs",
+			Target:  &c.showLogs,
+			Default: false,
+			Usage: `Output the last lines of the stderr logs of the allocations
+					of each failed or dead job. The failed allocations of the
+					job are used, or its most recent allocation if none have
+					failed. Must be used with --allocs.`,
+		})
+
+		f.IntVar(&flag.IntVar{
+			Name:    "tail",
+			Target:  &c.tail,
+			Default: 10,
+			Usage:   `Number of log lines output for each task when using --logs.`,
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "watch-events",
+			Target:  &c.watchEvents,
+			Default: false,
+			Usage: `After outputting the status, stream the job, deployment,
+					evaluation, and allocation events of the pack jobs from
+					the Nomad event stream until interrupted. Recent events
+					still buffered by the Nomad servers are output first.`,
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:    "job",
+			Target:  &c.jobID,
+			Default: "",
+			Usage: `Only include the job of the pack with the specified ID.
+					Requires a pack name.`,
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "follow",
+			Target:  &c.follow,
+			Default: false,
+			Usage: `Follow the job passed using --job, outputting its status and
+					deployment progress each time they change, until the job
+					is healthy or has failed. The command fails if the job
+					fails. Must be used with --job.`,
+		})
+
+		f.IntVar(&flag.IntVar{
+			Name:    "repeat",
+			Target:  &c.repeat,
+			Default: 0,
+			Usage: `Poll the status of the pack the specified number of times,
+					waiting for --interval between each poll, then exit with
+					the health observed by the last poll: 0 when every job
+					is healthy, 2 when a job is pending, 3 when a job is dead,
+					and 4 when a job has failed. Requires a pack name.`,
+		})
+
+		f.DurationVar(&flag.DurationVar{
+			Name:    "interval",
+			Target:  &c.interval,
+			Default: 5 * time.Second,
+			Usage:   `Time to wait between each poll when using --repeat.`,
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "watch",
+			Target:  &c.watch,
+			Default: false,
+			Usage: `Output the status of the pack every --watch-interval until
+					every job is healthy, or the command is interrupted using
+					Ctrl-C. Interactive output is redrawn in place, while a
+					timestamped table is output for each poll otherwise.
+					Requires a pack name.`,
+		})
+
+		f.DurationVar(&flag.DurationVar{
+			Name:    "watch-interval",
+			Target:  &c.watchInterval,
+			Default: 5 * time.Second,
+			Usage:   `Time to wait between each poll when using --watch. Must be at least 2s.`,
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:    "node",
+			Target:  &c.node,
+			Default: "",
+			Usage: `Only include jobs with allocations on the client node with
+					the specified ID, ID prefix, or name. When combined with
+					--allocs, only the allocations on the node are output.`,
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "cached",
+			Target:  &c.cached,
+			Default: false,
+			Usage: `When listing the deployed packs, output the snapshot of the
+					last query of the cluster, rather than querying it again,
+					if it is younger than --cache-ttl. Snapshots are kept for
+					each cluster address and namespace. When the snapshot is
+					older, the cluster is queried and the snapshot updated,
+					falling back to the stale snapshot with a warning if the
+					query fails.`,
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "refresh",
+			Target:  &c.refresh,
+			Default: false,
+			Usage: `When listing the deployed packs, query the cluster and
+					update the snapshot used by --cached.`,
+		})
+
+		f.DurationVar(&flag.DurationVar{
+			Name:    "cache-ttl",
+			Target:  &c.cacheTTL,
+			Default: time.Minute,
+			Usage:   `Age after which the snapshot used by --cached is stale.`,
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "tree",
+			Target:  &c.tree,
+			Default: false,
+			Usage: `When listing the deployed packs, output every deployed pack
+					job within a tree of the registries, packs, and
+					deployments they belong to, along with the status of each
+					job.`,
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "hide-gc",
+			Target:  &c.hideGC,
+			Default: false,
+			Usage: `Omit jobs which are dead, such as stopped or completed jobs,
+					and so are pending garbage collection. This keeps the
+					output focused on live workloads.`,
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "only-gc",
+			Target:  &c.onlyGC,
+			Default: false,
+			Usage: `Only output jobs which are dead, such as stopped or completed
+					jobs, and so are pending garbage collection.`,
+		})
+
+		f.StringMapVar(&flag.StringMapVar{
+			Name:    "meta",
+			Target:  &c.meta,
+			Default: make(map[string]string),
+			Usage: `Only output jobs whose Nomad job metadata has the key set to
+					the value, in the form key=value. Can be specified multiple
+					times, in which case the jobs must match every pair.`,
+		})
+
+		f.EnumVar(&flag.EnumVar{
+			Name:   "filter-status",
+			Target: &c.filterStatus,
+			Values: []string{"running", "pending", "dead", jobKindPeriodic, jobKindParameterized},
+			Usage: `Only output jobs with one of the specified statuses. Can be
+					specified multiple times, or as a comma separated list.
+					Periodic and parameterized jobs are matched by the
+					periodic and parameterized statuses. Can be combined with
+					--name to filter the jobs of a deployment.`,
+		})
+
+		f.StringSliceVar(&flag.StringSliceVar{
+			Name:   "columns",
+			Target: &c.columns,
+			Usage: `Comma separated list of the jobs table columns to output, in
+					the order they are output, such as "job,status". Columns
+					are referred to by their header, such as "Job Name", or by
+					their --header-map key. Columns enabled by other flags,
+					such as --show-version, can also be selected. All the
+					columns are output by default.`,
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:    "header-map",
+			Target:  &c.headerMap,
+			Default: "",
+			Usage: `Comma separated list of key=header pairs used to rename the
+					table column headers, such as "job=Job ID,status=State".
+					Valid keys are pack, registry, deployment, ref, job,
+					version, modify_index, status, running, pending, failed,
+					alloc, node_id, node, task_group, desired, and error. Applies to all formats
+					which output the tables.`,
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:    "separator",
+			Target:  &c.separator,
+			Default: "",
+			Usage: `Separator to output between the table columns, such as
+					"\t", "," or "|". When set, the columns are not padded to
+					align them, which makes the output easier to process with
+					tools such as cut and awk. Headers use the same separator.`,
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "csv",
+			Target:  &c.csv,
+			Default: false,
+			Usage: `Output the tables as CSV, with the headers as the first
+					record. Fields are quoted as described by RFC 4180, unless
+					changed using the --csv-delimiter, --csv-always-quote, and
+					--csv-crlf flags.`,
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:    "csv-delimiter",
+			Target:  &c.csvDelimiter,
+			Default: ",",
+			Usage: `Character to separate the fields of the CSV output with, such
+					as ";" or "\t". Requires --csv.`,
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "csv-always-quote",
+			Target:  &c.csvAlwaysQuote,
+			Default: false,
+			Usage: `Quote every field of the CSV output, rather than only the
+					fields which contain the delimiter, a quote, or a line
+					break. Requires --csv.`,
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "csv-crlf",
+			Target:  &c.csvCRLF,
+			Default: false,
+			Usage: `End the records of the CSV output with \r\n rather than \n.
+					Requires --csv.`,
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "stream",
+			Target:  &c.stream,
+			Default: false,
+			Usage: `Write the table rows as they are formatted, rather than
+					buffering the whole table, which keeps memory use bounded
+					for very large clusters. The columns are sized using the
+					first rows, so alignment is approximate. Tables with more
+					than 10000 rows are always streamed.`,
+		})
+
+		f.StringMapVar(&flag.StringMapVar{
+			Name:    "expected-registry-map",
+			Target:  &c.expectedRegistries,
+			Default: make(map[string]string),
+			Usage: `Specifies the registry the pack jobs within a namespace are
+					expected to be deployed from, in the form
+					namespace=registry, and can be specified multiple times.
+					The namespace of each job is output along with a registry
+					check column, which warns about jobs deployed from a
+					different registry. Jobs in namespaces which are not
+					mapped are not checked.`,
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:    "label-prefix",
+			Target:  &c.labelPrefix,
+			Default: defaultPackMetaPrefix,
+			Usage: `Prefix of the job metadata keys used to match deployed jobs
+					to the pack they belong to, such as the pack name in
+					"pack.name". This allows jobs deployed using an older
+					label scheme to be matched. The pack name, registry,
+					deployment name, and ref keys all use the prefix.`,
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "health-summary",
+			Target:  &c.healthSummary,
+			Default: false,
+			Usage: `Only output a line for each pack with the number of its
+					jobs which are healthy, such as "example: 8/10 jobs
+					healthy", for use in status bars or chat. If no pack name
+					is specified, a line is output for each deployed pack.`,
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "fail-on-unhealthy",
+			Aliases: []string{"exit-code"},
+			Target:  &c.failOnUnhealthy,
+			Default: false,
+			Usage: `Exit with the most severe health of the jobs output: 0
+					when every job is healthy, 2 when a job is pending, 3 when
+					a job is dead, and 4 when a job has failed. Jobs which are
+					running, batch jobs which have completed, and periodic or
+					parameterized jobs are healthy. Requires a pack name, or
+					--health-summary.`,
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "fail-on-registry-mismatch",
+			Target:  &c.failOnRegistryMismatch,
+			Default: false,
+			Usage: `Fail the command if any job was deployed from a registry
+					other than the one expected for its namespace. Must be
+					used with --expected-registry-map.`,
+		})
+
+		f.IntVar(&flag.IntVar{
+			Name:    "limit",
+			Target:  &c.limit,
+			Default: 0,
+			Usage: `Maximum number of jobs to output, ordered by namespace and
+					job ID. When more jobs are available, a page token is
+					written to stderr which continues from the last job output
+					when passed using --page-token. A limit of 0 outputs all
+					jobs.`,
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:    "page-token",
+			Target:  &c.pageToken,
+			Default: "",
+			Usage: `Continue the output after the last job of a previous page,
+					using the token written when using --limit. The token
+					encodes the position of the job rather than an offset, so
+					paging is consistent when jobs are added or removed.`,
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "dry-run",
+			Target:  &c.dryRun,
+			Default: false,
+			Usage: `Render the pack without deploying it and output the jobs it
+					would create, along with whether a job with the same ID
+					already exists in the cluster. Existing jobs of the same
+					deployment would be updated, while any other existing job
+					conflicts with the deployment. Takes the same variable
+					flags as run.`,
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "syslog",
+			Target:  &c.syslog,
+			Default: false,
+			Usage: `Also write a JSON summary of the status of the pack jobs to
+					syslog, for monitoring based on logs. Failing to connect
+					to syslog results in a warning rather than an error.`,
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:    "syslog-address",
+			Target:  &c.syslogAddress,
+			Default: "",
+			Usage: `Address of the remote syslog server the status is written
+					to when using --syslog, such as udp://logs.example.com:514
+					or tcp://logs.example.com:601. If not specified, the local
+					syslog server is used.`,
+		})
+
+		f.EnumSingleVar(&flag.EnumSingleVar{
+			Name:    "syslog-facility",
+			Target:  &c.syslogFacility,
+			Values:  syslogFacilities,
+			Default: "user",
+			Usage:   `Syslog facility the status is written with when using --syslog.`,
+		})
+
+		f.EnumSingleVar(&flag.EnumSingleVar{
+			Name:    "syslog-priority",
+			Target:  &c.syslogPriority,
+			Values:  syslogSeverities,
+			Default: "info",
+			Usage:   `Syslog severity the status is written with when using --syslog.`,
+		})
+
+		f.EnumSingleVar(&flag.EnumSingleVar{
+			Name:    "format",
+			Target:  &c.format,
+			Values:  []string{statusFormatTable, statusFormatCSV, statusFormatMarkdown, statusFormatHTML, statusFormatJUnit, statusFormatJSON, statusFormatInflux},
+			Default: statusFormatTable,
+			Usage: `Format used to output the status. The csv format outputs
+					the tables as CSV, in the same way as --csv, for use in
+					spreadsheets. The markdown format outputs the tables as
+					GitHub Flavored Markdown, for embedding in documentation
+					such as a README. The html format outputs a
+					self-contained HTML report, with the status of each job
+					colored, which can be shared without further processing.
+					The junit format outputs a JUnit XML report with a test
+					case for each job of the pack, which fails when the job is
+					not healthy, for display alongside CI test results. The
+					json format outputs the status of each job of the pack,
+					along with the Nomad index it is current as of, for use
+					with --since-index. The influx format outputs a point in
+					the InfluxDB line protocol for each job of the pack.`,
+		})
+
+		f.Uint64Var(&flag.Uint64Var{
+			Name:   "since-index",
+			Target: &c.sinceIndex,
+			Usage: `Only output the jobs of the pack modified after the Nomad
+					index. The command waits until a job changes after the
+					index, using a blocking query, then writes the index the
+					jobs are current as of to stderr, to pass to the next call.
+					This allows efficient polling loops. Requires a pack
+					name.`,
+		})
+
+		f.EnumSingleVar(&flag.EnumSingleVar{
+			Name:    "output",
+			Target:  &c.output,
+			Values:  []string{statusFormatTable, statusFormatJSON},
+			Default: statusFormatTable,
+			Usage: `Output mode of the status, matching the --output flag of
+					the info command. The json output is the same as
+					--format=json, and outputs a report without jobs, rather
+					than a warning, when no jobs are found. Requires a pack
+					name.`,
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:    "output-file",
+			Target:  &c.outputFile,
+			Default: "",
+			Usage: `Path of the file to write the report to when using a report
+					format, such as html or junit. If not specified, the report is
+					written to stdout.`,
+		})
+
+		noCacheFlag(f, &c.noCache)
 	})
 }
 
@@ -140,10 +1959,22 @@ func (c *StatusCommand) Help() string {
 	# Get a list of all deployed packs and their registries
 	nomad-pack status
 
+	# Get a list of all deployed packs, using the snapshot of the last query
+	# when it is less than five minutes old
+	nomad-pack status --cached --cache-ttl=5m
+
+	# Get a tree of all deployed packs, grouped by registry and deployment,
+	# along with the status of their jobs
+	nomad-pack status --tree
+
 	# Get a list of all deployed jobs in pack example, along with their status
 	# and deployment names
 	nomad-pack status example
 
+	# Preview the jobs pack example would create, and whether any of them
+	# already exist in the cluster, before running it
+	nomad-pack status example --dry-run --var=region=eu
+
 	# Get a list of all deployed jobs and their status for an example pack in
 	# the deployment name "dev"
 	nomad-pack status example --name=dev
@@ -151,6 +1982,120 @@ func (c *StatusCommand) Help() string {
 	# Get a list of all deployed jobs and their status for an example pack in
 	# the deployment name "dev"
 	nomad-pack status example --name=dev --registry=community
+
+	# Get the allocations of all deployed jobs in pack example which are
+	# placed on a node being drained
+	nomad-pack status example --allocs --node=f7476465
+
+	# Get the allocations of all deployed jobs in pack example, along with
+	# the last 20 lines of the stderr logs of any failed jobs
+	nomad-pack status example --allocs --logs --tail=20
+
+	# Get the live jobs of pack example, omitting stopped and completed jobs
+	# which are pending garbage collection
+	nomad-pack status example --hide-gc
+
+	# Get the jobs of pack example owned by the edge team, using their Nomad
+	# job metadata
+	nomad-pack status example --meta team=edge
+
+	# Get the jobs of the dev deployment of pack example which are not running
+	nomad-pack status example --name=dev --filter-status=pending,dead
+
+	# Get only the name and status of the jobs of pack example
+	nomad-pack status example --columns=job,status
+
+	# Get a list of all deployed packs, matching jobs deployed using an older
+	# label scheme, such as with the pack name in legacy_pack.name
+	nomad-pack status --label-prefix=legacy_pack.
+
+	# Get the number of healthy jobs of each deployed pack, failing if any
+	# job is not healthy
+	nomad-pack status --health-summary --fail-on-unhealthy
+
+	# Get the status of pack example, exiting with a non-zero code unless
+	# every job is healthy, such as to gate a deploy in CI
+	nomad-pack status example --exit-code
+
+	# Get the jobs of pack example with failed and dead jobs first, for triage
+	nomad-pack status example --sort=severity
+
+	# Get the scaling policy bounds and current counts of the autoscaled task
+	# groups in pack example
+	nomad-pack status example --scaling
+
+	# Find out why the jobs in pack example are pending, such as a placement
+	# failure caused by an unsatisfied constraint
+	nomad-pack status example --show-evals
+
+	# Explain why each job in pack example has its status, from the health of
+	# its allocations and the result of its latest deployment
+	nomad-pack status example --explain-health
+
+	# Get the status of the jobs of pack example in every namespace, along with
+	# the namespace of each job
+	nomad-pack status example --namespace=*
+
+	# Check that the jobs of pack example in the prod namespace were deployed
+	# from the approved registry, failing if any were not
+	nomad-pack status example --expected-registry-map=prod=approved \
+		--fail-on-registry-mismatch
+
+	# Page through the jobs of pack example 50 at a time, passing the token
+	# output after each page to get the next one
+	nomad-pack status example --limit=50
+	nomad-pack status example --limit=50 --page-token=eyJucyI6...
+
+	# Poll the status of pack example 3 times, 10 seconds apart, and exit
+	# with the health of the pack observed by the last poll
+	nomad-pack status example --repeat=3 --interval=10s
+
+	# Watch the status of pack example until every job is healthy
+	nomad-pack status example --watch --watch-interval=10s
+
+	# Follow the deployment of the web job of pack example until it is healthy
+	nomad-pack status example --job=web --follow
+
+	# Watch the events of all deployed jobs in pack example during a rollout
+	nomad-pack status example --watch-events
+
+	# Get the status of the jobs in pack example deployed across a very large
+	# cluster without buffering the table
+	nomad-pack status example --allocs --stream
+
+	# Get a tab separated list of all deployed jobs in pack example
+	nomad-pack status example --separator='\t'
+
+	# Get the deployed jobs in pack example as CSV for a spreadsheet which
+	# expects semicolons, quoted fields, and CRLF line endings
+	nomad-pack status example --csv --csv-delimiter=';' --csv-always-quote --csv-crlf
+
+	# Export the deployed jobs in pack example to a spreadsheet
+	nomad-pack status example --format=csv > status.csv
+
+	# Generate a Markdown table of the deployed jobs in pack example for a README
+	nomad-pack status example --format=markdown > status.md
+
+	# Also write a summary of the status of pack example to a remote syslog
+	# server
+	nomad-pack status example --syslog --syslog-address=udp://logs:514
+
+	# Write an HTML report of all deployed jobs in pack example
+	nomad-pack status example --format=html --output-file=status.html
+
+	# Write a JUnit XML report of the health of the jobs in pack example
+	nomad-pack status example --format=junit --output-file=pack-health.xml
+
+	# Output the status of the jobs in pack example in the InfluxDB line
+	# protocol, such as from a Telegraf exec input
+	nomad-pack status example --format=influx
+
+	# Wait for the jobs in pack example to change after index 1234, then
+	# output them as JSON along with the index to pass to the next call
+	nomad-pack status example --format=json --since-index=1234
+
+	# Output the status of the jobs in pack example as JSON for use in scripts
+	nomad-pack status example --output=json | jq -r '.jobs[].status'
 	`
 
 	return formatHelp(`
@@ -195,20 +2140,155 @@ func formatDeployedPacks(packRegistryMap map[string]map[string]struct{}) *termin
 	return tbl
 }
 
-func formatDeployedPackJobs(packJobs []JobStatusInfo) *terminal.Table {
-	tbl := terminal.NewTable("Pack Name", "Registry Name", "Deployment Name", "Job Name", "Status")
+// jobsTableOptions returns the options of the jobs table set by the flags.
+func (c *StatusCommand) jobsTableOptions() jobTableOptions {
+	return jobTableOptions{
+		splitByRef:         c.splitByRef,
+		showVersion:        c.showVersion,
+		expectedRegistries: c.expectedRegistries,
+		showNamespace:      c.allNamespaces(),
+		columns:            c.columns,
+	}
+}
+
+// allNamespaces returns whether the jobs of every namespace are queried, which
+// is the case when the namespace is the * wildcard.
+func (c *StatusCommand) allNamespaces() bool {
+	namespace := c.nomadConfig.namespace
+	if namespace == "" {
+		namespace = os.Getenv("NOMAD_NAMESPACE")
+	}
+	return namespace == "*"
+}
+
+// jobTableOptions controls the optional columns, and ordering, of the table
+// output by formatDeployedPackJobs.
+type jobTableOptions struct {
+	// splitByRef includes the pack ref of each job and orders the jobs so
+	// that those of each deployment and ref are output together.
+	splitByRef bool
+
+	// showVersion includes the version and modify index of each job.
+	showVersion bool
+
+	// expectedRegistries includes the namespace of each job, and whether it
+	// was deployed from the registry expected for its namespace, when set.
+	expectedRegistries map[string]string
+
+	// showNamespace includes the namespace of each job, which is otherwise
+	// only included along with expectedRegistries.
+	showNamespace bool
+
+	// columns are the headers of the columns to include, in the order they
+	// are output. All the columns are included when empty.
+	columns []string
+}
+
+// formatDeployedPackJobs returns the table of deployed pack jobs, including
+// the optional columns enabled by opts.
+func formatDeployedPackJobs(packJobs []JobStatusInfo, opts jobTableOptions) *terminal.Table {
+	if opts.splitByRef {
+		packJobs = slices.Clone(packJobs)
+		slices.SortStableFunc(packJobs, func(a, b JobStatusInfo) int {
+			return cmp.Or(
+				cmp.Compare(a.deploymentName, b.deploymentName),
+				cmp.Compare(a.packRef, b.packRef),
+				cmp.Compare(a.jobID, b.jobID),
+			)
+		})
+	}
+
+	checkRegistry := len(opts.expectedRegistries) > 0
+	showNamespace := checkRegistry || opts.showNamespace
+	tbl := terminal.NewTable(jobTableHeaders(opts)...)
 	for _, jobInfo := range packJobs {
 		row := []string{}
 		row = append(row, jobInfo.packName)
 		row = append(row, jobInfo.registryName)
 		row = append(row, jobInfo.deploymentName)
+		if opts.splitByRef {
+			row = append(row, jobInfo.packRef)
+		}
 		row = append(row, jobInfo.jobID)
+		if showNamespace {
+			row = append(row, jobInfo.namespace)
+		}
+		if opts.showVersion {
+			row = append(row, strconv.FormatUint(jobInfo.version, 10))
+			row = append(row, strconv.FormatUint(jobInfo.modifyIndex, 10))
+		}
 		row = append(row, jobInfo.status)
+		row = append(row, formatAllocCounts(jobInfo.allocs)...)
+		if checkRegistry {
+			row = append(row, registryCheck(jobInfo, opts.expectedRegistries))
+		}
 		tbl.Rows = append(tbl.Rows, row)
 	}
+
+	if len(opts.columns) > 0 {
+		return selectColumns(tbl, opts.columns)
+	}
 	return tbl
 }
 
+// jobTableHeaders returns the headers of the table output by
+// formatDeployedPackJobs, including the optional columns enabled by opts.
+func jobTableHeaders(opts jobTableOptions) []string {
+	headers := []string{"Pack Name", "Registry Name", "Deployment Name"}
+	if opts.splitByRef {
+		headers = append(headers, "Pack Ref")
+	}
+	headers = append(headers, "Job Name")
+	checkRegistry := len(opts.expectedRegistries) > 0
+	if checkRegistry || opts.showNamespace {
+		headers = append(headers, "Namespace")
+	}
+	if opts.showVersion {
+		headers = append(headers, "Version", "Modify Index")
+	}
+	headers = append(headers, "Status", "Running", "Pending", "Failed")
+	if checkRegistry {
+		headers = append(headers, "Registry Check")
+	}
+	return headers
+}
+
+// formatAllocCounts returns the running, pending, and failed allocation counts
+// of a job as table cells, which are blank when the counts are not known.
+func formatAllocCounts(counts *allocCounts) []string {
+	if counts == nil {
+		return []string{"", "", ""}
+	}
+	return []string{strconv.Itoa(counts.running), strconv.Itoa(counts.pending), strconv.Itoa(counts.failed)}
+}
+
+// registryCheck returns the result of checking the job was deployed from the
+// registry expected for its namespace, which is empty when the namespace is
+// not mapped to a registry.
+func registryCheck(info JobStatusInfo, expectedRegistries map[string]string) string {
+	expected, ok := expectedRegistries[info.namespace]
+	switch {
+	case !ok:
+		return ""
+	case info.registryName == expected:
+		return "ok"
+	default:
+		return color.RedString("expected registry %q", expected)
+	}
+}
+
+// registryMismatches returns the number of jobs deployed from a registry other
+// than the one expected for their namespace.
+func registryMismatches(packJobs []JobStatusInfo, expectedRegistries map[string]string) int {
+	var n int
+	for _, info := range packJobs {
+		if expected, ok := expectedRegistries[info.namespace]; ok && info.registryName != expected {
+			n++
+		}
+	}
+	return n
+}
+
 func formatDeployedPackErrs(packErrs []JobStatusError) *terminal.Table {
 	tbl := terminal.NewTable("Job Name", "Error")
 	for _, jobInfo := range packErrs {
@@ -219,3 +2299,109 @@ func formatDeployedPackErrs(packErrs []JobStatusError) *terminal.Table {
 	}
 	return tbl
 }
+
+func formatPackJobAllocs(packJobs []JobStatusInfo, jobAllocs map[string][]*api.AllocationListStub) *terminal.Table {
+	tbl := terminal.NewTable("Job Name", "Alloc ID", "Node ID", "Node Name", "Task Group", "Desired", "Status")
+	for _, jobInfo := range packJobs {
+		for _, alloc := range jobAllocs[jobInfo.jobID] {
+			row := []string{}
+			row = append(row, jobInfo.jobID)
+			row = append(row, shortID(alloc.ID))
+			row = append(row, shortID(alloc.NodeID))
+			row = append(row, alloc.NodeName)
+			row = append(row, alloc.TaskGroup)
+			row = append(row, alloc.DesiredStatus)
+			row = append(row, alloc.ClientStatus)
+			tbl.Rows = append(tbl.Rows, row)
+		}
+	}
+	return tbl
+}
+
+func formatPackJobScaling(scaling []taskGroupScaling) *terminal.Table {
+	tbl := terminal.NewTable("Job Name", "Task Group", "Enabled", "Min", "Max", "Desired", "Running", "In Bounds")
+	for _, tg := range scaling {
+		tbl.Rows = append(tbl.Rows, []string{
+			tg.jobID,
+			tg.group,
+			strconv.FormatBool(tg.enabled),
+			strconv.FormatInt(tg.min, 10),
+			strconv.FormatInt(tg.max, 10),
+			strconv.Itoa(tg.desired),
+			strconv.Itoa(tg.running),
+			strconv.FormatBool(tg.inBounds()),
+		})
+	}
+	return tbl
+}
+
+// formatPackJobEvals returns a table of the latest evaluation of each job,
+// with a row for each task group which could not be placed. Evaluations
+// without placement failures are output on a single row.
+func formatPackJobEvals(evals []jobEvaluation) *terminal.Table {
+	tbl := terminal.NewTable("Job Name", "Eval ID", "Triggered By", "Eval Status", "Task Group", "Placement Failures")
+	for _, eval := range evals {
+		row := []string{eval.jobID, shortID(eval.evalID), eval.triggeredBy, eval.status}
+		if len(eval.failedTGAllocs) == 0 {
+			tbl.Rows = append(tbl.Rows, append(row, "", "(none)"))
+			continue
+		}
+		for _, group := range slices.Sorted(maps.Keys(eval.failedTGAllocs)) {
+			reasons := placementFailureReasons(eval.failedTGAllocs[group])
+			tbl.Rows = append(tbl.Rows, append(slices.Clone(row), group, color.RedString(strings.Join(reasons, "; "))))
+		}
+	}
+	return tbl
+}
+
+// formatPackJobHealth returns a table of the health of each job, along with
+// the explanation of why the job has its status. Unhealthy jobs are
+// highlighted.
+func formatPackJobHealth(packJobs []JobStatusInfo, explanations map[string]string) *terminal.Table {
+	tbl := terminal.NewTable("Job Name", "Health", "Explanation")
+	for _, info := range packJobs {
+		health := info.health.String()
+		if info.health != jobHealthy {
+			health = color.RedString(health)
+		}
+		tbl.Rows = append(tbl.Rows, []string{info.jobID, health, explanations[info.jobID]})
+	}
+	return tbl
+}
+
+// dryRunJob is a job rendered by a pack which has not been deployed, along
+// with the job of the same ID which already exists in the cluster, if any.
+type dryRunJob struct {
+	tplName   string
+	jobID     string
+	namespace string
+	existing  *api.Job
+}
+
+// formatDryRunJobs returns a table of the jobs a pack would create, with the
+// result of deploying each one as the deployment, and the number of jobs which
+// conflict with an existing job.
+func formatDryRunJobs(jobs []dryRunJob, deploymentName string) (*terminal.Table, int) {
+	var conflicts int
+	tbl := terminal.NewTable("Job Name", "Namespace", "Template", "Existing Status", "Result")
+	for _, j := range jobs {
+		var existingStatus, result string
+		switch {
+		case j.existing == nil:
+			result = "create"
+		case j.existing.Meta[job.PackDeploymentNameKey] == deploymentName:
+			existingStatus = pointer.Value(j.existing.Status)
+			result = "update"
+		case j.existing.Meta[job.PackDeploymentNameKey] != "":
+			existingStatus = pointer.Value(j.existing.Status)
+			result = color.RedString("conflict: part of deployment %q", j.existing.Meta[job.PackDeploymentNameKey])
+			conflicts++
+		default:
+			existingStatus = pointer.Value(j.existing.Status)
+			result = color.RedString("conflict: not managed by nomad pack")
+			conflicts++
+		}
+		tbl.Rows = append(tbl.Rows, []string{j.jobID, j.namespace, j.tplName, existingStatus, result})
+	}
+	return tbl, conflicts
+}
@@ -0,0 +1,471 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"html/template"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad-pack/terminal"
+)
+
+const (
+	// statusFormatTable outputs the status as aligned tables in the terminal.
+	statusFormatTable = "table"
+
+	// statusFormatHTML outputs the status as a self-contained HTML report.
+	statusFormatHTML = "html"
+
+	// statusFormatJUnit outputs the health of each job as a JUnit XML test
+	// report.
+	statusFormatJUnit = "junit"
+
+	// statusFormatJSON outputs the status of each job as JSON, along with the
+	// Nomad index the status is current as of.
+	statusFormatJSON = "json"
+
+	// statusFormatInflux outputs the status of each job as a point in the
+	// InfluxDB line protocol.
+	statusFormatInflux = "influx"
+
+	// statusFormatCSV outputs the tables as CSV, in the same way as --csv.
+	statusFormatCSV = "csv"
+
+	// statusFormatMarkdown outputs the tables as GitHub Flavored Markdown
+	// tables.
+	statusFormatMarkdown = "markdown"
+)
+
+// statusReport holds the data output by the report formats. Each format uses
+// the subset of the data relevant to it.
+type statusReport struct {
+	// title describes the report, such as the pack the status is of.
+	title string
+
+	// packName is the name of the pack the jobs were deployed from. It is
+	// empty when the report lists all deployed packs.
+	packName string
+
+	// deploymentName is the deployment the jobs were filtered by, if any.
+	deploymentName string
+
+	// index is the Nomad index the jobs are current as of.
+	index uint64
+
+	packJobs []JobStatusInfo
+	jobErrs  []JobStatusError
+
+	// tables are the same tables output by the table format.
+	tables []statusReportTable
+
+	// headerMap renames the column headers of the tables, keyed by their
+	// default header.
+	headerMap map[string]string
+}
+
+// formatStatusReport formats the report using the passed report format.
+func formatStatusReport(format string, report *statusReport, now time.Time) (string, error) {
+	switch format {
+	case statusFormatHTML:
+		return formatHTMLReport(report.title, now, report.tables, report.headerMap)
+	case statusFormatJUnit:
+		return formatJUnitReport(report.packName, now, report.packJobs, report.jobErrs)
+	case statusFormatJSON:
+		return formatJSONReport(report)
+	case statusFormatInflux:
+		return formatInfluxReport(report.packName, now, report.packJobs, report.jobErrs), nil
+	default:
+		return "", fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+// formatJSONReport formats the status of the pack jobs as the same summary
+// written to syslog, along with the index the status is current as of.
+func formatJSONReport(report *statusReport) (string, error) {
+	summary := newStatusSummary(report.packName, report.deploymentName, report.packJobs, report.jobErrs)
+	summary.Index = report.index
+
+	out, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(out) + "\n", nil
+}
+
+// statusReportTable is a titled table included within a status report.
+type statusReportTable struct {
+	title string
+	tbl   *terminal.Table
+}
+
+// statusColumnKeys are the keys used to refer to the status table columns
+// when renaming them using the --header-map flag, mapped to the default header
+// of the column.
+var statusColumnKeys = map[string]string{
+	"pack":           "Pack Name",
+	"registry":       "Registry Name",
+	"deployment":     "Deployment Name",
+	"ref":            "Pack Ref",
+	"job":            "Job Name",
+	"namespace":      "Namespace",
+	"version":        "Version",
+	"modify_index":   "Modify Index",
+	"status":         "Status",
+	"alloc":          "Alloc ID",
+	"node_id":        "Node ID",
+	"node":           "Node Name",
+	"task_group":     "Task Group",
+	"desired":        "Desired",
+	"enabled":        "Enabled",
+	"min":            "Min",
+	"max":            "Max",
+	"running":        "Running",
+	"pending":        "Pending",
+	"failed":         "Failed",
+	"in_bounds":      "In Bounds",
+	"eval":           "Eval ID",
+	"triggered_by":   "Triggered By",
+	"eval_status":    "Eval Status",
+	"failures":       "Placement Failures",
+	"registry_check": "Registry Check",
+	"health":         "Health",
+	"explanation":    "Explanation",
+	"error":          "Error",
+}
+
+// parseHeaderMap parses a comma separated list of key=header pairs, such as
+// "job=Job ID,status=State", into a map of the new headers keyed by the
+// default header of the column. The keys must be one of statusColumnKeys.
+func parseHeaderMap(in string) (map[string]string, error) {
+	if in == "" {
+		return nil, nil
+	}
+
+	out := make(map[string]string)
+	for _, pair := range strings.Split(in, ",") {
+		key, header, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid header mapping %q, expected the form key=header", pair)
+		}
+		column, ok := statusColumnKeys[key]
+		if !ok {
+			return nil, fmt.Errorf("unknown column key %q, must be one of: %s",
+				key, strings.Join(slices.Sorted(maps.Keys(statusColumnKeys)), ", "))
+		}
+		out[column] = strings.TrimSpace(header)
+	}
+	return out, nil
+}
+
+// mapHeaders returns the headers renamed using the header map. Headers which
+// are not in the map are unchanged.
+func mapHeaders(headers []string, headerMap map[string]string) []string {
+	out := make([]string, len(headers))
+	for i, h := range headers {
+		if mapped, ok := headerMap[h]; ok {
+			out[i] = mapped
+		} else {
+			out[i] = h
+		}
+	}
+	return out
+}
+
+// parseColumns resolves the columns passed using the --columns flag to the
+// table headers. Each column is either a header or a statusColumnKeys key, and
+// is matched regardless of case, but must be one of the headers.
+func parseColumns(columns, headers []string) ([]string, error) {
+	out := make([]string, 0, len(columns))
+	for _, column := range columns {
+		column = strings.TrimSpace(column)
+		header := column
+		if h, ok := statusColumnKeys[strings.ToLower(column)]; ok {
+			header = h
+		}
+		i := slices.IndexFunc(headers, func(h string) bool { return strings.EqualFold(h, header) })
+		if i < 0 {
+			return nil, fmt.Errorf("unknown column %q, must be one of: %s", column, strings.Join(headers, ", "))
+		}
+		out = append(out, headers[i])
+	}
+	return out, nil
+}
+
+// selectColumns returns a table with only the named columns of tbl, in the
+// order they are named. Columns which are not in tbl are ignored.
+func selectColumns(tbl *terminal.Table, columns []string) *terminal.Table {
+	var indexes []int
+	for _, column := range columns {
+		if i := slices.Index(tbl.Headers, column); i >= 0 {
+			indexes = append(indexes, i)
+		}
+	}
+
+	out := &terminal.Table{Headers: make([]string, len(indexes))}
+	for j, i := range indexes {
+		out.Headers[j] = tbl.Headers[i]
+	}
+	for _, row := range tbl.Rows {
+		selected := make([]string, len(indexes))
+		for j, i := range indexes {
+			selected[j] = row[i]
+		}
+		out.Rows = append(out.Rows, selected)
+	}
+	return out
+}
+
+// reANSI matches the terminal color escape sequences which may be included in
+// table cells, so they can be removed from reports.
+var reANSI = regexp.MustCompile(ansi)
+
+// statusClass returns the CSS class used to color a status cell, based on
+// whether the job or allocation status is healthy, in progress, or failed.
+func statusClass(status string) string {
+	switch strings.ToLower(status) {
+	case "running", "complete", "successful":
+		return "status-ok"
+	case "pending", "queued", "starting":
+		return "status-pending"
+	case "dead", "failed", "lost", "unknown":
+		return "status-failed"
+	default:
+		return ""
+	}
+}
+
+// htmlReportCell is a single table cell within the HTML report.
+type htmlReportCell struct {
+	Value string
+	Class string
+}
+
+// htmlReportTable is a single table within the HTML report.
+type htmlReportTable struct {
+	Title   string
+	Headers []string
+	Rows    [][]htmlReportCell
+}
+
+var htmlReportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{ .Title }}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #1f2328; }
+h1 { font-size: 1.5em; }
+h2 { font-size: 1.2em; margin-top: 2em; }
+p.generated { color: #656d76; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #d0d7de; padding: 6px 12px; text-align: left; }
+th { background-color: #f6f8fa; }
+td.status-ok { background-color: #dafbe1; color: #1a7f37; }
+td.status-pending { background-color: #fff8c5; color: #9a6700; }
+td.status-failed { background-color: #ffebe9; color: #cf222e; }
+</style>
+</head>
+<body>
+<h1>{{ .Title }}</h1>
+<p class="generated">Generated at {{ .Generated }}</p>
+{{- range .Tables }}
+<h2>{{ .Title }}</h2>
+<table>
+<thead>
+<tr>{{ range .Headers }}<th>{{ . }}</th>{{ end }}</tr>
+</thead>
+<tbody>
+{{- range .Rows }}
+<tr>{{ range . }}<td{{ with .Class }} class="{{ . }}"{{ end }}>{{ .Value }}</td>{{ end }}</tr>
+{{- end }}
+</tbody>
+</table>
+{{- end }}
+</body>
+</html>
+`))
+
+// formatHTMLReport returns a self-contained HTML document containing the
+// tables, with their headers renamed using the header map. Cells in a "Status"
+// column are colored by their value, and all values are HTML escaped.
+func formatHTMLReport(title string, generated time.Time, tables []statusReportTable, headerMap map[string]string) (string, error) {
+	data := struct {
+		Title     string
+		Generated string
+		Tables    []htmlReportTable
+	}{
+		Title:     title,
+		Generated: generated.UTC().Format(time.RFC3339),
+	}
+
+	for _, t := range tables {
+		statusCol := slices.Index(t.tbl.Headers, "Status")
+
+		out := htmlReportTable{Title: t.title, Headers: mapHeaders(t.tbl.Headers, headerMap)}
+		for _, row := range t.tbl.Rows {
+			cells := make([]htmlReportCell, len(row))
+			for i, value := range row {
+				cells[i].Value = reANSI.ReplaceAllString(value, "")
+				if i == statusCol {
+					cells[i].Class = statusClass(cells[i].Value)
+				}
+			}
+			out.Rows = append(out.Rows, cells)
+		}
+		data.Tables = append(data.Tables, out)
+	}
+
+	var b strings.Builder
+	if err := htmlReportTemplate.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// junitTestSuites is the root element of a JUnit XML report.
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string       `xml:"name,attr"`
+	ClassName string       `xml:"classname,attr"`
+	Failure   *junitResult `xml:"failure,omitempty"`
+	Error     *junitResult `xml:"error,omitempty"`
+}
+
+type junitResult struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// formatJUnitReport returns a JUnit XML report with a test suite named after
+// the pack, containing a test case for each job. Test cases pass when the job
+// is healthy, and otherwise fail with the job status as the message. Jobs
+// whose status could not be retrieved are reported as errored test cases.
+func formatJUnitReport(packName string, now time.Time, packJobs []JobStatusInfo, jobErrs []JobStatusError) (string, error) {
+	suite := junitTestSuite{
+		Name:      packName,
+		Timestamp: now.UTC().Format(time.RFC3339),
+	}
+
+	for _, info := range packJobs {
+		tc := junitTestCase{
+			Name:      info.jobID,
+			ClassName: packName + "." + info.deploymentName,
+		}
+		if info.health != jobHealthy {
+			tc.Failure = &junitResult{
+				Message: info.status,
+				Type:    info.health.String(),
+				Text:    info.healthReason,
+			}
+			suite.Failures++
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+
+	for _, jobErr := range jobErrs {
+		suite.Cases = append(suite.Cases, junitTestCase{
+			Name:      jobErr.jobID,
+			ClassName: packName,
+			Error: &junitResult{
+				Message: jobErr.jobError.Error(),
+				Type:    "error",
+			},
+		})
+		suite.Errors++
+	}
+	suite.Tests = len(suite.Cases)
+
+	out, err := xml.MarshalIndent(junitTestSuites{
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Errors:   suite.Errors,
+		Suites:   []junitTestSuite{suite},
+	}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return xml.Header + string(out) + "\n", nil
+}
+
+// influxMeasurement is the measurement of the points output by the influx
+// format.
+const influxMeasurement = "nomad_pack_job"
+
+var (
+	// influxTagEscaper escapes the tag keys, tag values, and field keys of a
+	// line protocol point.
+	influxTagEscaper = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `)
+
+	// influxStringEscaper escapes the string field values of a line protocol
+	// point, which are enclosed in double quotes.
+	influxStringEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+)
+
+// formatInfluxReport formats the status of each pack job as a point in the
+// InfluxDB line protocol, such as:
+//
+//	nomad_pack_job,pack=x,registry=y,job=z status="running",health="healthy",version=1i 1704164645000000000
+//
+// Jobs whose status could not be retrieved are output with an error field
+// instead. Tags with an empty value are omitted, as the line protocol does not
+// allow them.
+func formatInfluxReport(packName string, now time.Time, packJobs []JobStatusInfo, jobErrs []JobStatusError) string {
+	var b strings.Builder
+	ts := now.UnixNano()
+
+	writePoint := func(tags [][2]string, fields string) {
+		b.WriteString(influxMeasurement)
+		for _, tag := range tags {
+			if tag[1] == "" {
+				continue
+			}
+			fmt.Fprintf(&b, ",%s=%s", tag[0], influxTagEscaper.Replace(tag[1]))
+		}
+		fmt.Fprintf(&b, " %s %d\n", fields, ts)
+	}
+
+	for _, info := range packJobs {
+		writePoint([][2]string{
+			{"pack", packName},
+			{"registry", info.registryName},
+			{"deployment", info.deploymentName},
+			{"namespace", info.namespace},
+			{"job", info.jobID},
+		}, fmt.Sprintf(`status="%s",health="%s",version=%di`,
+			influxStringEscaper.Replace(info.status), info.health, info.version))
+	}
+
+	for _, jobErr := range jobErrs {
+		writePoint([][2]string{
+			{"pack", packName},
+			{"job", jobErr.jobID},
+		}, fmt.Sprintf(`error="%s"`, influxStringEscaper.Replace(jobErr.jobError.Error())))
+	}
+	return b.String()
+}
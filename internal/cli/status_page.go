@@ -0,0 +1,84 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"cmp"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"slices"
+)
+
+// jobPageKey is the position of a job within the stable order used to page
+// through the jobs of a pack. Jobs are ordered by namespace and then ID, which
+// uniquely identify a job, so a page continues from the same position even if
+// jobs before it are added or removed.
+type jobPageKey struct {
+	Namespace string `json:"ns"`
+	JobID     string `json:"job"`
+}
+
+func jobPageKeyOf(info JobStatusInfo) jobPageKey {
+	return jobPageKey{Namespace: info.namespace, JobID: info.jobID}
+}
+
+func (k jobPageKey) compare(other jobPageKey) int {
+	return cmp.Or(
+		cmp.Compare(k.Namespace, other.Namespace),
+		cmp.Compare(k.JobID, other.JobID),
+	)
+}
+
+// encodePageToken returns the opaque token which continues paging after the
+// job with the key.
+func encodePageToken(k jobPageKey) string {
+	b, _ := json.Marshal(k)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodePageToken returns the key of the job the token continues paging
+// after.
+func decodePageToken(token string) (jobPageKey, error) {
+	var k jobPageKey
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return k, errors.New("invalid page token")
+	}
+	if err := json.Unmarshal(b, &k); err != nil || k.JobID == "" {
+		return k, errors.New("invalid page token")
+	}
+	return k, nil
+}
+
+// pageJobs returns a page of at most limit jobs, ordered by namespace and ID,
+// which follow the position encoded in the page token. An empty token starts
+// from the first job, and a limit of 0 returns all the remaining jobs. The
+// token of the next page is returned when jobs remain after the page, and is
+// empty otherwise.
+func pageJobs(packJobs []JobStatusInfo, token string, limit int) ([]JobStatusInfo, string, error) {
+	packJobs = slices.Clone(packJobs)
+	slices.SortFunc(packJobs, func(a, b JobStatusInfo) int {
+		return jobPageKeyOf(a).compare(jobPageKeyOf(b))
+	})
+
+	if token != "" {
+		after, err := decodePageToken(token)
+		if err != nil {
+			return nil, "", err
+		}
+		start, _ := slices.BinarySearchFunc(packJobs, after, func(info JobStatusInfo, k jobPageKey) int {
+			return jobPageKeyOf(info).compare(k)
+		})
+		for start < len(packJobs) && jobPageKeyOf(packJobs[start]).compare(after) <= 0 {
+			start++
+		}
+		packJobs = packJobs[start:]
+	}
+
+	if limit == 0 || len(packJobs) <= limit {
+		return packJobs, "", nil
+	}
+	return packJobs[:limit], encodePageToken(jobPageKeyOf(packJobs[limit-1])), nil
+}
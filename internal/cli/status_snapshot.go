@@ -0,0 +1,146 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/hashicorp/nomad-pack/internal/pkg/cache"
+	"github.com/hashicorp/nomad/api"
+)
+
+// deployedPacksSnapshot is the last known result of listing the packs deployed
+// to a cluster, which is stored on disk so it can be output without querying
+// the cluster using --cached.
+type deployedPacksSnapshot struct {
+	Address   string              `json:"address"`
+	Namespace string              `json:"namespace,omitempty"`
+	Time      time.Time           `json:"time"`
+	Packs     map[string][]string `json:"packs"`
+}
+
+// getDeployedPacks returns the packs deployed to the cluster. When --cached is
+// set, a snapshot taken within --cache-ttl is returned rather than querying
+// the cluster. Otherwise the cluster is queried, and the snapshot is updated
+// when --cached or --refresh is set. If the query fails, an expired snapshot
+// is returned along with a warning that it is stale.
+func (c *StatusCommand) getDeployedPacks(client *api.Client) (map[string]map[string]struct{}, error) {
+	if !c.cached && !c.refresh {
+		return getDeployedPacks(client, c.packMetaKeys())
+	}
+
+	conf := clientOptsFromCLI(c.baseCommand)
+	dir := cache.DefaultStatusCachePath()
+	now := time.Now()
+
+	var snapshot *deployedPacksSnapshot
+	if c.cached {
+		if s, err := readDeployedPacksSnapshot(dir, conf.Address, conf.Namespace); err == nil {
+			snapshot = s
+			if now.Sub(s.Time) < c.cacheTTL {
+				if _, stderr, err := c.ui.OutputWriters(); err == nil {
+					fmt.Fprintf(stderr, "Using the deployed packs snapshot taken %s ago, refresh it with --refresh\n",
+						formatTimeDifference(s.Time, now, time.Second))
+				}
+				return s.packRegistryMap(), nil
+			}
+		}
+	}
+
+	packRegistryMap, err := getDeployedPacks(client, c.packMetaKeys())
+	if err != nil {
+		if snapshot == nil {
+			return nil, err
+		}
+		c.ui.Warning(fmt.Sprintf("%s; using the stale deployed packs snapshot taken %s ago",
+			err, formatTimeDifference(snapshot.Time, now, time.Second)))
+		return snapshot.packRegistryMap(), nil
+	}
+
+	// Writing the snapshot is best effort, as the live result is still output.
+	if err := writeDeployedPacksSnapshot(dir, newDeployedPacksSnapshot(conf.Address, conf.Namespace, packRegistryMap, now)); err != nil {
+		c.ui.Warning(fmt.Sprintf("failed to write deployed packs snapshot: %s", err))
+	}
+	return packRegistryMap, nil
+}
+
+// newDeployedPacksSnapshot returns a snapshot of the deployed packs, mapped to
+// the registries they were deployed from, taken at the passed time.
+func newDeployedPacksSnapshot(address, namespace string, packRegistryMap map[string]map[string]struct{}, now time.Time) *deployedPacksSnapshot {
+	s := &deployedPacksSnapshot{
+		Address:   address,
+		Namespace: namespace,
+		Time:      now.UTC(),
+		Packs:     make(map[string][]string, len(packRegistryMap)),
+	}
+	for packName, registries := range packRegistryMap {
+		s.Packs[packName] = slices.Sorted(maps.Keys(registries))
+	}
+	return s
+}
+
+// packRegistryMap returns the deployed packs of the snapshot in the form
+// returned by getDeployedPacks.
+func (s *deployedPacksSnapshot) packRegistryMap() map[string]map[string]struct{} {
+	out := make(map[string]map[string]struct{}, len(s.Packs))
+	for packName, registries := range s.Packs {
+		out[packName] = make(map[string]struct{}, len(registries))
+		for _, registry := range registries {
+			out[packName][registry] = struct{}{}
+		}
+	}
+	return out
+}
+
+// deployedPacksSnapshotPath returns the path of the snapshot of the cluster
+// at the address, scoped to the namespace, within dir.
+func deployedPacksSnapshotPath(dir, address, namespace string) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%d:%s;%d:%s;", len(address), address, len(namespace), namespace)
+	return filepath.Join(dir, hex.EncodeToString(h.Sum(nil))+".json")
+}
+
+// readDeployedPacksSnapshot reads the snapshot of the cluster at the address,
+// scoped to the namespace, from dir.
+func readDeployedPacksSnapshot(dir, address, namespace string) (*deployedPacksSnapshot, error) {
+	src, err := os.ReadFile(deployedPacksSnapshotPath(dir, address, namespace))
+	if err != nil {
+		return nil, err
+	}
+
+	var s deployedPacksSnapshot
+	if err := json.Unmarshal(src, &s); err != nil {
+		return nil, fmt.Errorf("invalid deployed packs snapshot: %w", err)
+	}
+	return &s, nil
+}
+
+// writeDeployedPacksSnapshot writes the snapshot to dir, replacing any
+// previous snapshot of the same cluster and namespace.
+func writeDeployedPacksSnapshot(dir string, s *deployedPacksSnapshot) error {
+	out, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so a concurrent reader never sees a
+	// partially written snapshot.
+	p := deployedPacksSnapshotPath(dir, s.Address, s.Namespace)
+	tmp := p + ".tmp"
+	if err := os.WriteFile(tmp, out, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, p)
+}
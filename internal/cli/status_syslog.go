@@ -0,0 +1,134 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// syslogTag is the tag the status summaries are written to syslog with.
+const syslogTag = "nomad-pack"
+
+// syslogFacilities are the names of the syslog facilities which can be passed
+// using the --syslog-facility flag.
+var syslogFacilities = []string{
+	"kern", "user", "mail", "daemon", "auth", "syslog", "lpr", "news", "uucp",
+	"cron", "authpriv", "ftp", "local0", "local1", "local2", "local3",
+	"local4", "local5", "local6", "local7",
+}
+
+// syslogSeverities are the names of the syslog severities which can be passed
+// using the --syslog-priority flag.
+var syslogSeverities = []string{
+	"emerg", "alert", "crit", "err", "warning", "notice", "info", "debug",
+}
+
+// statusSummary is the structured summary of the status of a pack, which is
+// serialized as JSON.
+type statusSummary struct {
+	Pack       string                `json:"pack"`
+	Deployment string                `json:"deployment,omitempty"`
+	Health     string                `json:"health"`
+	Jobs       []statusSummaryJob    `json:"jobs"`
+	Errors     []statusSummaryJobErr `json:"errors,omitempty"`
+
+	// Index is the Nomad index the status is current as of, which is passed
+	// to --since-index to only output the jobs which change after it. It is
+	// omitted from the summary written to syslog.
+	Index uint64 `json:"index,omitempty"`
+}
+
+// statusSummaryJob is the status of a single job within a statusSummary.
+type statusSummaryJob struct {
+	ID           string `json:"id"`
+	Namespace    string `json:"namespace,omitempty"`
+	Pack         string `json:"pack"`
+	Registry     string `json:"registry"`
+	Deployment   string `json:"deployment"`
+	PackRef      string `json:"packRef,omitempty"`
+	Status       string `json:"status"`
+	Health       string `json:"health"`
+	HealthReason string `json:"healthReason,omitempty"`
+}
+
+// statusSummaryJobErr is a job whose status could not be retrieved.
+type statusSummaryJobErr struct {
+	ID    string `json:"id"`
+	Error string `json:"error"`
+}
+
+// newStatusSummary returns the summary of the status of the pack jobs, and
+// the jobs whose status could not be retrieved.
+func newStatusSummary(packName, deploymentName string, packJobs []JobStatusInfo, jobErrs []JobStatusError) *statusSummary {
+	s := &statusSummary{
+		Pack:       packName,
+		Deployment: deploymentName,
+		Health:     packHealth(packJobs).String(),
+		Jobs:       make([]statusSummaryJob, 0, len(packJobs)),
+	}
+	for _, info := range packJobs {
+		s.Jobs = append(s.Jobs, statusSummaryJob{
+			ID:           info.jobID,
+			Namespace:    info.namespace,
+			Pack:         info.packName,
+			Registry:     info.registryName,
+			Deployment:   info.deploymentName,
+			PackRef:      info.packRef,
+			Status:       info.status,
+			Health:       info.health.String(),
+			HealthReason: info.healthReason,
+		})
+	}
+	for _, jobErr := range jobErrs {
+		s.Errors = append(s.Errors, statusSummaryJobErr{ID: jobErr.jobID, Error: jobErr.jobError.Error()})
+	}
+	return s
+}
+
+// parseSyslogAddress parses the address passed using --syslog-address, such
+// as udp://logs.example.com:514, into the network and address to dial. The
+// network defaults to udp when not specified. An empty address connects to
+// the local syslog server.
+func parseSyslogAddress(addr string) (string, string, error) {
+	if addr == "" {
+		return "", "", nil
+	}
+
+	network, host, ok := strings.Cut(addr, "://")
+	if !ok {
+		network, host = "udp", addr
+	}
+	switch network {
+	case "udp", "tcp":
+	default:
+		return "", "", fmt.Errorf("unsupported syslog network %q, must be udp or tcp", network)
+	}
+	if host == "" {
+		return "", "", fmt.Errorf("invalid syslog address %q", addr)
+	}
+	return network, host, nil
+}
+
+// writeSyslog writes the status summary to syslog as JSON. Failing to connect
+// or write to syslog only results in a warning, so the status is still output.
+func (c *StatusCommand) writeSyslog(summary *statusSummary) {
+	b, err := json.Marshal(summary)
+	if err != nil {
+		c.ui.Warning(fmt.Sprintf("failed to write status to syslog: %s", err))
+		return
+	}
+
+	w, err := dialSyslog(c.syslogAddress, c.syslogFacility, c.syslogPriority)
+	if err != nil {
+		c.ui.Warning(fmt.Sprintf("failed to connect to syslog: %s", err))
+		return
+	}
+	defer w.Close()
+
+	if _, err := w.Write(b); err != nil {
+		c.ui.Warning(fmt.Sprintf("failed to write status to syslog: %s", err))
+	}
+}
@@ -0,0 +1,14 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"errors"
+	"io"
+)
+
+// dialSyslog always fails, since syslog is not available on Windows.
+func dialSyslog(_, _, _ string) (io.WriteCloser, error) {
+	return nil, errors.New("syslog is not supported on windows")
+}
@@ -0,0 +1,138 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/hashicorp/nomad/api"
+
+	"github.com/hashicorp/nomad-pack/internal/pkg/cache"
+	"github.com/hashicorp/nomad-pack/internal/pkg/errors"
+)
+
+// statusTreeNode is a single node of the tree output by status --tree, such
+// as a registry, pack, deployment, or job.
+type statusTreeNode struct {
+	label    string
+	children []*statusTreeNode
+}
+
+// treeGlyphs are the prefixes used to draw the branches of the tree. Each node
+// is prefixed with branch, or last when it is the last of its siblings, and
+// its children are indented with pipe, or space below the last sibling.
+type treeGlyphs struct {
+	branch, last, pipe, space string
+}
+
+var (
+	// unicodeTreeGlyphs draw the tree using box drawing characters.
+	unicodeTreeGlyphs = treeGlyphs{branch: "├── ", last: "└── ", pipe: "│   ", space: "    "}
+
+	// asciiTreeGlyphs draw the tree using ASCII characters, which is used
+	// when the output is not colored.
+	asciiTreeGlyphs = treeGlyphs{branch: "|-- ", last: "`-- ", pipe: "|   ", space: "    "}
+)
+
+// renderDeployedPacksTree outputs every deployed pack job within a tree of
+// the registries, packs, and deployments they belong to.
+func (c *StatusCommand) renderDeployedPacksTree(client *api.Client, errorContext *errors.UIErrorContext) int {
+	packJobs, jobErrs, err := getDeployedPackJobs(client, &cache.PackConfig{}, "", c.packMetaKeys(), c.failFast)
+	if err != nil {
+		c.ui.ErrorWithContext(err, "error retrieving jobs", errorContext.GetAll()...)
+		return 1
+	}
+
+	if len(packJobs) == 0 {
+		c.ui.Warning("no packs found")
+		return 0
+	}
+
+	glyphs := unicodeTreeGlyphs
+	if color.NoColor {
+		glyphs = asciiTreeGlyphs
+	}
+	c.ui.Output(formatStatusTree(buildDeployedPacksTree(packJobs), glyphs))
+
+	if len(jobErrs) > 0 {
+		c.ui.WarningBold("error retrieving job status for the following jobs:")
+		c.renderTable(formatDeployedPackErrs(jobErrs))
+	}
+	return 0
+}
+
+// buildDeployedPacksTree groups the pack jobs by their registry, pack, and
+// deployment. Each level of the tree is ordered by name, and the jobs are
+// ordered by ID.
+func buildDeployedPacksTree(packJobs []JobStatusInfo) []*statusTreeNode {
+	grouped := make(map[string]map[string]map[string][]JobStatusInfo)
+	for _, info := range packJobs {
+		if grouped[info.registryName] == nil {
+			grouped[info.registryName] = make(map[string]map[string][]JobStatusInfo)
+		}
+		if grouped[info.registryName][info.packName] == nil {
+			grouped[info.registryName][info.packName] = make(map[string][]JobStatusInfo)
+		}
+		deployments := grouped[info.registryName][info.packName]
+		deployments[info.deploymentName] = append(deployments[info.deploymentName], info)
+	}
+
+	var out []*statusTreeNode
+	for _, registryName := range slices.Sorted(maps.Keys(grouped)) {
+		registryNode := &statusTreeNode{label: "registry " + registryName}
+		packs := grouped[registryName]
+
+		for _, packName := range slices.Sorted(maps.Keys(packs)) {
+			packNode := &statusTreeNode{label: "pack " + packName}
+			deployments := packs[packName]
+
+			for _, deploymentName := range slices.Sorted(maps.Keys(deployments)) {
+				// Jobs deployed without a deployment name are grouped
+				// together.
+				deploymentNode := &statusTreeNode{label: "deployment " + cmp.Or(deploymentName, "(none)")}
+
+				jobs := slices.SortedFunc(slices.Values(deployments[deploymentName]), func(a, b JobStatusInfo) int {
+					return cmp.Compare(a.jobID, b.jobID)
+				})
+				for _, info := range jobs {
+					deploymentNode.children = append(deploymentNode.children,
+						&statusTreeNode{label: fmt.Sprintf("%s: %s", info.jobID, info.status)})
+				}
+				packNode.children = append(packNode.children, deploymentNode)
+			}
+			registryNode.children = append(registryNode.children, packNode)
+		}
+		out = append(out, registryNode)
+	}
+	return out
+}
+
+// formatStatusTree formats the tree, writing each root without a prefix and
+// indenting the descendants of each using the glyphs.
+func formatStatusTree(roots []*statusTreeNode, glyphs treeGlyphs) string {
+	var b strings.Builder
+	for _, root := range roots {
+		b.WriteString(root.label + "\n")
+		writeStatusTree(&b, root.children, "", glyphs)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// writeStatusTree writes the nodes and their descendants, each prefixed with
+// the indentation of their parents.
+func writeStatusTree(b *strings.Builder, nodes []*statusTreeNode, prefix string, glyphs treeGlyphs) {
+	for i, n := range nodes {
+		branch, indent := glyphs.branch, glyphs.pipe
+		if i == len(nodes)-1 {
+			branch, indent = glyphs.last, glyphs.space
+		}
+		b.WriteString(prefix + branch + n.label + "\n")
+		writeStatusTree(b, n.children, prefix+indent, glyphs)
+	}
+}
@@ -41,6 +41,9 @@ func (c *StopCommand) Run(args []string) int {
 		return 1
 	}
 
+	// Output any errors held back by --group-errors once the run completes.
+	defer c.flushErrors()
+
 	// Since we call this command from destroy, set up the correct verbiage
 	// for nicer output
 	var (
@@ -58,6 +61,7 @@ func (c *StopCommand) Run(args []string) int {
 
 	// Set the packConfig defaults if necessary and generate our UI error context.
 	errorContext := initPackCommand(c.packConfig)
+	defer c.packConfig.Cleanup()
 
 	client, err := c.getAPIClient()
 	if err != nil {
@@ -0,0 +1,77 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// commandSummary is the summary of a command run written to the file
+// descriptor passed using --summary-fd.
+type commandSummary struct {
+	Command  string               `json:"command"`
+	ExitCode int                  `json:"exitCode"`
+	Counts   commandSummaryCounts `json:"counts"`
+	Warnings []string             `json:"warnings"`
+}
+
+// commandSummaryCounts are the number of warnings and errors output by a
+// command run.
+type commandSummaryCounts struct {
+	Warnings int `json:"warnings"`
+	Errors   int `json:"errors"`
+}
+
+// openSummaryFD returns the file for the file descriptor passed using
+// --summary-fd, or an error if it is not open. A file descriptor of 0
+// disables the summary, and returns a nil file.
+func openSummaryFD(fd int) (*os.File, error) {
+	switch {
+	case fd == 0:
+		return nil, nil
+	case fd < 0:
+		return nil, fmt.Errorf("--summary-fd must not be negative")
+	}
+
+	f := os.NewFile(uintptr(fd), fmt.Sprintf("fd%d", fd))
+	if _, err := f.Stat(); err != nil {
+		return nil, fmt.Errorf("--summary-fd %d is not an open file descriptor: %w", fd, err)
+	}
+	return f, nil
+}
+
+// writeSummary writes the JSON summary of the command run, which completed
+// with the exit code, to the file descriptor passed using --summary-fd. It is
+// a no-op if the flag was not passed.
+func (c *baseCommand) writeSummary(exitCode int) {
+	if c.summaryFile == nil {
+		return
+	}
+
+	s := commandSummary{Command: c.cmdKey, ExitCode: exitCode, Warnings: []string{}}
+	if c.summary != nil {
+		s.Warnings = c.summary.Warnings()
+		s.Counts.Errors = c.summary.Errors()
+	}
+	s.Counts.Warnings = len(s.Warnings)
+
+	b, err := json.Marshal(s)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode command summary: %s\n", err)
+		return
+	}
+
+	if _, err := c.summaryFile.Write(append(b, '\n')); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write command summary: %s\n", err)
+	}
+
+	// Closing the file signals the end of the summary to the reader, but the
+	// standard streams remain open for any further output.
+	if c.summaryFD > 2 {
+		c.summaryFile.Close()
+	}
+	c.summaryFile = nil
+}
@@ -0,0 +1,296 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cli
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+
+	"github.com/hashicorp/nomad-pack/internal/pkg/cache"
+	"github.com/hashicorp/nomad-pack/internal/pkg/errors"
+	"github.com/hashicorp/nomad-pack/internal/pkg/flag"
+	"github.com/hashicorp/nomad-pack/internal/runner"
+	"github.com/hashicorp/nomad-pack/internal/runner/job"
+)
+
+// VerifyDeploymentCommand renders a deployed pack using the ref and variables
+// it was deployed with, and compares the jobs with the jobs running in Nomad.
+type VerifyDeploymentCommand struct {
+	*baseCommand
+	packConfig *cache.PackConfig
+	jobConfig  *job.CLIConfig
+}
+
+func (c *VerifyDeploymentCommand) Run(args []string) int {
+	c.cmdKey = "verify-deployment" // Add cmdKey here to print out helpUsageMessage on Init error
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithExactArgs(1, args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+	); err != nil {
+		c.ui.ErrorWithContext(err, ErrParsingArgsOrFlags)
+		c.ui.Info(c.helpUsageMessage())
+		return runner.PlanCodeError
+	}
+
+	// Output any errors held back by --group-errors once the run completes.
+	defer c.flushErrors()
+
+	c.packConfig.Name = c.args[0]
+
+	// Set the packConfig defaults if necessary and generate our UI error context.
+	errorContext := initPackCommand(c.packConfig)
+	defer c.packConfig.Cleanup()
+
+	// If no deploymentName set default to pack@ref
+	c.deploymentName = getDeploymentName(c.baseCommand, c.packConfig)
+	errorContext.Add(errors.UIContextPrefixDeploymentName, c.deploymentName)
+
+	client, err := c.getAPIClient()
+	if err != nil {
+		c.ui.ErrorWithContext(err, "failed to initialize client", errorContext.GetAll()...)
+		return runner.PlanCodeError
+	}
+
+	deployedJobs, err := getPackJobsByDeploy(client, c.packConfig, c.deploymentName)
+	if err == nil && len(deployedJobs) == 0 {
+		err = fmt.Errorf("no jobs found for deployment %q", c.deploymentName)
+	}
+	if err != nil {
+		c.ui.ErrorWithContext(err, "failed to find deployed pack", errorContext.GetAll()...)
+		return runner.PlanCodeError
+	}
+	deployedMeta := deployedJobs[0].Meta
+
+	// Render the pack at the ref it was deployed with. Packs loaded from a
+	// directory have no ref, so they are rendered as they are.
+	if ref := deployedMeta[job.PackRefKey]; ref != "" && c.packConfig.Registry != cache.DevRegistryName && ref != c.packConfig.Ref {
+		c.packConfig.Ref = ref
+		errorContext = initPackCommand(c.packConfig)
+		errorContext.Add(errors.UIContextPrefixDeploymentName, c.deploymentName)
+	}
+
+	if err := cache.VerifyPackExists(c.packConfig, errorContext, c.ui); err != nil {
+		return runner.PlanCodeError
+	}
+
+	deployedVars, err := getDeployedVariables(client, deployedJobs)
+	if err != nil {
+		c.ui.ErrorWithContext(err, "failed to read deployed variables", errorContext.GetAll()...)
+		return runner.PlanCodeError
+	}
+	if deployedVars == nil {
+		c.ui.Warning(fmt.Sprintf("Deployment %q did not record its variables; the pack is rendered using the passed variables only", c.deploymentName))
+	} else {
+		cleanup, err := c.useDeployedVariables(deployedVars)
+		if err != nil {
+			c.ui.ErrorWithContext(err, "failed to write deployed variables", errorContext.GetAll()...)
+			return runner.PlanCodeError
+		}
+		defer cleanup()
+	}
+
+	packManager := generatePackManager(c.baseCommand, client, c.packConfig)
+
+	r, err := renderPack(packManager, c.ui, false, false, c.ignoreMissingVars, errorContext)
+	if err != nil {
+		return runner.PlanCodeError
+	}
+
+	// Commands that render templates are required to render at least one
+	// parent template.
+	if r.LenParentRenders() < 1 {
+		c.ui.ErrorWithContext(errors.ErrNoTemplatesRendered, "no templates rendered", errorContext.GetAll()...)
+		return runner.PlanCodeError
+	}
+
+	// The pack metadata is set as it was deployed, so it is not reported as
+	// drift when the pack is rendered from elsewhere, such as another cache.
+	depConfig := runner.Config{
+		PackName:       c.packConfig.Name,
+		PathPath:       deployedMeta[job.PackPathKey],
+		PackRef:        deployedMeta[job.PackRefKey],
+		DeploymentName: c.deploymentName,
+		RegistryName:   deployedMeta[job.PackRegistryKey],
+	}
+
+	jobRunner, err := generateRunner(client, "job", c.jobConfig, &depConfig)
+	if err != nil {
+		c.ui.ErrorWithContext(err, "failed to generate deployer", errorContext.GetAll()...)
+		return runner.PlanCodeError
+	}
+
+	jobRunner.SetTemplates(r.ParentRenders())
+
+	if parseErrs := jobRunner.ParseTemplates(); parseErrs != nil {
+		for _, parseErr := range parseErrs {
+			parseErr.Context.Append(errorContext)
+			c.ui.ErrorWithContext(parseErr.Err, parseErr.Subject, parseErr.Context.GetAll()...)
+		}
+		return runner.PlanCodeError
+	}
+
+	if canonicalizeErrs := jobRunner.CanonicalizeTemplates(); canonicalizeErrs != nil {
+		for _, canonicalizeErr := range canonicalizeErrs {
+			canonicalizeErr.Context.Append(errorContext)
+			c.ui.ErrorWithContext(canonicalizeErr.Err, canonicalizeErr.Subject, canonicalizeErr.Context.GetAll()...)
+		}
+		return runner.PlanCodeError
+	}
+
+	drifted, verifyErrs := jobRunner.VerifyDeployment(c.ui, errorContext)
+	for _, verifyErr := range verifyErrs {
+		c.ui.ErrorWithContext(verifyErr.Err, verifyErr.Subject, verifyErr.Context.GetAll()...)
+	}
+
+	parsed, _ := jobRunner.ParsedTemplates().(map[string]job.ParsedTemplate)
+	unrendered := unrenderedJobs(deployedJobs, parsed)
+	for _, id := range unrendered {
+		c.ui.Warning(fmt.Sprintf("Job %q is running as part of the deployment but is not rendered by the pack", id))
+		drifted = append(drifted, id)
+	}
+
+	if len(verifyErrs) > 0 {
+		return runner.PlanCodeError
+	}
+	if len(drifted) > 0 {
+		c.ui.Warning(fmt.Sprintf("%d of %d jobs of deployment %q have drifted from the pack",
+			len(drifted), len(parsed)+len(unrendered), c.deploymentName))
+		return runner.PlanCodeUpdates
+	}
+	c.ui.Success(fmt.Sprintf("Deployment %q matches the pack", c.deploymentName))
+	return runner.PlanCodeNoUpdates
+}
+
+// useDeployedVariables writes the variables recorded by the deployment to a
+// variable file, which is passed before any other variable file so the
+// variables can still be overridden. The returned func removes the file.
+func (c *VerifyDeploymentCommand) useDeployedVariables(vars map[string]string) (func(), error) {
+	dir, err := os.MkdirTemp("", "nomad-pack-verify-")
+	if err != nil {
+		return nil, err
+	}
+	cleanup := func() { _ = os.RemoveAll(dir) }
+
+	path := filepath.Join(dir, "deployed.hcl")
+	if err := os.WriteFile(path, []byte(formatDeployedVarFile(vars)), 0o600); err != nil {
+		cleanup()
+		return nil, err
+	}
+	c.varFiles = append([]string{path}, c.varFiles...)
+	return cleanup, nil
+}
+
+// formatDeployedVarFile formats the variables recorded by a deployment as an
+// HCL variable file, ordered by name. The recorded values are already in HCL
+// syntax, but template sequences within strings are escaped so the values are
+// read back as they were recorded.
+func formatDeployedVarFile(vars map[string]string) string {
+	escaper := strings.NewReplacer("${", "$${", "%{", "%%{")
+
+	var b strings.Builder
+	for _, name := range slices.Sorted(maps.Keys(vars)) {
+		fmt.Fprintf(&b, "%s = %s\n", name, escaper.Replace(vars[name]))
+	}
+	return b.String()
+}
+
+// unrenderedJobs returns the IDs of the deployed jobs which are not rendered by
+// the pack, such as when a job was removed from the pack, ordered by ID.
+func unrenderedJobs(deployed []*api.Job, parsed map[string]job.ParsedTemplate) []string {
+	rendered := make(map[string]struct{}, len(parsed))
+	for _, tpl := range parsed {
+		rendered[*tpl.Job().ID] = struct{}{}
+	}
+
+	var out []string
+	for _, j := range deployed {
+		if _, ok := rendered[*j.ID]; !ok {
+			out = append(out, *j.ID)
+		}
+	}
+	slices.Sort(out)
+	return out
+}
+
+func (c *VerifyDeploymentCommand) Flags() *flag.Sets {
+	c.packConfig = &cache.PackConfig{}
+
+	return c.flagSet(flagSetOperation|flagSetNomadClient, func(set *flag.Sets) {
+		f := set.NewSet("Verify Options")
+
+		c.jobConfig = &job.CLIConfig{
+			RunConfig:  &job.RunCLIConfig{},
+			PlanConfig: &job.PlanCLIConfig{Diff: true},
+		}
+
+		f.StringVar(&flag.StringVar{
+			Name:    "registry",
+			Target:  &c.packConfig.Registry,
+			Default: "",
+			Usage:   `Specific registry name containing the deployed pack.`,
+		})
+
+		f.BoolVarP(&flag.BoolVarP{
+			BoolVar: &flag.BoolVar{
+				Name:    "verbose",
+				Target:  &c.jobConfig.PlanConfig.Verbose,
+				Default: false,
+				Usage:   `Increase diff verbosity.`,
+			},
+			Shorthand: "v",
+		})
+	})
+}
+
+func (c *VerifyDeploymentCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *VerifyDeploymentCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *VerifyDeploymentCommand) Help() string {
+	c.Example = `
+	# Verify the jobs of the dev deployment of an example pack match the pack
+	nomad-pack verify-deployment example --name=dev
+
+	# Verify a deployment, overriding a sensitive variable which is not
+	# recorded with the deployment
+	nomad-pack verify-deployment example --name=dev --var=password=secret
+	`
+
+	return formatHelp(`
+	Usage: nomad-pack verify-deployment <pack-name> [options]
+
+	Verify the jobs of a deployed pack match the pack.
+
+	The pack is rendered at the ref it was deployed with, using the variables
+	recorded by the deployment, and each job is compared with the job running
+	in Nomad. Any difference, such as a job edited outside of Nomad Pack, is
+	output as a diff. Sensitive variables are not recorded, so they must be
+	passed again using --var or --var-file. Packs deployed from a registry at
+	the latest ref are rendered at the latest ref in the cache.
+
+	Verify will return one of the following exit codes:
+		* code 0:   The jobs match the pack.
+		* code 1:   The jobs have drifted from the pack.
+		* code 255: An error occurred verifying the deployment.
+
+` + c.GetExample() + c.Flags().Help())
+}
+
+// Synopsis satisfies the Synopsis function of the cli.Command interface.
+func (c *VerifyDeploymentCommand) Synopsis() string {
+	return "Verify the jobs of a deployed pack match the pack"
+}
@@ -15,6 +15,7 @@ const (
 	FileNameReadme    = "README.md"
 	FileNameChangelog = "CHANGELOG.md"
 	FileNameVariables = "variables.hcl"
+	FileNameProfiles  = "profiles.hcl"
 
 	FolderNameTemplates = "templates"
 )
@@ -4,6 +4,7 @@
 package cache
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/fs"
@@ -21,8 +22,10 @@ import (
 
 const tmpDir = "nomad-pack-tmp"
 
-// Add adds a registry to a cache from the passed config.
-func (c *Cache) Add(opts *AddOpts) (*Registry, error) {
+// Add adds a registry to a cache from the passed config. Canceling the
+// context stops the fetch of the registry, including any git processes it
+// started, and removes the partially fetched registry.
+func (c *Cache) Add(ctx context.Context, opts *AddOpts) (*Registry, error) {
 	var cachedRegistry *Registry
 	// Throw error if cache path not defined
 	if c.cfg.Path == "" {
@@ -50,7 +53,11 @@ func (c *Cache) Add(opts *AddOpts) (*Registry, error) {
 		return cachedRegistry, errors.ErrRegistrySourceRequired
 	}
 
-	return c.addFromURI(opts)
+	if isTarballSource(opts.Source) {
+		return c.addFromTarball(ctx, opts)
+	}
+
+	return c.addFromURI(ctx, opts)
 }
 
 // addFromURI loads a registry from a remote git repository. If addToCache is
@@ -58,35 +65,31 @@ func (c *Cache) Add(opts *AddOpts) (*Registry, error) {
 // must be specified to allow user customization of cache location. If a name is
 // specified, the registry will be added with that alias, otherwise the registry
 // URL slug will be used.
-func (c *Cache) addFromURI(opts *AddOpts) (cachedRegistry *Registry, err error) {
-	// Set the logger instance to reduce boilerplate.
-	logger := c.cfg.Logger
-
+func (c *Cache) addFromURI(ctx context.Context, opts *AddOpts) (cachedRegistry *Registry, err error) {
 	// Set default revision if not defined
 	if opts.Ref == "" {
 		opts.Ref = DefaultRef
 	}
 
 	// Set up a defer function so that the temp directory always gets removed
-	defer func() {
-		// remove the tmp directory
-		if _, sErr := os.Stat(c.clonePath()); errors.Is(sErr, os.ErrNotExist) {
-			return // there's nothing to clean up
-		}
-
-		err = os.RemoveAll(c.clonePath())
-		if err != nil {
-			logger.Debug(fmt.Sprintf("add completed with errors - %s directory not deleted: %s", c.clonePath(), err.Error()))
-		}
-		logger.Info("temp directory deleted")
-	}()
+	defer c.removeClonePath()
 
 	// keep the SHA of the clone operation (if any)
-	c.latestSHA, err = c.cloneRemoteGitRegistry(opts)
+	c.latestSHA, err = c.cloneRemoteGitRegistry(ctx, opts)
 	if err != nil {
 		return
 	}
 
+	return c.addClonedPacks(ctx, opts)
+}
+
+// addClonedPacks moves the packs found in the clone path into the global cache
+// and writes the registry metadata. It is shared by all registry sources once
+// they have been fetched to the clone path. The context is checked before each
+// pack is moved, so a canceled add does not leave a pack partially written.
+func (c *Cache) addClonedPacks(ctx context.Context, opts *AddOpts) (cachedRegistry *Registry, err error) {
+	logger := c.cfg.Logger
+
 	logger.Debug(fmt.Sprintf("Processing pack entries at %s", c.clonePath()))
 
 	// Move the cloned registry packs to the global cache.
@@ -98,6 +101,11 @@ func (c *Cache) addFromURI(opts *AddOpts) (cachedRegistry *Registry, err error)
 			continue
 		}
 
+		if err = ctx.Err(); err != nil {
+			logger.ErrorWithContext(err, "registry add canceled", c.ErrorContext.GetAll()...)
+			return
+		}
+
 		logger.Debug(fmt.Sprintf("found pack entry %s", packEntry.Name()))
 
 		// Make a new add opts for each pack so that we don't end up corrupting
@@ -139,9 +147,24 @@ func (c *Cache) addFromURI(opts *AddOpts) (cachedRegistry *Registry, err error)
 	return
 }
 
+// removeClonePath removes the temporary directory used to fetch registries.
+func (c *Cache) removeClonePath() {
+	logger := c.cfg.Logger
+
+	if _, err := os.Stat(c.clonePath()); errors.Is(err, os.ErrNotExist) {
+		return // there's nothing to clean up
+	}
+
+	if err := os.RemoveAll(c.clonePath()); err != nil {
+		logger.Debug(fmt.Sprintf("add completed with errors - %s directory not deleted: %s", c.clonePath(), err.Error()))
+		return
+	}
+	logger.Info("temp directory deleted")
+}
+
 // cloneRemoteGitRegistry clones a remote git repository to the cache. Returns
 // the SHA of the HEAD of the cloned repository.
-func (c *Cache) cloneRemoteGitRegistry(opts *AddOpts) (string, error) {
+func (c *Cache) cloneRemoteGitRegistry(ctx context.Context, opts *AddOpts) (string, error) {
 	logger := c.cfg.Logger
 	url := opts.Source
 
@@ -166,7 +189,12 @@ func (c *Cache) cloneRemoteGitRegistry(opts *AddOpts) (string, error) {
 	if opts.PackName != "" {
 		clonePath = path.Join(clonePath, "packs", opts.PackName)
 	}
-	if err := gg.Get(clonePath, fmt.Sprintf("git::%s", url)); err != nil {
+	if err := gg.Get(clonePath, fmt.Sprintf("git::%s", url), gg.WithContext(ctx)); err != nil {
+		// The error of a git process killed by canceling the context does
+		// not wrap the cancellation, so report it directly.
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
 		logger.ErrorWithContext(err, "could not install registry", c.ErrorContext.GetAll()...)
 		return "n/a", err
 	}
@@ -330,6 +358,9 @@ type AddOpts struct {
 	Username string
 	// Optional password for basic auth to a registry that requires authentication.
 	Password string
+	// Optional SHA256 checksum of a tarball source. When set, the download is
+	// verified against it before the packs are added.
+	SHA256 string
 }
 
 // RegistryPath fulfills the cacheOperationProvider interface for AddOpts
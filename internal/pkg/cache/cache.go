@@ -67,6 +67,20 @@ func DefaultCachePath() string {
 	return path.Join(cacheDir, "nomad/packs")
 }
 
+// DefaultParseCachePath returns the default path used to cache parsed pack
+// variables. It is kept alongside, rather than within, the registry cache so
+// that it is not loaded as a registry.
+func DefaultParseCachePath() string {
+	return path.Join(path.Dir(DefaultCachePath()), "pack-parse-cache")
+}
+
+// DefaultStatusCachePath returns the default path used to store snapshots of
+// the packs deployed to each cluster. Like the parse cache, it is kept
+// alongside the registry cache so that it is not loaded as a registry.
+func DefaultStatusCachePath() string {
+	return path.Join(path.Dir(DefaultCachePath()), "pack-status-cache")
+}
+
 func defaultCacheConfig() *CacheConfig {
 	return &CacheConfig{
 		Path:   DefaultCachePath(),
@@ -4,11 +4,20 @@
 package cache
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io/fs"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path"
+	"path/filepath"
 	"slices"
 	"strings"
 	"sync"
@@ -49,7 +58,7 @@ func TestListRegistries(t *testing.T) {
 	must.NoError(t, err)
 	must.NotNil(t, cache)
 
-	registry, err := cache.Add(opts)
+	registry, err := cache.Add(context.Background(), opts)
 	must.NoError(t, err)
 	must.NotNil(t, registry)
 
@@ -69,7 +78,7 @@ func TestAddRegistry(t *testing.T) {
 	must.NoError(t, err)
 	must.NotNil(t, cache)
 
-	registry, err := cache.Add(opts)
+	registry, err := cache.Add(context.Background(), opts)
 	must.NoError(t, err)
 	must.NotNil(t, registry)
 
@@ -97,12 +106,12 @@ func TestAddRegistryPacksAtMultipleRefs(t *testing.T) {
 	must.NotNil(t, cache)
 
 	// Add at ref
-	registry, err := cache.Add(addOpts)
+	registry, err := cache.Add(context.Background(), addOpts)
 	must.NoError(t, err)
 	must.NotNil(t, registry)
 
 	// Add at latest
-	registry, err = cache.Add(testOpts)
+	registry, err = cache.Add(context.Background(), testOpts)
 	must.NoError(t, err)
 	must.NotNil(t, registry)
 
@@ -134,7 +143,7 @@ func TestAddRegistryWithTarget(t *testing.T) {
 	must.NotNil(t, cache)
 
 	// Add at ref
-	registry, err := cache.Add(addOpts)
+	registry, err := cache.Add(context.Background(), addOpts)
 	must.NoError(t, err)
 	must.NotNil(t, registry)
 
@@ -160,7 +169,7 @@ func TestAddRegistryWithSHA(t *testing.T) {
 	must.NotNil(t, cache)
 
 	// Add at SHA
-	registry, err := cache.Add(addOpts)
+	registry, err := cache.Add(context.Background(), addOpts)
 	must.NoError(t, err)
 	must.NotNil(t, registry)
 
@@ -201,7 +210,7 @@ func TestAddRegistryWithRefAndPackName(t *testing.T) {
 	must.NotNil(t, cache)
 
 	// Add Ref and PackName
-	registry, err := cache.Add(addOpts)
+	registry, err := cache.Add(context.Background(), addOpts)
 	must.NoError(t, err)
 	must.NotNil(t, registry)
 
@@ -217,7 +226,7 @@ func TestAddRegistryNoCacheDir(t *testing.T) {
 	})
 	must.Error(t, err)
 
-	registry, err := cache.Add(opts)
+	registry, err := cache.Add(context.Background(), opts)
 	must.Error(t, err)
 	must.Nil(t, registry)
 	must.Eq(t, errors.ErrCachePathRequired, err)
@@ -236,13 +245,87 @@ func TestAddRegistryNoSource(t *testing.T) {
 	must.NoError(t, err)
 	must.NotNil(t, cache)
 
-	registry, err := cache.Add(opts)
+	registry, err := cache.Add(context.Background(), opts)
 
 	must.Error(t, err)
 	must.Nil(t, registry)
 	must.Eq(t, errors.ErrRegistrySourceRequired, err)
 }
 
+func TestAddRegistryFromTarball(t *testing.T) {
+	t.Parallel()
+
+	tarball := testPackTarball(t, testfixture.AbsPath(t, "v2/test_registry/packs/simple_raw_exec"))
+	sum := sha256.Sum256(tarball)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(tarball)
+	}))
+	defer srv.Close()
+
+	cache, err := NewCache(&CacheConfig{
+		Path:   t.TempDir(),
+		Logger: NewTestLogger(t),
+	})
+	must.NoError(t, err)
+
+	registry, err := cache.Add(context.Background(), &AddOpts{
+		RegistryName: "tarball",
+		Source:       srv.URL + "/simple_raw_exec-1.2.0.tar.gz",
+		Ref:          "v1.0.0",
+		SHA256:       hex.EncodeToString(sum[:]),
+	})
+	must.NoError(t, err)
+	must.Len(t, 1, registry.Packs)
+	must.Eq(t, "simple_raw_exec", registry.Packs[0].Name())
+	must.Eq(t, DefaultRef, registry.Packs[0].Ref)
+
+	_, err = cache.Add(context.Background(), &AddOpts{
+		RegistryName: "bad-checksum",
+		Source:       srv.URL + "/simple_raw_exec-1.2.0.tar.gz",
+		SHA256:       strings.Repeat("0", 64),
+	})
+	must.ErrorContains(t, err, "Checksums did not match")
+}
+
+func TestAddRegistryCanceled(t *testing.T) {
+	t.Parallel()
+
+	// The server never responds, so the download is only stopped by canceling
+	// the context.
+	ctx, cancel := context.WithCancel(context.Background())
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cancel()
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	cacheDir := t.TempDir()
+	cache, err := NewCache(&CacheConfig{
+		Path:   cacheDir,
+		Logger: NewTestLogger(t),
+	})
+	must.NoError(t, err)
+
+	_, err = cache.Add(ctx, &AddOpts{
+		RegistryName: "canceled",
+		Source:       srv.URL + "/simple_raw_exec-1.2.0.tar.gz",
+	})
+	must.ErrorIs(t, err, context.Canceled)
+
+	_, err = os.Stat(cache.clonePath())
+	must.ErrorIs(t, err, fs.ErrNotExist)
+	_, err = os.Stat(filepath.Join(cacheDir, "canceled"))
+	must.ErrorIs(t, err, fs.ErrNotExist)
+
+	// Canceling before the packs are moved into the cache leaves the cache
+	// untouched.
+	_, err = cache.Add(ctx, testAddOpts("canceled-git"))
+	must.ErrorIs(t, err, context.Canceled)
+	_, err = os.Stat(filepath.Join(cacheDir, "canceled-git"))
+	must.ErrorIs(t, err, fs.ErrNotExist)
+}
+
 func TestDeleteRegistry(t *testing.T) {
 	t.Parallel()
 	cacheDir := t.TempDir()
@@ -255,7 +338,7 @@ func TestDeleteRegistry(t *testing.T) {
 	must.NoError(t, err)
 	must.NotNil(t, cache)
 
-	registry, err := cache.Add(opts)
+	registry, err := cache.Add(context.Background(), opts)
 	must.NoError(t, err)
 	must.NotNil(t, registry)
 
@@ -289,7 +372,7 @@ func TestDeletePack(t *testing.T) {
 	must.NoError(t, err)
 	must.NotNil(t, cache)
 
-	registry, err := cache.Add(opts)
+	registry, err := cache.Add(context.Background(), opts)
 	must.NoError(t, err)
 	must.NotNil(t, registry)
 
@@ -330,13 +413,13 @@ func TestDeletePackByRef(t *testing.T) {
 	must.NoError(t, err)
 	must.NotNil(t, cache)
 
-	registry, err := cache.Add(opts)
+	registry, err := cache.Add(context.Background(), opts)
 	must.NoError(t, err)
 	must.NotNil(t, registry)
 
 	// Now add at different ref
 	opts.Ref = tReg.Ref1()
-	registry, err = cache.Add(opts)
+	registry, err = cache.Add(context.Background(), opts)
 	must.NoError(t, err)
 	must.NotNil(t, registry)
 
@@ -754,3 +837,43 @@ func hasFile(name string) func(d fs.DirEntry) bool {
 		return d.Type().IsRegular() && d.Name() == name
 	}
 }
+
+// testPackTarball returns a gzipped tarball containing the files of the pack
+// at the passed path at the root of the archive.
+func testPackTarball(t *testing.T, packPath string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	err := filepath.WalkDir(packPath, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || p == packPath {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name, _ = filepath.Rel(packPath, p)
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		b, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		_, err = tw.Write(b)
+		return err
+	})
+	must.NoError(t, err)
+	must.NoError(t, tw.Close())
+	must.NoError(t, gw.Close())
+	return buf.Bytes()
+}
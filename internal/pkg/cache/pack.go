@@ -10,6 +10,7 @@ import (
 	"runtime"
 	"strings"
 
+	"github.com/hashicorp/nomad-pack/internal/pkg/loader"
 	"github.com/hashicorp/nomad-pack/sdk/pack"
 )
 
@@ -21,6 +22,13 @@ type PackConfig struct {
 	Ref        string
 	Path       string
 	SourcePath string
+
+	// CachePath is the cache the pack is read from when it is not a local
+	// directory. If not set, the default cache path is used.
+	CachePath string
+
+	// extractDir is the temporary directory a pack archive is extracted to.
+	extractDir string
 }
 
 func (cfg *PackConfig) Init() {
@@ -38,7 +46,9 @@ func (cfg *PackConfig) Init() {
 	if pathErr == nil {
 		_, pathErr = os.Stat(packPath)
 	}
-	if pathErr == nil {
+	if pathErr == nil && loader.IsArchive(packPath) {
+		cfg.initFromArchive(packPath)
+	} else if pathErr == nil {
 		cfg.initFromDirectory(packPath)
 	} else {
 		cfg.initFromArgs()
@@ -58,10 +68,48 @@ func (cfg *PackConfig) initFromDirectory(packPath string) {
 	cfg.Ref = DevRef
 }
 
+// initFromArchive extracts the pack archive to a temporary directory, which is
+// then used as the pack directory, so the pack is named after the top-level
+// directory of the archive. If the archive cannot be extracted, the archive
+// path is used as is, so the error is returned when the pack is loaded.
+func (cfg *PackConfig) initFromArchive(archivePath string) {
+	dir, err := os.MkdirTemp("", "nomad-pack-archive-")
+	if err != nil {
+		cfg.initFromDirectory(archivePath)
+		return
+	}
+
+	root, err := loader.ExtractArchive(archivePath, dir)
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		cfg.initFromDirectory(archivePath)
+		return
+	}
+
+	name := cfg.Name
+	cfg.Cleanup()
+	cfg.extractDir = dir
+	cfg.initFromDirectory(root)
+	cfg.SourcePath = name
+}
+
+// Cleanup removes the directory a pack archive was extracted to by Init. It
+// is a no-op if the pack is not an archive.
+func (cfg *PackConfig) Cleanup() {
+	if cfg.extractDir != "" {
+		_ = os.RemoveAll(cfg.extractDir)
+		cfg.extractDir = ""
+	}
+}
+
 // initFromArgs is a utility function to build a pack path for registry added
 // packs. Not for use with file system based packs.
 func (cfg *PackConfig) initFromArgs() {
-	cfg.Path = path.Join(DefaultCachePath(), cfg.Registry, cfg.Ref, cfg.Name)
+	cachePath := cfg.CachePath
+	if cachePath == "" {
+		cachePath = DefaultCachePath()
+	}
+	cfg.Path = path.Join(cachePath, cfg.Registry, cfg.Ref, cfg.Name)
 	if cfg.Ref != "" {
 		cfg.Path = AppendRef(cfg.Path, cfg.Ref)
 	}
@@ -71,6 +119,10 @@ func (cfg *PackConfig) initFromArgs() {
 // showing the registry in the global cache differentiated from the pack metadata.
 type Pack struct {
 	Ref string
+
+	// Path is the directory of the pack within the cache.
+	Path string
+
 	*pack.Pack
 }
 
@@ -92,6 +92,7 @@ func (r *Registry) get(opts *GetOpts, cache *Cache) error {
 				Ref:          opts.Ref,
 			}
 			cachedPack = invalidPackDefinition(invalidOpts)
+			cachedPack.Path = opts.toPackDir(packEntry)
 			// Append the pack to the registry's packs field.
 			r.add(cachedPack)
 			continue
@@ -120,6 +121,7 @@ func (r *Registry) get(opts *GetOpts, cache *Cache) error {
 				Pack: loadedPack,
 			}
 		}
+		cachedPack.Path = opts.toPackDir(packEntry)
 
 		// Append the pack to the registry's packs field.
 		r.add(cachedPack)
@@ -0,0 +1,162 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cache
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	gg "github.com/hashicorp/go-getter"
+
+	"github.com/hashicorp/nomad-pack/internal/pkg/errors"
+)
+
+// tarballExtensions are the file extensions which identify a registry source
+// as a tarball rather than a git repository.
+var tarballExtensions = []string{".tar.gz", ".tgz"}
+
+// archiveVersionSuffix matches a trailing version on an archive name, such as
+// the "-1.2.0" in "mypack-1.2.0", so it can be removed to find the pack name.
+var archiveVersionSuffix = regexp.MustCompile(`[-_]v?[0-9][0-9A-Za-z.+-]*$`)
+
+// isTarballSource returns whether the registry source is an HTTP(S) URL to a
+// tarball.
+func isTarballSource(source string) bool {
+	u, err := url.Parse(source)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return false
+	}
+	return archiveExtension(u.Path) != ""
+}
+
+// archiveExtension returns the archive extension of the passed file name, or
+// an empty string if it does not have one.
+func archiveExtension(name string) string {
+	for _, ext := range tarballExtensions {
+		if strings.HasSuffix(strings.ToLower(name), ext) {
+			return ext
+		}
+	}
+	return ""
+}
+
+// addFromTarball downloads and extracts a tarball into the cache. The tarball
+// may either contain a registry, with a top level packs directory, or a single
+// pack. The URL acts as the pin for the packs, so any ref is ignored and the
+// packs are added at the latest ref.
+func (c *Cache) addFromTarball(ctx context.Context, opts *AddOpts) (cachedRegistry *Registry, err error) {
+	logger := c.cfg.Logger
+
+	if !opts.IsLatest() {
+		logger.Warning(fmt.Sprintf("ignoring ref %q, tarball sources are pinned by their URL", opts.Ref))
+	}
+	opts.Ref = DefaultRef
+
+	// Set up a defer function so that the temp directory always gets removed
+	defer c.removeClonePath()
+
+	src := opts.Source
+	if opts.SHA256 != "" {
+		c.ErrorContext.Add(errors.RegistryContextPrefixChecksum, opts.SHA256)
+
+		sep := "?"
+		if strings.Contains(src, "?") {
+			sep = "&"
+		}
+		src = fmt.Sprintf("%s%schecksum=sha256:%s", src, sep, opts.SHA256)
+	}
+
+	logger.Debug(fmt.Sprintf("go-getter URL is %s", src))
+
+	if err = gg.Get(c.clonePath(), src, gg.WithContext(ctx)); err != nil {
+		logger.ErrorWithContext(err, "could not download registry tarball", c.ErrorContext.GetAll()...)
+		return
+	}
+
+	if err = c.normalizeExtractedPack(opts); err != nil {
+		logger.ErrorWithContext(err, "error processing registry tarball", c.ErrorContext.GetAll()...)
+		return
+	}
+
+	// There is no commit to record for a tarball, so use the checksum when
+	// it has been provided.
+	c.latestSHA = "n/a"
+	if opts.SHA256 != "" {
+		c.latestSHA = opts.SHA256
+	}
+
+	return c.addClonedPacks(ctx, opts)
+}
+
+// normalizeExtractedPack ensures the extracted tarball follows the registry
+// layout expected by addClonedPacks. If the tarball contains a single pack,
+// either at its root or within a single directory, it is moved under a packs
+// directory.
+func (c *Cache) normalizeExtractedPack(opts *AddOpts) error {
+	if info, err := os.Stat(c.clonedPacksPath()); err == nil && info.IsDir() {
+		return nil
+	}
+
+	// A pack at the root of the tarball is named after the archive, unless
+	// the user targeted a specific pack name.
+	if _, err := os.Stat(filepath.Join(c.clonePath(), "metadata.hcl")); err == nil {
+		name := opts.PackName
+		if name == "" {
+			name = packNameFromArchive(opts.Source)
+		}
+		return movePackDir(c.clonePath(), filepath.Join(c.clonedPacksPath(), name))
+	}
+
+	entries, err := os.ReadDir(c.clonePath())
+	if err != nil {
+		return err
+	}
+	if len(entries) == 1 && entries[0].IsDir() {
+		dir := filepath.Join(c.clonePath(), entries[0].Name())
+		if _, err := os.Stat(filepath.Join(dir, "metadata.hcl")); err == nil {
+			return movePackDir(dir, filepath.Join(c.clonedPacksPath(), entries[0].Name()))
+		}
+	}
+
+	return errors.New("tarball does not contain a packs directory or a pack metadata.hcl file")
+}
+
+// movePackDir moves the contents of src into dst. The dst directory may be
+// nested within src.
+func movePackDir(src, dst string) error {
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		from := filepath.Join(src, entry.Name())
+		if strings.HasPrefix(dst, from+string(filepath.Separator)) || dst == from {
+			continue
+		}
+		if err := os.Rename(from, filepath.Join(dst, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// packNameFromArchive derives a pack name from the file name of an archive
+// source by removing the extension and any trailing version.
+func packNameFromArchive(source string) string {
+	if u, err := url.Parse(source); err == nil && u.Path != "" {
+		source = u.Path
+	}
+	name := path.Base(source)
+	name = name[:len(name)-len(archiveExtension(name))]
+	return archiveVersionSuffix.ReplaceAllString(name, "")
+}
@@ -25,4 +25,5 @@ const (
 	RegistryContextPrefixRegistryName   = "Registry Name: "
 	RegistryContextPrefixPackName       = "Pack Name: "
 	RegistryContextPrefixRef            = "Ref: "
+	RegistryContextPrefixChecksum       = "Checksum: "
 )
@@ -20,6 +20,16 @@ func DiagFileNotFound(f string) *hcl.Diagnostic {
 	}
 }
 
+// DiagFileNotReadable is returned when pack parsing fails to read a file, such
+// as one which is too large.
+func DiagFileNotReadable(f string, err error) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Failed to read file",
+		Detail:   fmt.Sprintf("The file %q could not be read: %s.", f, err),
+	}
+}
+
 // DiagMissingRootVar is returned when a pack consumer passes in a variable that
 // is not defined for the pack.
 func DiagMissingRootVar(name string, sub *hcl.Range) *hcl.Diagnostic {
@@ -64,6 +74,94 @@ func DiagInvalidValueForType(err error, sub *hcl.Range) *hcl.Diagnostic {
 	}
 }
 
+// DiagInvalidTypeHint is returned when a pack consumer passes a type hint with
+// a CLI variable which is not a valid type.
+func DiagInvalidTypeHint(hint string, sub *hcl.Range) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Invalid variable type hint",
+		Detail:   fmt.Sprintf("The type hint %q is not a valid type. Type hints use the same syntax as variable types, such as string, number, bool, or list(string).", hint),
+		Subject:  sub,
+	}
+}
+
+// DiagInvalidValueForTypeHint is returned when a pack consumer passes a CLI
+// variable value which is not compatible with the type hint passed with it.
+func DiagInvalidValueForTypeHint(hint string, err error, sub *hcl.Range) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Invalid value for variable type hint",
+		Detail:   fmt.Sprintf("This variable value is not compatible with its type hint %s: %s.", hint, err),
+		Subject:  sub,
+	}
+}
+
+// DiagInvalidDefaultFrom is returned when a pack author references a default
+// provider which is not registered, or whose value cannot be resolved.
+func DiagInvalidDefaultFrom(ref string, err error, sub *hcl.Range) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Failed to resolve default value",
+		Detail:   fmt.Sprintf("Unable to resolve the default value from %q: %s.", ref, err),
+		Subject:  sub,
+	}
+}
+
+// DiagInvalidValueRef is returned when a pack consumer passes a variable value
+// by reference, and the referenced value cannot be resolved.
+func DiagInvalidValueRef(ref string, err error, sub *hcl.Range) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Failed to resolve variable value",
+		Detail:   fmt.Sprintf("Unable to resolve the value from %q: %s.", ref, err),
+		Subject:  sub,
+	}
+}
+
+// DiagDeprecatedVariable is returned as a warning when a pack consumer sets a
+// variable which the pack author has marked as deprecated.
+func DiagDeprecatedVariable(name, source, msg string, sub *hcl.Range) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagWarning,
+		Summary:  "Deprecated variable",
+		Detail:   fmt.Sprintf("The variable %q set by %s is deprecated: %s", name, source, msg),
+		Subject:  sub,
+	}
+}
+
+// DiagFailedVariableValidation is returned when the value of a variable does
+// not satisfy the condition of one of its validation blocks.
+func DiagFailedVariableValidation(name, msg string, sub *hcl.Range) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Invalid value for variable",
+		Detail:   fmt.Sprintf("The value of the variable %q is invalid: %s", name, msg),
+		Subject:  sub,
+	}
+}
+
+// DiagConflictingMapEntry is returned when a pack consumer sets both a whole
+// map variable and individual entries of it using CLI variables.
+func DiagConflictingMapEntry(name string, sub *hcl.Range) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Conflicting variable assignments",
+		Detail:   fmt.Sprintf("The variable %q is set as a whole value and by individual entries. Set either the whole value or its entries, but not both.", name),
+		Subject:  sub,
+	}
+}
+
+// DiagInvalidVarPatch is returned when a JSON Patch passed by a pack consumer
+// using --var-patch cannot be applied to the variable.
+func DiagInvalidVarPatch(name string, err error, sub *hcl.Range) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Invalid variable patch",
+		Detail:   fmt.Sprintf("The patch for variable %q could not be applied: %s", name, err),
+		Subject:  sub,
+	}
+}
+
 // DiagInvalidVariableName is returned when a pack author specifies an invalid
 // name for a variable in their varfile
 func DiagInvalidVariableName(sub *hcl.Range) *hcl.Diagnostic {
@@ -70,6 +70,9 @@ func (f *Set) BoolVarP(i *BoolVarP) {
 	// with boolean flags, but since everything in the internal flag pkg calls var
 	// flags, we need to set the value ourselves
 	f.unionSet.Lookup(i.Name).NoOptDefVal = "true"
+	for _, a := range i.Aliases {
+		f.unionSet.Lookup(a).NoOptDefVal = "true"
+	}
 }
 
 type boolValue struct {
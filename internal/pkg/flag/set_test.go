@@ -74,3 +74,17 @@ func TestSets(t *testing.T) {
 		})
 	}
 }
+
+func TestSets_BoolAlias(t *testing.T) {
+	var val bool
+	sets := NewSets()
+	sets.NewSet("set").BoolVar(&BoolVar{
+		Name:    "fail",
+		Aliases: []string{"exit-code"},
+		Target:  &val,
+	})
+
+	// The alias is set without a value, in the same way as the flag.
+	must.NoError(t, sets.Parse([]string{"--exit-code"}))
+	must.True(t, val)
+}
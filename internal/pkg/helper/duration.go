@@ -0,0 +1,92 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package helper
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const day = 24 * time.Hour
+
+// durationUnits are the units used when formatting durations, ordered from
+// largest to smallest.
+var durationUnits = []struct {
+	suffix string
+	size   time.Duration
+}{
+	{"d", day},
+	{"h", time.Hour},
+	{"m", time.Minute},
+	{"s", time.Second},
+}
+
+// FormatDuration returns a compact representation of d using at most its two
+// most significant units, such as "3d4h", "2m3s", or "45s". Durations of less
+// than a second are output in milliseconds, or microseconds when shorter than
+// a millisecond, such as "350ms".
+func FormatDuration(d time.Duration) string {
+	if d < 0 {
+		return "-" + FormatDuration(-d)
+	}
+
+	switch {
+	case d == 0:
+		return "0s"
+	case d < time.Millisecond:
+		return d.Round(time.Microsecond).String()
+	case d < time.Second:
+		return d.Round(time.Millisecond).String()
+	}
+
+	d = d.Round(time.Second)
+
+	var b strings.Builder
+	var parts int
+	for _, unit := range durationUnits {
+		n := d / unit.size
+		if n == 0 && parts == 0 {
+			continue
+		}
+		d -= n * unit.size
+		parts++
+
+		if n > 0 {
+			fmt.Fprintf(&b, "%d%s", n, unit.suffix)
+		}
+		if parts == 2 {
+			break
+		}
+	}
+	return b.String()
+}
+
+// HumanizeDuration returns a relative representation of d using its most
+// significant unit, such as "3d ago" or "2m ago". Negative durations are in
+// the future, such as "in 5m". Durations of less than a second are output as
+// "just now".
+func HumanizeDuration(d time.Duration) string {
+	future := d < 0
+	if future {
+		d = -d
+	}
+
+	if d < time.Second {
+		return "just now"
+	}
+
+	var out string
+	for _, unit := range durationUnits {
+		if d >= unit.size {
+			out = fmt.Sprintf("%d%s", d/unit.size, unit.suffix)
+			break
+		}
+	}
+
+	if future {
+		return "in " + out
+	}
+	return out + " ago"
+}
@@ -0,0 +1,49 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package helper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shoenig/test/must"
+)
+
+func TestFormatDuration(t *testing.T) {
+	cases := []struct {
+		d   time.Duration
+		exp string
+	}{
+		{d: 0, exp: "0s"},
+		{d: 1500 * time.Nanosecond, exp: "2µs"},
+		{d: 350*time.Millisecond + 400*time.Microsecond, exp: "350ms"},
+		{d: 45 * time.Second, exp: "45s"},
+		{d: 2*time.Minute + 3*time.Second + 200*time.Millisecond, exp: "2m3s"},
+		{d: 2 * time.Minute, exp: "2m"},
+		{d: time.Hour + 5*time.Second, exp: "1h"},
+		{d: 3*day + 4*time.Hour + 5*time.Minute, exp: "3d4h"},
+		{d: -90 * time.Second, exp: "-1m30s"},
+	}
+
+	for _, tc := range cases {
+		must.Eq(t, tc.exp, FormatDuration(tc.d), must.Sprintf("duration: %s", tc.d))
+	}
+}
+
+func TestHumanizeDuration(t *testing.T) {
+	cases := []struct {
+		d   time.Duration
+		exp string
+	}{
+		{d: 300 * time.Millisecond, exp: "just now"},
+		{d: 45 * time.Second, exp: "45s ago"},
+		{d: 2*time.Minute + 3*time.Second, exp: "2m ago"},
+		{d: 3*day + 4*time.Hour, exp: "3d ago"},
+		{d: -5 * time.Minute, exp: "in 5m"},
+	}
+
+	for _, tc := range cases {
+		must.Eq(t, tc.exp, HumanizeDuration(tc.d), must.Sprintf("duration: %s", tc.d))
+	}
+}
@@ -0,0 +1,366 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package jsonpatch applies JSON Patch (RFC 6902) documents to JSON values.
+// Locations within the values are given as JSON Pointers (RFC 6901).
+package jsonpatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// ErrPathNotFound is returned when the location referenced by an operation
+// does not exist within the value being patched.
+var ErrPathNotFound = errors.New("path not found")
+
+// Operation is a single operation of a JSON Patch document.
+type Operation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// Patch is a JSON Patch document: a list of operations applied in order.
+type Patch []Operation
+
+// Decode decodes a JSON Patch document and checks each operation is valid,
+// without applying it.
+func Decode(raw []byte) (Patch, error) {
+	var p Patch
+	if err := json.Unmarshal(raw, &p); err != nil {
+		return nil, fmt.Errorf("failed to decode patch: %w", err)
+	}
+
+	for i, op := range p {
+		switch op.Op {
+		case "add", "replace", "test":
+			if op.Value == nil {
+				return nil, fmt.Errorf("operation %d: %q requires a value", i, op.Op)
+			}
+		case "move", "copy":
+			if _, err := splitPointer(op.From); err != nil {
+				return nil, fmt.Errorf("operation %d: invalid from %q: %w", i, op.From, err)
+			}
+		case "remove":
+		default:
+			return nil, fmt.Errorf("operation %d: unsupported op %q", i, op.Op)
+		}
+		if _, err := splitPointer(op.Path); err != nil {
+			return nil, fmt.Errorf("operation %d: invalid path %q: %w", i, op.Path, err)
+		}
+	}
+	return p, nil
+}
+
+// Apply applies the operations of the patch to the JSON document, returning
+// the patched document. The operations are applied atomically: if any
+// operation fails, an error is returned and the document is not patched.
+func (p Patch) Apply(doc []byte) ([]byte, error) {
+	val, err := decodeValue(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode document: %w", err)
+	}
+
+	for i, op := range p {
+		if val, err = applyOperation(val, op); err != nil {
+			return nil, fmt.Errorf("operation %d (%s %s): %w", i, op.Op, op.Path, err)
+		}
+	}
+	return json.Marshal(val)
+}
+
+func applyOperation(doc any, op Operation) (any, error) {
+	path, err := splitPointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op.Op {
+	case "add":
+		val, err := decodeValue(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, path, val)
+
+	case "remove":
+		doc, _, err = remove(doc, path)
+		return doc, err
+
+	case "replace":
+		val, err := decodeValue(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		if len(path) == 0 {
+			return val, nil
+		}
+		if doc, _, err = remove(doc, path); err != nil {
+			return nil, err
+		}
+		return add(doc, path, val)
+
+	case "move":
+		from, err := splitPointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		if strings.HasPrefix(op.Path, op.From+"/") {
+			return nil, fmt.Errorf("cannot move %s into one of its children", op.From)
+		}
+		doc, val, err := remove(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, path, val)
+
+	case "copy":
+		from, err := splitPointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		val, err := get(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, path, deepCopy(val))
+
+	case "test":
+		want, err := decodeValue(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		got, err := get(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !equal(got, want) {
+			return nil, fmt.Errorf("test failed: value is %s", op.Value)
+		}
+		return doc, nil
+	}
+
+	return nil, fmt.Errorf("unsupported op %q", op.Op)
+}
+
+// splitPointer splits a JSON Pointer into its unescaped reference tokens. The
+// empty pointer references the whole document and has no tokens.
+func splitPointer(ptr string) ([]string, error) {
+	if ptr == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(ptr, "/") {
+		return nil, errors.New("pointer must be empty or start with /")
+	}
+
+	tokens := strings.Split(ptr[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+	return tokens, nil
+}
+
+// get returns the value at the path within the document.
+func get(doc any, path []string) (any, error) {
+	for _, token := range path {
+		switch node := doc.(type) {
+		case map[string]any:
+			val, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("%w: %q", ErrPathNotFound, token)
+			}
+			doc = val
+		case []any:
+			idx, err := arrayIndex(token, len(node)-1)
+			if err != nil {
+				return nil, err
+			}
+			doc = node[idx]
+		default:
+			return nil, fmt.Errorf("%w: %q is not within an object or array", ErrPathNotFound, token)
+		}
+	}
+	return doc, nil
+}
+
+// add adds the value at the path, returning the updated document. Members of
+// objects are set, while the value is inserted into arrays.
+func add(doc any, path []string, val any) (any, error) {
+	if len(path) == 0 {
+		return val, nil
+	}
+	return update(doc, path, func(parent any, token string) (any, error) {
+		switch node := parent.(type) {
+		case map[string]any:
+			node[token] = val
+			return node, nil
+		case []any:
+			idx := len(node)
+			if token != "-" {
+				var err error
+				if idx, err = arrayIndex(token, len(node)); err != nil {
+					return nil, err
+				}
+			}
+			out := make([]any, 0, len(node)+1)
+			out = append(out, node[:idx]...)
+			out = append(out, val)
+			return append(out, node[idx:]...), nil
+		}
+		return nil, fmt.Errorf("%w: %q is not within an object or array", ErrPathNotFound, token)
+	})
+}
+
+// remove removes the value at the path, returning the updated document and
+// the removed value.
+func remove(doc any, path []string) (any, any, error) {
+	if len(path) == 0 {
+		return nil, nil, errors.New("cannot remove the whole value")
+	}
+
+	var removed any
+	doc, err := update(doc, path, func(parent any, token string) (any, error) {
+		switch node := parent.(type) {
+		case map[string]any:
+			val, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("%w: %q", ErrPathNotFound, token)
+			}
+			removed = val
+			delete(node, token)
+			return node, nil
+		case []any:
+			idx, err := arrayIndex(token, len(node)-1)
+			if err != nil {
+				return nil, err
+			}
+			removed = node[idx]
+			out := make([]any, 0, len(node)-1)
+			out = append(out, node[:idx]...)
+			return append(out, node[idx+1:]...), nil
+		}
+		return nil, fmt.Errorf("%w: %q is not within an object or array", ErrPathNotFound, token)
+	})
+	return doc, removed, err
+}
+
+// update walks to the parent of the last token of the path and replaces it
+// with the result of calling fn, returning the updated document.
+func update(doc any, path []string, fn func(parent any, token string) (any, error)) (any, error) {
+	if len(path) == 1 {
+		return fn(doc, path[0])
+	}
+
+	child, err := get(doc, path[:1])
+	if err != nil {
+		return nil, err
+	}
+	child, err = update(child, path[1:], fn)
+	if err != nil {
+		return nil, err
+	}
+
+	switch node := doc.(type) {
+	case map[string]any:
+		node[path[0]] = child
+	case []any:
+		idx, _ := arrayIndex(path[0], len(node)-1)
+		node[idx] = child
+	}
+	return doc, nil
+}
+
+// arrayIndex parses the token as an index of an array, which must not be
+// greater than maxIdx.
+func arrayIndex(token string, maxIdx int) (int, error) {
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	idx, err := strconv.Atoi(token)
+	if err != nil || idx < 0 {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	if idx > maxIdx {
+		return 0, fmt.Errorf("%w: array index %d out of range", ErrPathNotFound, idx)
+	}
+	return idx, nil
+}
+
+// decodeValue decodes a JSON value, keeping numbers in their original form so
+// they are not limited to the precision of a float64.
+func decodeValue(raw []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	var val any
+	if err := dec.Decode(&val); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, errors.New("unexpected data after value")
+	}
+	return val, nil
+}
+
+func deepCopy(val any) any {
+	switch v := val.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, elem := range v {
+			out[k] = deepCopy(elem)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, elem := range v {
+			out[i] = deepCopy(elem)
+		}
+		return out
+	}
+	return val
+}
+
+// equal reports whether the two decoded JSON values are equal. Numbers are
+// compared by value, so 1 and 1.0 are equal.
+func equal(a, b any) bool {
+	switch av := a.(type) {
+	case map[string]any:
+		bv, ok := b.(map[string]any)
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for k, elem := range av {
+			if other, ok := bv[k]; !ok || !equal(elem, other) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		bv, ok := b.([]any)
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for i := range av {
+			if !equal(av[i], bv[i]) {
+				return false
+			}
+		}
+		return true
+	case json.Number:
+		bv, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		af, _, aErr := big.ParseFloat(av.String(), 10, 512, big.ToNearestEven)
+		bf, _, bErr := big.ParseFloat(bv.String(), 10, 512, big.ToNearestEven)
+		return aErr == nil && bErr == nil && af.Cmp(bf) == 0
+	}
+	return a == b
+}
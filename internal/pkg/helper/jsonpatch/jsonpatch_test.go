@@ -0,0 +1,153 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jsonpatch
+
+import (
+	"testing"
+
+	"github.com/shoenig/test/must"
+)
+
+func TestPatch_Apply(t *testing.T) {
+	const doc = `{"a":{"b":1,"c":[1,2,3]},"d~/e":"x"}`
+
+	testCases := []struct {
+		desc        string
+		patch       string
+		expected    string
+		expectedErr string
+	}{
+		{
+			desc:     "add member",
+			patch:    `[{"op":"add","path":"/a/z","value":{"k":true}}]`,
+			expected: `{"a":{"b":1,"c":[1,2,3],"z":{"k":true}},"d~/e":"x"}`,
+		},
+		{
+			desc:     "add array element",
+			patch:    `[{"op":"add","path":"/a/c/1","value":9},{"op":"add","path":"/a/c/-","value":10}]`,
+			expected: `{"a":{"b":1,"c":[1,9,2,3,10]},"d~/e":"x"}`,
+		},
+		{
+			desc:     "remove",
+			patch:    `[{"op":"remove","path":"/a/c/0"},{"op":"remove","path":"/d~0~1e"}]`,
+			expected: `{"a":{"b":1,"c":[2,3]}}`,
+		},
+		{
+			desc:     "replace",
+			patch:    `[{"op":"replace","path":"/a/b","value":"two"}]`,
+			expected: `{"a":{"b":"two","c":[1,2,3]},"d~/e":"x"}`,
+		},
+		{
+			desc:     "replace whole value",
+			patch:    `[{"op":"replace","path":"","value":{"new":1}}]`,
+			expected: `{"new":1}`,
+		},
+		{
+			desc:     "move",
+			patch:    `[{"op":"move","from":"/a/b","path":"/b"}]`,
+			expected: `{"a":{"c":[1,2,3]},"b":1,"d~/e":"x"}`,
+		},
+		{
+			desc:     "copy",
+			patch:    `[{"op":"copy","from":"/a/c","path":"/c"},{"op":"add","path":"/c/-","value":4}]`,
+			expected: `{"a":{"b":1,"c":[1,2,3]},"c":[1,2,3,4],"d~/e":"x"}`,
+		},
+		{
+			desc:     "test",
+			patch:    `[{"op":"test","path":"/a","value":{"c":[1,2,3],"b":1.0}}]`,
+			expected: doc,
+		},
+		{
+			desc:        "failed test",
+			patch:       `[{"op":"test","path":"/a/b","value":2}]`,
+			expectedErr: "operation 0 (test /a/b): test failed: value is 2",
+		},
+		{
+			desc:        "replace missing member",
+			patch:       `[{"op":"replace","path":"/a/missing","value":1}]`,
+			expectedErr: `operation 0 (replace /a/missing): path not found: "missing"`,
+		},
+		{
+			desc:        "missing parent",
+			patch:       `[{"op":"add","path":"/x/y","value":1}]`,
+			expectedErr: `operation 0 (add /x/y): path not found: "x"`,
+		},
+		{
+			desc:        "array index out of range",
+			patch:       `[{"op":"add","path":"/a/c/4","value":1}]`,
+			expectedErr: "operation 0 (add /a/c/4): path not found: array index 4 out of range",
+		},
+		{
+			desc:        "invalid array index",
+			patch:       `[{"op":"remove","path":"/a/c/01"}]`,
+			expectedErr: `operation 0 (remove /a/c/01): invalid array index "01"`,
+		},
+		{
+			desc:        "path within scalar",
+			patch:       `[{"op":"add","path":"/a/b/c","value":1}]`,
+			expectedErr: `operation 0 (add /a/b/c): path not found: "c" is not within an object or array`,
+		},
+		{
+			desc:        "move into child",
+			patch:       `[{"op":"move","from":"/a","path":"/a/b/c"}]`,
+			expectedErr: "operation 0 (move /a/b/c): cannot move /a into one of its children",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			p, err := Decode([]byte(tc.patch))
+			must.NoError(t, err)
+
+			out, err := p.Apply([]byte(doc))
+			if tc.expectedErr != "" {
+				must.EqError(t, err, tc.expectedErr)
+				return
+			}
+			must.NoError(t, err)
+			must.Eq(t, tc.expected, string(out))
+		})
+	}
+}
+
+func TestDecode_Invalid(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		patch       string
+		expectedErr string
+	}{
+		{
+			desc:        "not a list",
+			patch:       `{"op":"add"}`,
+			expectedErr: "failed to decode patch",
+		},
+		{
+			desc:        "unsupported op",
+			patch:       `[{"op":"merge","path":"/a"}]`,
+			expectedErr: `operation 0: unsupported op "merge"`,
+		},
+		{
+			desc:        "missing value",
+			patch:       `[{"op":"add","path":"/a"}]`,
+			expectedErr: `operation 0: "add" requires a value`,
+		},
+		{
+			desc:        "invalid path",
+			patch:       `[{"op":"remove","path":"a/b"}]`,
+			expectedErr: `operation 0: invalid path "a/b": pointer must be empty or start with /`,
+		},
+		{
+			desc:        "invalid from",
+			patch:       `[{"op":"copy","from":"a","path":"/b"}]`,
+			expectedErr: `operation 0: invalid from "a": pointer must be empty or start with /`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := Decode([]byte(tc.patch))
+			must.ErrorContains(t, err, tc.expectedErr)
+		})
+	}
+}
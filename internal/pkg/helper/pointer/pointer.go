@@ -7,3 +7,12 @@ package pointer
 func Of[A any](a A) *A {
 	return &a
 }
+
+// Value returns the value pointed to by a, or the zero value of A if a is nil.
+func Value[A any](a *A) A {
+	if a == nil {
+		var zero A
+		return zero
+	}
+	return *a
+}
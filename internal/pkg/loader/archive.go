@@ -0,0 +1,221 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package loader
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/nomad-pack/sdk/pack"
+)
+
+// ErrArchivePathTraversal is returned when an archive contains an entry whose
+// path is absolute or refers to a parent directory, so extracting it would
+// write outside of the extraction directory.
+var ErrArchivePathTraversal = errors.New("archive entry path is outside of the archive")
+
+// archiveExts are the file extensions of the supported pack archives.
+var archiveExts = []string{".tar.gz", ".tgz", ".zip"}
+
+// IsArchive returns whether the named path is a regular file with the
+// extension of a supported pack archive, which is a gzipped tarball or a zip
+// archive.
+func IsArchive(name string) bool {
+	if !hasArchiveExt(name) {
+		return false
+	}
+	fi, err := os.Stat(name)
+	return err == nil && fi.Mode().IsRegular()
+}
+
+func hasArchiveExt(name string) bool {
+	lower := strings.ToLower(name)
+	for _, ext := range archiveExts {
+		if strings.HasSuffix(lower, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// ExtractArchive extracts the named pack archive into the dst directory and
+// returns the path of the pack directory. The archive must contain a single
+// top-level directory, which is the pack directory. Only directories and
+// regular files are extracted; any other entry, such as a symlink, is an
+// error, as is an entry which would be written outside of dst.
+func ExtractArchive(name, dst string) (string, error) {
+	var err error
+	if strings.HasSuffix(strings.ToLower(name), ".zip") {
+		err = extractZip(name, dst)
+	} else {
+		err = extractTarGz(name, dst)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to extract %s: %w", name, err)
+	}
+
+	entries, err := os.ReadDir(dst)
+	if err != nil {
+		return "", err
+	}
+	if len(entries) != 1 || !entries[0].IsDir() {
+		return "", fmt.Errorf("archive %s must contain a single top-level pack directory", name)
+	}
+	return filepath.Join(dst, entries[0].Name()), nil
+}
+
+func extractTarGz(name, dst string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		target, err := archiveEntryPath(dst, hdr.Name)
+		if err != nil {
+			return err
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(target, 0o755)
+		case tar.TypeReg:
+			err = writeArchiveFile(target, tr)
+		case tar.TypeXGlobalHeader:
+			// PAX global headers, such as those written by git archive, hold
+			// no file content.
+			continue
+		default:
+			err = fmt.Errorf("unsupported archive entry %q", hdr.Name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func extractZip(name, dst string) error {
+	zr, err := zip.OpenReader(name)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+
+	for _, zf := range zr.File {
+		target, err := archiveEntryPath(dst, zf.Name)
+		if err != nil {
+			return err
+		}
+
+		mode := zf.Mode()
+		switch {
+		case mode.IsDir():
+			err = os.MkdirAll(target, 0o755)
+		case mode.IsRegular():
+			err = extractZipFile(zf, target)
+		default:
+			err = fmt.Errorf("unsupported archive entry %q", zf.Name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func extractZipFile(zf *zip.File, target string) error {
+	r, err := zf.Open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	return writeArchiveFile(target, r)
+}
+
+// archiveEntryPath returns the path an archive entry is extracted to within
+// dst. Entry names always use forward slashes, regardless of the platform the
+// archive was created on.
+func archiveEntryPath(dst, entry string) (string, error) {
+	rel := filepath.FromSlash(strings.TrimSuffix(entry, "/"))
+	if !filepath.IsLocal(rel) {
+		return "", fmt.Errorf("%w: %q", ErrArchivePathTraversal, entry)
+	}
+	return filepath.Join(dst, rel), nil
+}
+
+func writeArchiveFile(target string, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// loadArchive loads the pack within the named archive. The archive is
+// extracted to a temporary directory, which is removed once the pack is
+// loaded, so the path of each file refers to the file within the archive.
+func loadArchive(ctx context.Context, name string, opts walkOptions) (*pack.Pack, error) {
+	dir, err := os.MkdirTemp("", "nomad-pack-archive-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	root, err := ExtractArchive(name, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := loadDir(ctx, root, opts)
+	if p != nil {
+		for _, f := range packFiles(p) {
+			f.Path = filepath.Join(name, filepath.FromSlash(f.Name))
+		}
+	}
+	return p, err
+}
+
+// packFiles returns the files of the pack which are retained once loaded.
+func packFiles(p *pack.Pack) []*pack.File {
+	files := append([]*pack.File{}, p.TemplateFiles...)
+	files = append(files, p.AuxiliaryFiles...)
+	for _, f := range []*pack.File{p.RootVariableFile, p.OutputTemplateFile} {
+		if f != nil {
+			files = append(files, f)
+		}
+	}
+	return files
+}
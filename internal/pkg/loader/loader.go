@@ -5,29 +5,88 @@ package loader
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/hcl/v2/hclsimple"
 
 	"github.com/hashicorp/nomad-pack/sdk/pack"
 )
 
-func Load(name string) (*pack.Pack, error) {
+const (
+	// DefaultLoadTimeout is the time Load allows for loading a pack.
+	DefaultLoadTimeout = 30 * time.Second
+
+	// MaxLoadDepth is the number of directories a file may be nested within
+	// below the pack directory.
+	MaxLoadDepth = 32
+)
+
+// ErrMaxDepthExceeded is returned when a pack contains directories nested
+// deeper than MaxLoadDepth, such as when a symlink refers to a parent
+// directory.
+var ErrMaxDepthExceeded = errors.New("pack directory exceeds the maximum depth")
+
+// ErrExternalSymlink is returned when a pack contains a symlink which
+// resolves to a path outside of the pack directory, and external symlinks
+// have not been allowed.
+var ErrExternalSymlink = errors.New("pack contains a symlink outside of the pack directory")
+
+// LoadOption configures how a pack is loaded.
+type LoadOption func(*walkOptions)
+
+// AllowExternalSymlinks allows the pack to contain symlinks which resolve to
+// a path outside of the pack directory. By default, loading such a pack
+// fails, as the symlink may expose files from elsewhere on the host.
+func AllowExternalSymlinks(allow bool) LoadOption {
+	return func(o *walkOptions) { o.allowExternalSymlinks = allow }
+}
+
+// Load loads the pack within the named directory or archive, using
+// DefaultLoadTimeout.
+func Load(name string, opts ...LoadOption) (*pack.Pack, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultLoadTimeout)
+	defer cancel()
+	return LoadContext(ctx, name, opts...)
+}
+
+// LoadContext loads the pack within the named directory or archive. An
+// archive, which is a gzipped tarball or a zip archive, must contain a single
+// top-level directory, which is the pack directory. Loading stops with an
+// error when the context is cancelled or its deadline is exceeded.
+func LoadContext(ctx context.Context, name string, opts ...LoadOption) (*pack.Pack, error) {
 	fi, err := os.Stat(name)
 	if err != nil {
 		return nil, err
 	}
-	if !fi.IsDir() {
+	archive := fi.Mode().IsRegular() && hasArchiveExt(name)
+	if !fi.IsDir() && !archive {
 		return nil, errors.New("unable to load non-directory pack")
 	}
-	return loadDir(name)
+
+	wOpts := walkOptions{maxDepth: MaxLoadDepth}
+	for _, opt := range opts {
+		opt(&wOpts)
+	}
+
+	var p *pack.Pack
+	if archive {
+		p, err = loadArchive(ctx, name, wOpts)
+	} else {
+		p, err = loadDir(ctx, name, wOpts)
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return nil, fmt.Errorf("failed to load pack %q: %w", name, err)
+	}
+	return p, err
 }
 
-func loadDir(dir string) (*pack.Pack, error) {
+func loadDir(ctx context.Context, dir string, opts walkOptions) (*pack.Pack, error) {
 	abs, err := filepath.Abs(dir)
 	if err != nil {
 		return nil, err
@@ -69,7 +128,7 @@ func loadDir(dir string) (*pack.Pack, error) {
 		return nil
 	}
 
-	if err = walk(abs, walkFn); err != nil {
+	if err = walk(ctx, abs, opts, walkFn); err != nil {
 		return nil, err
 	}
 	return loadFiles(files)
@@ -78,6 +137,7 @@ func loadDir(dir string) (*pack.Pack, error) {
 func loadFiles(files []*pack.File) (*pack.Pack, error) {
 
 	p := new(pack.Pack)
+	var decodeErr error
 
 	for _, f := range files {
 		switch {
@@ -88,9 +148,7 @@ func loadFiles(files []*pack.File) (*pack.Pack, error) {
 			if p.Metadata == nil {
 				p.Metadata = new(pack.Metadata)
 			}
-			if err := hclsimple.Decode(f.Name, f.Content, nil, p.Metadata); err != nil {
-				return p, fmt.Errorf("failed to decode %s: %v", f.Name, err)
-			}
+			decodeErr = hclsimple.Decode(f.Name, f.Content, nil, p.Metadata)
 
 		case f.Name == "variables.hcl":
 			p.RootVariableFile = f
@@ -119,9 +177,5 @@ func loadFiles(files []*pack.File) (*pack.Pack, error) {
 		}
 	}
 
-	// Validate the metadata.
-	if p.Metadata == nil {
-		return p, errors.New("metadata.hcl file not found")
-	}
-	return p, p.Metadata.Validate()
+	return p, validateStructure(p, decodeErr)
 }
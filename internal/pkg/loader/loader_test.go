@@ -0,0 +1,285 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package loader
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shoenig/test/must"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const testMetadata = `
+app {
+  url = "https://example.com"
+}
+
+pack {
+  name    = "example"
+  version = "0.0.1"
+}
+`
+
+// writeTestPack writes a minimal pack to a temporary directory and returns
+// its path.
+func writeTestPack(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	must.NoError(t, os.WriteFile(filepath.Join(dir, "metadata.hcl"), []byte(testMetadata), 0644))
+	must.NoError(t, os.WriteFile(filepath.Join(dir, "variables.hcl"), nil, 0644))
+	must.NoError(t, os.Mkdir(filepath.Join(dir, "templates"), 0755))
+	must.NoError(t, os.WriteFile(filepath.Join(dir, "templates", "example.nomad.tpl"), []byte(`job "example" {}`), 0644))
+	return dir
+}
+
+func TestLoadContext(t *testing.T) {
+	dir := writeTestPack(t)
+
+	p, err := LoadContext(context.Background(), dir)
+	must.NoError(t, err)
+	must.Eq(t, "example", p.Metadata.Pack.Name)
+	must.Len(t, 1, p.TemplateFiles)
+}
+
+func TestLoadContext_CustomMetadata(t *testing.T) {
+	dir := writeTestPack(t)
+	must.NoError(t, os.WriteFile(filepath.Join(dir, "metadata.hcl"), []byte(`
+app {
+  url = "https://example.com"
+}
+
+pack {
+  name       = "example"
+  version    = "0.0.1"
+  maintainer = "platform-team"
+  tags       = ["web", "edge"]
+}
+`), 0644))
+
+	p, err := LoadContext(context.Background(), dir)
+	must.NoError(t, err)
+	must.Eq(t, "example", p.Metadata.Pack.Name)
+	must.MapLen(t, 2, p.Metadata.Pack.Custom)
+	must.Eq(t, "platform-team", p.Metadata.Pack.Custom["maintainer"].AsString())
+	must.True(t, p.Metadata.Pack.Custom["tags"].Equals(cty.TupleVal([]cty.Value{cty.StringVal("web"), cty.StringVal("edge")})).True())
+}
+
+func TestLoadContext_Structure(t *testing.T) {
+	testCases := []struct {
+		name        string
+		setup       func(t *testing.T, dir string)
+		problems    []string
+		suggestions int
+	}{
+		{
+			name: "missing files",
+			setup: func(t *testing.T, dir string) {
+				must.NoError(t, os.RemoveAll(filepath.Join(dir, "metadata.hcl")))
+				must.NoError(t, os.RemoveAll(filepath.Join(dir, "variables.hcl")))
+				must.NoError(t, os.RemoveAll(filepath.Join(dir, "templates")))
+			},
+			problems: []string{
+				"metadata.hcl file not found",
+				"variables.hcl file not found",
+				"templates directory not found or contains no templates",
+			},
+			suggestions: 3,
+		},
+		{
+			name: "malformed metadata",
+			setup: func(t *testing.T, dir string) {
+				must.NoError(t, os.WriteFile(filepath.Join(dir, "metadata.hcl"), []byte("pack {"), 0644))
+			},
+			problems:    []string{"failed to decode metadata.hcl: metadata.hcl:1,"},
+			suggestions: 1,
+		},
+		{
+			name: "invalid metadata",
+			setup: func(t *testing.T, dir string) {
+				must.NoError(t, os.WriteFile(filepath.Join(dir, "metadata.hcl"), []byte(`app {
+  url = "https://example.com"
+}`), 0644))
+			},
+			problems: []string{"invalid metadata.hcl: Pack metadata is uninitialized"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := writeTestPack(t)
+			tc.setup(t, dir)
+
+			_, err := LoadContext(context.Background(), dir)
+			var structErr *StructureError
+			must.True(t, errors.As(err, &structErr))
+			must.Len(t, len(tc.problems), structErr.Problems)
+			for i, problem := range tc.problems {
+				must.StrHasPrefix(t, problem, structErr.Problems[i])
+			}
+			must.Len(t, tc.suggestions, structErr.Suggestions)
+		})
+	}
+}
+
+func TestLoadContext_Cancelled(t *testing.T) {
+	dir := writeTestPack(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := LoadContext(ctx, dir)
+	must.ErrorIs(t, err, context.Canceled)
+}
+
+func TestLoadContext_MaxDepth(t *testing.T) {
+	dir := writeTestPack(t)
+
+	// A symlink to the pack directory causes the pack to contain itself.
+	must.NoError(t, os.Symlink(dir, filepath.Join(dir, "templates", "loop")))
+
+	_, err := LoadContext(context.Background(), dir)
+	must.ErrorIs(t, err, ErrMaxDepthExceeded)
+}
+
+func TestLoadContext_ExternalSymlink(t *testing.T) {
+	dir := writeTestPack(t)
+
+	external := filepath.Join(t.TempDir(), "secret.tpl")
+	must.NoError(t, os.WriteFile(external, []byte("secret"), 0644))
+	link := filepath.Join(dir, "templates", "secret.tpl")
+	must.NoError(t, os.Symlink(external, link))
+
+	_, err := LoadContext(context.Background(), dir)
+	must.ErrorIs(t, err, ErrExternalSymlink)
+	must.ErrorContains(t, err, link)
+
+	p, err := LoadContext(context.Background(), dir, AllowExternalSymlinks(true))
+	must.NoError(t, err)
+	must.Len(t, 1, p.AuxiliaryFiles)
+}
+
+func TestLoadContext_InternalSymlink(t *testing.T) {
+	dir := writeTestPack(t)
+
+	must.NoError(t, os.Symlink(
+		filepath.Join(dir, "templates", "example.nomad.tpl"),
+		filepath.Join(dir, "templates", "_helpers.tpl"),
+	))
+
+	p, err := LoadContext(context.Background(), dir)
+	must.NoError(t, err)
+	must.Len(t, 2, p.TemplateFiles)
+}
+
+// writeTestArchive writes the passed entries to an archive of the type given by
+// the extension of name, and returns the archive path. Entries with a trailing
+// slash are directories.
+func writeTestArchive(t *testing.T, name string, entries map[string]string) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if filepath.Ext(name) == ".zip" {
+		zw := zip.NewWriter(&buf)
+		for entry, content := range entries {
+			w, err := zw.Create(entry)
+			must.NoError(t, err)
+			_, err = w.Write([]byte(content))
+			must.NoError(t, err)
+		}
+		must.NoError(t, zw.Close())
+	} else {
+		gw := gzip.NewWriter(&buf)
+		tw := tar.NewWriter(gw)
+		for entry, content := range entries {
+			hdr := &tar.Header{Name: entry, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}
+			if entry[len(entry)-1] == '/' {
+				hdr.Mode, hdr.Typeflag = 0755, tar.TypeDir
+			}
+			must.NoError(t, tw.WriteHeader(hdr))
+			_, err := tw.Write([]byte(content))
+			must.NoError(t, err)
+		}
+		must.NoError(t, tw.Close())
+		must.NoError(t, gw.Close())
+	}
+
+	path := filepath.Join(t.TempDir(), name)
+	must.NoError(t, os.WriteFile(path, buf.Bytes(), 0644))
+	return path
+}
+
+func TestLoadContext_Archive(t *testing.T) {
+	for _, name := range []string{"example-0.0.1.tar.gz", "example-0.0.1.tgz", "example-0.0.1.zip"} {
+		t.Run(name, func(t *testing.T) {
+			archive := writeTestArchive(t, name, map[string]string{
+				"example/":                            "",
+				"example/metadata.hcl":                testMetadata,
+				"example/variables.hcl":               "",
+				"example/templates/example.nomad.tpl": `job "example" {}`,
+			})
+			must.True(t, IsArchive(archive))
+
+			p, err := LoadContext(context.Background(), archive)
+			must.NoError(t, err)
+			must.Eq(t, "example", p.Metadata.Pack.Name)
+			must.Len(t, 1, p.TemplateFiles)
+			must.Eq(t, filepath.Join(archive, "templates", "example.nomad.tpl"), p.TemplateFiles[0].Path)
+		})
+	}
+}
+
+func TestLoadContext_ArchiveInvalid(t *testing.T) {
+	testCases := []struct {
+		name    string
+		entries map[string]string
+		err     error
+		errMsg  string
+	}{
+		{
+			name:    "parent.tar.gz",
+			entries: map[string]string{"example/../../secret.tpl": "secret"},
+			err:     ErrArchivePathTraversal,
+		},
+		{
+			name:    "parent.zip",
+			entries: map[string]string{"../secret.tpl": "secret"},
+			err:     ErrArchivePathTraversal,
+		},
+		{
+			name:    "absolute.tar.gz",
+			entries: map[string]string{"/tmp/secret.tpl": "secret"},
+			err:     ErrArchivePathTraversal,
+		},
+		{
+			name: "roots.zip",
+			entries: map[string]string{
+				"example/metadata.hcl": testMetadata,
+				"other/metadata.hcl":   testMetadata,
+			},
+			errMsg: "must contain a single top-level pack directory",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			archive := writeTestArchive(t, tc.name, tc.entries)
+
+			_, err := LoadContext(context.Background(), archive)
+			must.Error(t, err)
+			if tc.err != nil {
+				must.ErrorIs(t, err, tc.err)
+			} else {
+				must.ErrorContains(t, err, tc.errMsg)
+			}
+		})
+	}
+}
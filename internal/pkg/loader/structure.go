@@ -0,0 +1,78 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package loader
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad-pack/sdk/pack"
+)
+
+// ErrInvalidStructure is wrapped by a StructureError.
+var ErrInvalidStructure = errors.New("invalid pack structure")
+
+// StructureError is returned when a pack is missing a required file or
+// directory, or one of them is invalid. It lists every problem found, rather
+// than only the first, so they can all be fixed at once.
+type StructureError struct {
+	// Problems describes each missing or invalid element of the pack.
+	Problems []string
+
+	// Suggestions describes how to fix the problems.
+	Suggestions []string
+}
+
+// Error implements the error interface.
+func (e *StructureError) Error() string {
+	return ErrInvalidStructure.Error() + ": " + strings.Join(e.Problems, "; ")
+}
+
+// Unwrap returns ErrInvalidStructure.
+func (e *StructureError) Unwrap() error { return ErrInvalidStructure }
+
+func (e *StructureError) add(problem, suggestion string) {
+	e.Problems = append(e.Problems, problem)
+	if suggestion != "" {
+		e.Suggestions = append(e.Suggestions, suggestion)
+	}
+}
+
+// validateStructure checks the loaded pack has the files every pack requires,
+// which are a valid metadata.hcl, a variables.hcl, and at least one template
+// within the templates directory. The decodeErr is the error decoding
+// metadata.hcl, if any. It returns a StructureError listing each problem, or
+// nil if there are none.
+func validateStructure(p *pack.Pack, decodeErr error) error {
+	var err StructureError
+
+	switch {
+	case p.Metadata == nil:
+		err.add("metadata.hcl file not found",
+			"Add a metadata.hcl file to the pack directory, declaring the app and pack blocks")
+	case decodeErr != nil:
+		err.add(fmt.Sprintf("failed to decode metadata.hcl: %v", decodeErr),
+			"Fix the syntax of metadata.hcl")
+	default:
+		if vErr := p.Metadata.Validate(); vErr != nil {
+			err.add(fmt.Sprintf("invalid metadata.hcl: %v", vErr), "")
+		}
+	}
+
+	if p.RootVariableFile == nil {
+		err.add("variables.hcl file not found",
+			"Add a variables.hcl file to the pack directory, which may be empty if the pack has no variables")
+	}
+
+	if len(p.TemplateFiles) == 0 && len(p.AuxiliaryFiles) == 0 {
+		err.add("templates directory not found or contains no templates",
+			"Add the templates of the pack to the templates directory, named with the .nomad.tpl extension")
+	}
+
+	if len(err.Problems) > 0 {
+		return &err
+	}
+	return nil
+}
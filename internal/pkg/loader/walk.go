@@ -4,18 +4,42 @@
 package loader
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 )
 
-func walk(root string, walkFn filepath.WalkFunc) error {
+// walkOptions controls how walk traverses the file tree.
+type walkOptions struct {
+	// maxDepth is the number of directories a file may be nested within below
+	// the root.
+	maxDepth int
+
+	// allowExternalSymlinks allows following symlinks which resolve to a path
+	// outside of the root.
+	allowExternalSymlinks bool
+}
+
+// walk walks the file tree rooted at root, following symlinked directories.
+// The walk stops when the context is cancelled, when a directory is nested
+// deeper than the maximum depth below the root, or when a symlink resolves
+// outside of the root unless this is allowed.
+func walk(ctx context.Context, root string, opts walkOptions, walkFn filepath.WalkFunc) error {
 	info, err := os.Lstat(root)
 	if err != nil {
 		err = walkFn(root, nil, err)
 	} else {
-		err = symwalk(root, info, walkFn)
+		// Resolve the root, so it can be compared with the resolved path of
+		// symlinks within it.
+		var resolvedRoot string
+		if resolvedRoot, err = filepath.EvalSymlinks(root); err != nil {
+			return err
+		}
+		w := walker{ctx: ctx, root: resolvedRoot, opts: opts, walkFn: walkFn}
+		err = w.symwalk(root, info, 0)
 	}
 	if err == filepath.SkipDir {
 		return nil
@@ -23,23 +47,44 @@ func walk(root string, walkFn filepath.WalkFunc) error {
 	return err
 }
 
-func symwalk(path string, info os.FileInfo, walkFn filepath.WalkFunc) error {
+// walker holds the state shared by each step of a walk.
+type walker struct {
+	ctx    context.Context
+	root   string
+	opts   walkOptions
+	walkFn filepath.WalkFunc
+}
+
+func (w *walker) symwalk(path string, info os.FileInfo, depth int) error {
+	if err := w.ctx.Err(); err != nil {
+		return err
+	}
+
+	// Symlinked directories which refer to a parent directory would otherwise
+	// be walked forever, so limit how deep the walk may go.
+	if depth > w.opts.maxDepth {
+		return fmt.Errorf("%w: %q is nested more than %d directories deep", ErrMaxDepthExceeded, path, w.opts.maxDepth)
+	}
+
 	// Recursively walk symlinked directories.
 	if isSymlink(info) {
 		resolved, err := filepath.EvalSymlinks(path)
 		if err != nil {
 			return fmt.Errorf("error evaluating symlink: %v", err)
 		}
+		if !w.opts.allowExternalSymlinks && !withinDir(w.root, resolved) {
+			return fmt.Errorf("%w: %q resolves to %q", ErrExternalSymlink, path, resolved)
+		}
 		if info, err = os.Lstat(resolved); err != nil {
 			return err
 		}
-		if err := symwalk(path, info, walkFn); err != nil && err != filepath.SkipDir {
+		if err := w.symwalk(path, info, depth); err != nil && err != filepath.SkipDir {
 			return err
 		}
 		return nil
 	}
 
-	if err := walkFn(path, info, nil); err != nil {
+	if err := w.walkFn(path, info, nil); err != nil {
 		return err
 	}
 
@@ -49,18 +94,18 @@ func symwalk(path string, info os.FileInfo, walkFn filepath.WalkFunc) error {
 
 	names, err := readDirNames(path)
 	if err != nil {
-		return walkFn(path, info, err)
+		return w.walkFn(path, info, err)
 	}
 
 	for _, name := range names {
 		filename := filepath.Join(path, name)
 		fileInfo, err := os.Lstat(filename)
 		if err != nil {
-			if wErr := walkFn(filename, fileInfo, err); wErr != nil && wErr != filepath.SkipDir {
+			if wErr := w.walkFn(filename, fileInfo, err); wErr != nil && wErr != filepath.SkipDir {
 				return wErr
 			}
 		} else {
-			wErr := symwalk(filename, fileInfo, walkFn)
+			wErr := w.symwalk(filename, fileInfo, depth+1)
 			if wErr != nil {
 				if (!fileInfo.IsDir() && !isSymlink(fileInfo)) || wErr != filepath.SkipDir {
 					return wErr
@@ -84,6 +129,15 @@ func readDirNames(dirname string) ([]string, error) {
 	return names, f.Close()
 }
 
+// withinDir returns whether the path is the directory or is nested within it.
+func withinDir(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
 func isSymlink(fi os.FileInfo) bool {
 	return fi.Mode()&os.ModeSymlink != 0
 }
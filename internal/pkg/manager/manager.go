@@ -5,7 +5,9 @@ package manager
 
 import (
 	"fmt"
+	"maps"
 	"path"
+	"slices"
 	"strings"
 
 	"github.com/hashicorp/hcl/v2"
@@ -15,7 +17,9 @@ import (
 	"github.com/hashicorp/nomad-pack/internal/pkg/variable/parser"
 	"github.com/hashicorp/nomad-pack/internal/pkg/variable/parser/config"
 	"github.com/hashicorp/nomad-pack/sdk/pack"
+	"github.com/hashicorp/nomad-pack/sdk/pack/variables"
 	"github.com/hashicorp/nomad/api"
+	"github.com/zclconf/go-cty/cty"
 )
 
 // Config contains all the user specified parameters needed to correctly run
@@ -24,9 +28,41 @@ type Config struct {
 	Path            string
 	VariableFiles   []string
 	VariableCLIArgs map[string]string
+	VariablePatches map[string]string
 	VariableEnvVars map[string]string
 	UseParserV1     bool
 	AllowUnsetVars  bool
+
+	// Profile is the name of the variable profile, defined in ProfileFile,
+	// whose overrides are applied.
+	Profile     string
+	ProfileFile string
+
+	// MaxVariableFileSize is the maximum size, in bytes, of the variable
+	// files. The parser default is used when zero.
+	MaxVariableFileSize int64
+
+	// ResolveVariableRefs resolves the values of VariableCLIArgs and
+	// VariableEnvVars which reference a value resolver.
+	ResolveVariableRefs bool
+
+	// AllowExternalSymlinks allows the packs to contain symlinks which
+	// resolve outside of the pack directory.
+	AllowExternalSymlinks bool
+
+	// PromptVariable is called for each required variable which has not been
+	// set, with its name relative to the parent pack, and returns the value
+	// of the variable. Unset required variables are an error when it is nil.
+	PromptVariable func(name string, v *variables.Variable) (cty.Value, error)
+
+	// Warning is called with each warning found while parsing the variables,
+	// such as a deprecated variable being set. Warnings are discarded when it
+	// is nil.
+	Warning func(msg string)
+
+	// ParseCacheDir is the directory used to cache parsed variables between
+	// runs. Caching is disabled when it is empty.
+	ParseCacheDir string
 }
 
 // PackManager is responsible for loading, parsing, and rendering a Pack and
@@ -38,6 +74,12 @@ type PackManager struct {
 
 	// loadedPack is unavailable until the loadAndValidatePacks func is run.
 	loadedPack *pack.Pack
+
+	// tplCtx is unavailable until the ProcessTemplates func is run.
+	tplCtx parser.PackTemplateContext
+
+	// parsedVars is unavailable until the ProcessTemplates func is run.
+	parsedVars *parser.ParsedVariables
 }
 
 func NewPackManager(cfg *Config, client *api.Client) *PackManager {
@@ -73,6 +115,12 @@ func (pm *PackManager) ProcessVariableFiles() (*parser.ParsedVariables, []*error
 		EnvOverrides:      pm.cfg.VariableEnvVars,
 		FileOverrides:     pm.cfg.VariableFiles,
 		FlagOverrides:     pm.cfg.VariableCLIArgs,
+		PatchOverrides:    pm.cfg.VariablePatches,
+		Profile:           pm.cfg.Profile,
+		ProfileFile:       pm.cfg.ProfileFile,
+
+		ResolveValueRefs:    pm.cfg.ResolveVariableRefs,
+		MaxVariableFileSize: pm.cfg.MaxVariableFileSize,
 	}
 
 	if pm.cfg.UseParserV1 {
@@ -89,15 +137,47 @@ func (pm *PackManager) ProcessVariableFiles() (*parser.ParsedVariables, []*error
 		}}
 	}
 
+	if pm.cfg.ParseCacheDir != "" && !pm.cfg.UseParserV1 {
+		if key, err := parser.ParseCacheKey(pm.cfg.Path, pCfg); err == nil {
+			variableParser = parser.NewCachingParser(variableParser, pm.cfg.ParseCacheDir, key)
+		}
+	}
+
 	parsedVars, diags := variableParser.Parse()
 	if diags != nil && diags.HasErrors() {
 		return nil, errors.HCLDiagsToWrappedUIContext(diags)
 	}
+	if pm.cfg.Warning != nil {
+		for _, diag := range diags {
+			pm.cfg.Warning(diag.Detail)
+		}
+	}
 
-	// Ensure required variables are set, unless --allow-unset-vars
+	// Only errors remain in diags, so warnings are not reported as errors
+	// alongside any missing required variables below.
+	diags = nil
+
+	// Ensure required variables are set, unless --allow-unset-vars. They are
+	// prompted for when possible, in a consistent order.
 	if !pm.cfg.AllowUnsetVars {
-		for _, vars := range parsedVars.GetVars() {
-			for _, v := range vars {
+		vars := parsedVars.GetVars()
+		for _, pID := range slices.Sorted(maps.Keys(vars)) {
+			for _, vID := range slices.Sorted(maps.Keys(vars[pID])) {
+				v := vars[pID][vID]
+				if v.Value.IsNull() && pm.cfg.PromptVariable != nil {
+					name := strings.TrimPrefix(pID.Join(pack.ID(vID)).String(), loadedPack.ID().String()+".")
+					val, err := pm.cfg.PromptVariable(name, v)
+					if err != nil {
+						return nil, []*errors.WrappedUIContext{{
+							Err:     err,
+							Subject: "failed to prompt for required variable",
+							Context: errors.NewUIErrorContext(),
+						}}
+					}
+					v.Value = val
+					v.ValueSource = "prompt"
+					continue
+				}
 				if v.Value.IsNull() {
 					detail := fmt.Sprintf("missing required variable: %q", v.Name)
 					if v.Description != "" {
@@ -140,6 +220,9 @@ func (pm *PackManager) ProcessTemplates(renderAux bool, format bool, ignoreMissi
 		return nil, errors.HCLDiagsToWrappedUIContext(diags)
 	}
 
+	pm.tplCtx = tplCtx
+	pm.parsedVars = parsedVars
+
 	r := new(renderer.Renderer)
 	r.Client = pm.client
 	pm.renderer = r
@@ -159,18 +242,61 @@ func (pm *PackManager) ProcessTemplates(renderAux bool, format bool, ignoreMissi
 	return rendered, nil
 }
 
+// VariableValues returns the values of the variables the templates were
+// rendered with, keyed by their name relative to the parent pack, such as
+// child.count, and formatted as they would be written in a variable file.
+// Variables without a value are omitted. It is unavailable until the
+// ProcessTemplates func is run.
+func (pm *PackManager) VariableValues() map[string]string {
+	if pm.parsedVars == nil {
+		return nil
+	}
+
+	out := make(map[string]string)
+	for pID, vars := range pm.parsedVars.GetVars() {
+		for vID, v := range vars {
+			// Sensitive values are not recorded, as they are stored with the
+			// deployed pack.
+			if v.Sensitive || v.Value.IsNull() || !v.Value.IsWhollyKnown() {
+				continue
+			}
+			name := strings.TrimPrefix(pID.Join(pack.ID(vID)).String(), pm.loadedPack.ID().String()+".")
+			out[name] = variables.FormatValue(v.Value)
+		}
+	}
+	return out
+}
+
 // ProcessOutputTemplate performs the output template rendering.
 func (pm *PackManager) ProcessOutputTemplate() (string, error) {
 	return pm.renderer.RenderOutput()
 }
 
+// RenderOutputTemplate renders the pack templates followed by the output
+// template, without deploying the pack. This allows pack authors to preview
+// the output template. Errors from the output template are returned with the
+// same context as errors from the pack templates.
+func (pm *PackManager) RenderOutputTemplate(ignoreMissingVars bool) (string, []*errors.WrappedUIContext) {
+	if _, wErr := pm.ProcessTemplates(false, false, ignoreMissingVars); wErr != nil {
+		return "", wErr
+	}
+
+	out, err := pm.ProcessOutputTemplate()
+	if err != nil {
+		return "", []*errors.WrappedUIContext{
+			errors.ParseTemplateError(pm.tplCtx, err).ToWrappedUIContext(),
+		}
+	}
+	return out, nil
+}
+
 // loadAndValidatePacks triggers the initial parent load and then starts the
 // dependent pack loader. The returned pack will therefore be fully populated.
 func (pm *PackManager) loadAndValidatePacks() (*pack.Pack, error) {
 
-	parentPack, err := loader.Load(pm.cfg.Path)
+	parentPack, err := loader.Load(pm.cfg.Path, loader.AllowExternalSymlinks(pm.cfg.AllowExternalSymlinks))
 	if err != nil {
-		return nil, fmt.Errorf("failed to load pack: %v", err)
+		return nil, fmt.Errorf("failed to load pack: %w", err)
 	}
 
 	if err := parentPack.Validate(); err != nil {
@@ -182,12 +308,23 @@ func (pm *PackManager) loadAndValidatePacks() (*pack.Pack, error) {
 	depsPath := path.Join(pm.cfg.Path, "deps")
 
 	if err := pm.loadAndValidatePack(parentPack, depsPath); err != nil {
-		return nil, fmt.Errorf("failed to load pack dependency: %v", err)
+		return nil, fmt.Errorf("failed to load pack dependency: %w", err)
 	}
 
 	return parentPack, nil
 }
 
+// LoadPack loads and validates the pack and all of its dependencies, without
+// parsing their variables.
+func (pm *PackManager) LoadPack() (*pack.Pack, error) {
+	loadedPack, err := pm.loadAndValidatePacks()
+	if err != nil {
+		return nil, err
+	}
+	pm.loadedPack = loadedPack
+	return loadedPack, nil
+}
+
 // loadAndValidatePack recursively loads a pack and its dependencies. Errors
 // result in an immediate return.
 func (pm *PackManager) loadAndValidatePack(cur *pack.Pack, depsPath string) error {
@@ -201,9 +338,9 @@ func (pm *PackManager) loadAndValidatePack(cur *pack.Pack, depsPath string) erro
 
 		// Load and validate the dependency pack.
 		packPath := path.Join(depsPath, path.Clean(dep.Name))
-		depPack, err := loader.Load(packPath)
+		depPack, err := loader.Load(packPath, loader.AllowExternalSymlinks(pm.cfg.AllowExternalSymlinks))
 		if err != nil {
-			return fmt.Errorf("failed to load dependent pack: %v", err)
+			return fmt.Errorf("failed to load dependent pack: %w", err)
 		}
 
 		if err := depPack.Validate(); err != nil {
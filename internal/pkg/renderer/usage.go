@@ -0,0 +1,205 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package renderer
+
+import (
+	"fmt"
+	"strings"
+	"text/template/parse"
+
+	"github.com/hashicorp/nomad-pack/sdk/pack"
+	"github.com/hashicorp/nomad-pack/sdk/pack/variables"
+)
+
+// VariableUsage holds the variables referenced by the templates of a pack and
+// its dependencies, found by scanning the parsed templates rather than
+// rendering them.
+type VariableUsage struct {
+	// referenced holds the names of the variables referenced using the var
+	// and must_var template functions, keyed by the ID of the pack the
+	// variables belong to.
+	referenced map[pack.ID]map[variables.ID]struct{}
+
+	// dynamic holds the IDs of the packs whose variables are accessed in a
+	// way which cannot be resolved without rendering, such as using the vars
+	// template function or a variable name which is not a string literal.
+	dynamic map[pack.ID]struct{}
+}
+
+// Used returns whether the variable of the pack is referenced by a template.
+// Every variable of a pack whose variables are accessed dynamically is
+// considered used, since the references cannot be determined.
+func (u *VariableUsage) Used(pID pack.ID, name variables.ID) bool {
+	if u.Dynamic(pID) {
+		return true
+	}
+	_, ok := u.referenced[pID][name]
+	return ok
+}
+
+// Dynamic returns whether the variables of the pack are accessed in a way
+// which cannot be resolved without rendering the templates.
+func (u *VariableUsage) Dynamic(pID pack.ID) bool {
+	_, ok := u.dynamic[pID]
+	return ok
+}
+
+// ScanVariableUsage parses the template, auxiliary, and output template files
+// of the pack and its dependencies, and returns the variables they reference.
+// Within a template, the dot is taken to be the template context of the pack
+// which owns it, so a reference such as `var "name" .dep` refers to the
+// variable of the dependency aliased as dep.
+func ScanVariableUsage(p *pack.Pack) (*VariableUsage, error) {
+	u := &VariableUsage{
+		referenced: make(map[pack.ID]map[variables.ID]struct{}),
+		dynamic:    make(map[pack.ID]struct{}),
+	}
+	if err := u.scanPack(p, p.ID()); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+func (u *VariableUsage) scanPack(p *pack.Pack, pID pack.ID) error {
+	files := append(append([]*pack.File{}, p.TemplateFiles...), p.AuxiliaryFiles...)
+	if p.OutputTemplateFile != nil {
+		files = append(files, p.OutputTemplateFile)
+	}
+
+	for _, f := range files {
+		t := parse.New(f.Name)
+		t.Mode = parse.SkipFuncCheck
+		trees := make(map[string]*parse.Tree)
+		if _, err := t.Parse(string(f.Content), leftTemplateDelim, rightTemplateDelim, trees); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", f.Path, err)
+		}
+		for _, tree := range trees {
+			u.walk(tree.Root, pID)
+		}
+	}
+
+	for _, dep := range p.Dependencies() {
+		if err := u.scanPack(dep, pID.Join(dep.ID())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// walk records the variable references within the node and its children.
+func (u *VariableUsage) walk(node parse.Node, pID pack.ID) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			u.walk(child, pID)
+		}
+	case *parse.ActionNode:
+		u.walk(n.Pipe, pID)
+	case *parse.IfNode:
+		u.walkBranch(&n.BranchNode, pID)
+	case *parse.RangeNode:
+		u.walkBranch(&n.BranchNode, pID)
+	case *parse.WithNode:
+		u.walkBranch(&n.BranchNode, pID)
+	case *parse.TemplateNode:
+		if n.Pipe != nil {
+			u.walk(n.Pipe, pID)
+		}
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			u.walk(cmd, pID)
+		}
+	case *parse.ChainNode:
+		u.walk(n.Node, pID)
+	case *parse.CommandNode:
+		u.walkCommand(n, pID)
+	}
+}
+
+func (u *VariableUsage) walkBranch(n *parse.BranchNode, pID pack.ID) {
+	u.walk(n.Pipe, pID)
+	u.walk(n.List, pID)
+	u.walk(n.ElseList, pID)
+}
+
+// walkCommand records the reference made by a call to one of the variable
+// template functions, and walks its arguments for nested calls.
+func (u *VariableUsage) walkCommand(n *parse.CommandNode, pID pack.ID) {
+	for _, arg := range n.Args {
+		u.walk(arg, pID)
+	}
+
+	ident, ok := n.Args[0].(*parse.IdentifierNode)
+	if !ok {
+		return
+	}
+
+	switch ident.Ident {
+	case "var", "must_var":
+		// The context is the last argument, which is omitted when it is
+		// piped into the call.
+		target := pID
+		if len(n.Args) > 2 {
+			var ok bool
+			if target, ok = contextPackID(n.Args[2], pID); !ok {
+				u.dynamic[pID] = struct{}{}
+				return
+			}
+		}
+
+		var name *parse.StringNode
+		if len(n.Args) > 1 {
+			name, _ = n.Args[1].(*parse.StringNode)
+		}
+		if name == nil {
+			u.dynamic[target] = struct{}{}
+			return
+		}
+		varName, _, _ := strings.Cut(name.Text, ".")
+		if u.referenced[target] == nil {
+			u.referenced[target] = make(map[variables.ID]struct{})
+		}
+		u.referenced[target][variables.ID(varName)] = struct{}{}
+
+	case "vars":
+		target := pID
+		if len(n.Args) > 1 {
+			var ok bool
+			if target, ok = contextPackID(n.Args[1], pID); !ok {
+				target = pID
+			}
+		}
+		u.dynamic[target] = struct{}{}
+	}
+}
+
+// contextPackID returns the ID of the pack selected by a template context
+// argument, such as . for the current pack or .dep for its dependency
+// aliased as dep.
+func contextPackID(node parse.Node, pID pack.ID) (pack.ID, bool) {
+	var path []string
+	switch n := node.(type) {
+	case *parse.DotNode:
+	case *parse.FieldNode:
+		path = n.Ident
+	case *parse.VariableNode:
+		if n.Ident[0] != "$" {
+			return "", false
+		}
+		path = n.Ident[1:]
+	default:
+		return "", false
+	}
+
+	for _, alias := range path {
+		pID = pID.Join(pack.ID(alias))
+	}
+	return pID, true
+}
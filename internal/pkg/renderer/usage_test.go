@@ -0,0 +1,82 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package renderer
+
+import (
+	"testing"
+
+	"github.com/shoenig/test/must"
+
+	"github.com/hashicorp/nomad-pack/sdk/pack"
+	"github.com/hashicorp/nomad-pack/sdk/pack/variables"
+)
+
+func TestScanVariableUsage(t *testing.T) {
+	newPack := func(name string, templates ...string) *pack.Pack {
+		p := &pack.Pack{Metadata: &pack.Metadata{Pack: &pack.MetadataPack{Name: name}}}
+		for i, content := range templates {
+			p.TemplateFiles = append(p.TemplateFiles, &pack.File{
+				Name:    name + "/templates/" + string(rune('a'+i)) + ".nomad.tpl",
+				Content: []byte(content),
+			})
+		}
+		return p
+	}
+
+	dep := newPack("dep", `[[ vars . | toJson ]]`)
+	other := newPack("other", `[[ var "used" . ]]`)
+
+	root := newPack("root",
+		`job [[ var "name" . | quote ]] {
+  [[- if (must_var "enabled" .) ]]
+  count = [[ coalesce (var "count" .) 1 ]]
+  [[- end ]]
+  [[- range $k, $v := var "labels.env" $ ]][[ $k ]][[ end ]]
+  [[ template "helper" . ]]
+  image = [[ var "image" .other ]]
+}`,
+		`[[ define "helper" ]][[ with var "nested" . ]][[ . ]][[ end ]][[ end ]]`,
+	)
+	root.OutputTemplateFile = &pack.File{Name: "root/outputs.tpl", Content: []byte(`[[ var "url" . ]]`)}
+	root.AddDependency("dep", dep)
+	root.AddDependency("other", other)
+
+	u, err := ScanVariableUsage(root)
+	must.NoError(t, err)
+
+	for _, name := range []string{"name", "enabled", "count", "labels", "nested", "url"} {
+		must.True(t, u.Used("root", variables.ID(name)), must.Sprintf("expected %q to be used", name))
+	}
+	must.False(t, u.Used("root", "unused"))
+	must.False(t, u.Used("root", "image"))
+	must.False(t, u.Dynamic("root"))
+
+	must.True(t, u.Used("root.other", "image"))
+	must.True(t, u.Used("root.other", "used"))
+	must.False(t, u.Used("root.other", "unused"))
+
+	must.True(t, u.Dynamic("root.dep"))
+	must.True(t, u.Used("root.dep", "anything"))
+}
+
+func TestScanVariableUsage_DynamicName(t *testing.T) {
+	p := &pack.Pack{
+		Metadata:      &pack.Metadata{Pack: &pack.MetadataPack{Name: "example"}},
+		TemplateFiles: []*pack.File{{Name: "a.nomad.tpl", Content: []byte(`[[ $n := "count" ]][[ var $n . ]]`)}},
+	}
+
+	u, err := ScanVariableUsage(p)
+	must.NoError(t, err)
+	must.True(t, u.Dynamic("example"))
+}
+
+func TestScanVariableUsage_ParseError(t *testing.T) {
+	p := &pack.Pack{
+		Metadata:      &pack.Metadata{Pack: &pack.MetadataPack{Name: "example"}},
+		TemplateFiles: []*pack.File{{Name: "a.nomad.tpl", Path: "/example/templates/a.nomad.tpl", Content: []byte(`[[ if ]]`)}},
+	}
+
+	_, err := ScanVariableUsage(p)
+	must.ErrorContains(t, err, "failed to parse /example/templates/a.nomad.tpl")
+}
@@ -0,0 +1,158 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package varfile
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/nomad-pack/sdk/pack"
+	"github.com/hashicorp/nomad-pack/sdk/pack/variables"
+)
+
+// profilesSchema is the schema of a profiles file, which contains any number
+// of named profile blocks.
+var profilesSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{{Type: "profile", LabelNames: []string{"name"}}},
+}
+
+// profileSchema is the schema of a single profile block. The variables are
+// written using the same syntax as a variable override file, so they are
+// decoded from the expression rather than into a Go value.
+var profileSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "inherits"},
+		{Name: "variables"},
+	},
+}
+
+// profile is a named set of variable overrides, which may inherit the
+// overrides of other profiles.
+type profile struct {
+	inherits  []string
+	variables hcl.Expression
+	rng       hcl.Range
+}
+
+// DecodeProfile parses the profiles file and returns the variable overrides of
+// the named profile, including those it inherits. Profiles are inherited in
+// the order they are listed, and the overrides of a profile replace those it
+// inherits for the same variable.
+func DecodeProfile(root *pack.Pack, filename string, src []byte, name string) ([]*variables.Override, hcl.Diagnostics) {
+	file, diags := hclsyntax.ParseConfig(src, filename, hcl.Pos{Line: 1, Column: 1})
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	profiles, pDiags := decodeProfiles(file.Body)
+	diags = diags.Extend(pDiags)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	if _, ok := profiles[name]; !ok {
+		return nil, diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Unknown profile",
+			Detail: fmt.Sprintf("The profile %q is not defined in %s. Defined profiles: %s.",
+				name, filename, strings.Join(slices.Sorted(maps.Keys(profiles)), ", ")),
+			Subject: file.Body.MissingItemRange().Ptr(),
+		})
+	}
+
+	var out []*variables.Override
+	var resolve func(name string, chain []string, rng *hcl.Range) hcl.Diagnostics
+	resolve = func(name string, chain []string, rng *hcl.Range) hcl.Diagnostics {
+		p, ok := profiles[name]
+		if !ok {
+			return hcl.Diagnostics{{
+				Severity: hcl.DiagError,
+				Summary:  "Unknown profile",
+				Detail:   fmt.Sprintf("The profile %q inherits the profile %q, which is not defined.", chain[len(chain)-1], name),
+				Subject:  rng,
+			}}
+		}
+		if slices.Contains(chain, name) {
+			return hcl.Diagnostics{{
+				Severity: hcl.DiagError,
+				Summary:  "Profile inheritance cycle",
+				Detail:   fmt.Sprintf("The profile %q inherits itself: %s.", name, strings.Join(append(chain, name), " -> ")),
+				Subject:  rng,
+			}}
+		}
+		chain = append(chain, name)
+
+		var diags hcl.Diagnostics
+		for _, parent := range p.inherits {
+			diags = diags.Extend(resolve(parent, chain, p.rng.Ptr()))
+		}
+		if diags.HasErrors() {
+			return diags
+		}
+
+		vals, vDiags := decodeOverrides(root, p.variables, nil)
+		diags = diags.Extend(vDiags)
+		for _, v := range vals {
+			out = slices.DeleteFunc(out, func(e *variables.Override) bool {
+				return e.Path == v.Path && e.Name == v.Name
+			})
+			out = append(out, v)
+		}
+		return diags
+	}
+
+	diags = diags.Extend(resolve(name, nil, nil))
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	return out, diags
+}
+
+// decodeProfiles decodes the profile blocks of the body, keyed by their name.
+func decodeProfiles(body hcl.Body) (map[string]*profile, hcl.Diagnostics) {
+	content, diags := body.Content(profilesSchema)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	out := make(map[string]*profile, len(content.Blocks))
+	for _, block := range content.Blocks {
+		name := block.Labels[0]
+		if existing, ok := out[name]; ok {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Duplicate profile",
+				Detail:   fmt.Sprintf("The profile %q is already defined at %s.", name, existing.rng),
+				Subject:  block.DefRange.Ptr(),
+			})
+			continue
+		}
+
+		pContent, pDiags := block.Body.Content(profileSchema)
+		diags = diags.Extend(pDiags)
+		if pDiags.HasErrors() {
+			continue
+		}
+
+		p := &profile{
+			rng: block.DefRange,
+			// An empty object, so profiles which only inherit others have no
+			// overrides of their own.
+			variables: &hclsyntax.ObjectConsExpr{SrcRange: block.DefRange},
+		}
+		if attr, ok := pContent.Attributes["inherits"]; ok {
+			diags = diags.Extend(gohcl.DecodeExpression(attr.Expr, nil, &p.inherits))
+		}
+		if attr, ok := pContent.Attributes["variables"]; ok {
+			p.variables = attr.Expr
+		}
+		out[name] = p
+	}
+	return out, diags
+}
@@ -0,0 +1,103 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package varfile
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad-pack/sdk/pack"
+	"github.com/hashicorp/nomad-pack/sdk/pack/variables"
+	"github.com/shoenig/test/must"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestVarfile_DecodeProfile(t *testing.T) {
+	src := []byte(`
+profile "base" {
+  variables = {
+    region    = "eu"
+    count     = 1
+    dep.image = "web:1"
+  }
+}
+
+profile "large" {
+  variables = {
+    count = 5
+  }
+}
+
+profile "prod" {
+  inherits  = ["base", "large"]
+  variables = {
+    region = "us"
+  }
+}
+
+profile "loop_a" {
+  inherits = ["loop_b"]
+}
+
+profile "loop_b" {
+  inherits = ["loop_a"]
+}
+
+profile "orphan" {
+  inherits = ["missing"]
+}
+`)
+
+	// The values are compared by their Go syntax, since numbers decoded from
+	// HCL have a different precision to those constructed by the test.
+	values := func(ovrds []*variables.Override) map[string]string {
+		out := make(map[string]string, len(ovrds))
+		for _, o := range ovrds {
+			out[o.Path.Join(pack.ID(o.Name)).String()] = o.Value.GoString()
+		}
+		return out
+	}
+
+	t.Run("inherits in order", func(t *testing.T) {
+		ovrds, diags := DecodeProfile(testpack(), "profiles.hcl", src, "prod")
+		must.SliceEmpty(t, diags)
+		must.Eq(t, map[string]string{
+			"example.region":    cty.StringVal("us").GoString(),
+			"example.count":     cty.NumberIntVal(5).GoString(),
+			"example.dep.image": cty.StringVal("web:1").GoString(),
+		}, values(ovrds))
+	})
+
+	t.Run("without inheritance", func(t *testing.T) {
+		ovrds, diags := DecodeProfile(testpack(), "profiles.hcl", src, "large")
+		must.SliceEmpty(t, diags)
+		must.Eq(t, map[string]string{"example.count": cty.NumberIntVal(5).GoString()}, values(ovrds))
+	})
+
+	t.Run("errors on unknown profile", func(t *testing.T) {
+		_, diags := DecodeProfile(testpack(), "profiles.hcl", src, "staging")
+		must.True(t, diags.HasErrors())
+		must.StrContains(t, diags.Error(), "Defined profiles: base, large, loop_a, loop_b, orphan, prod.")
+	})
+
+	t.Run("errors on unknown parent", func(t *testing.T) {
+		_, diags := DecodeProfile(testpack(), "profiles.hcl", src, "orphan")
+		must.True(t, diags.HasErrors())
+		must.StrContains(t, diags.Error(), `The profile "orphan" inherits the profile "missing", which is not defined.`)
+	})
+
+	t.Run("errors on cycle", func(t *testing.T) {
+		_, diags := DecodeProfile(testpack(), "profiles.hcl", src, "loop_a")
+		must.True(t, diags.HasErrors())
+		must.StrContains(t, diags.Error(), "loop_a -> loop_b -> loop_a")
+	})
+
+	t.Run("errors on duplicate profile", func(t *testing.T) {
+		_, diags := DecodeProfile(testpack(), "profiles.hcl", []byte(`
+profile "base" {}
+profile "base" {}
+`), "base")
+		must.True(t, diags.HasErrors())
+		must.StrContains(t, diags.Error(), "Duplicate profile")
+	})
+}
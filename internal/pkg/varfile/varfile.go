@@ -154,15 +154,30 @@ func decode(root *pack.Pack, filename string, src []byte, ctx *hcl.EvalContext,
 
 	// Because we wrap the user provided values in a HCL map or JSON object both
 	// named `v`, we can use JustAttributes to parse the configuration, and obtain
-	// the user-supplied content getting the `v` attribute's Expression and using
-	// ExprMap to convert it into a []hcl.KeyValuePair
+	// the user-supplied content getting the `v` attribute's Expression.
 	attrs, diags := file.Body.JustAttributes()
-	expr := attrs["v"].Expr
-	em, emDiags := hcl.ExprMap(expr)
+	vals, oDiags := decodeOverrides(root, attrs["v"].Expr, fixupRange)
+	diags = diags.Extend(oDiags)
+
+	if len(vals) > 0 {
+		// What is this doing?
+		(*target)[pack.ID(filename)] = vals
+	}
+	return fm, diags
+}
+
+// decodeOverrides converts the map or object expression into overrides of the
+// variables of the root pack and its dependencies. The keys name the
+// variables, prefixed with the path to the dependency for those which do not
+// belong to the root pack. The fixup func, if set, adjusts the range of each
+// override to account for any changes made to the source.
+func decodeOverrides(root *pack.Pack, expr hcl.Expression, fixup func(*hcl.Range)) ([]*variables.Override, hcl.Diagnostics) {
+	// Use ExprMap to convert the expression into a []hcl.KeyValuePair
+	em, diags := hcl.ExprMap(expr)
 
 	// Any diags set at this point still aren't partially usable, so return them.
-	if emDiags.HasErrors() {
-		return fm, diags.Extend(emDiags)
+	if diags.HasErrors() {
+		return nil, diags
 	}
 
 	vals := make([]*variables.Override, 0, len(em))
@@ -208,7 +223,9 @@ func decode(root *pack.Pack, filename string, src []byte, ctx *hcl.EvalContext,
 
 		// Create a range that represents the sum of the key and value ranges.
 		oRange := hcl.RangeBetween(kv.Key.Range(), kv.Value.Range())
-		fixupRange(&oRange)
+		if fixup != nil {
+			fixup(&oRange)
+		}
 
 		var path pack.ID
 		var name variables.ID
@@ -241,11 +258,7 @@ func decode(root *pack.Pack, filename string, src []byte, ctx *hcl.EvalContext,
 		vals = append(vals, &val)
 	}
 
-	if len(vals) > 0 {
-		// What is this doing?
-		(*target)[pack.ID(filename)] = vals
-	}
-	return fm, diags
+	return vals, diags
 }
 
 // wrapHCLBytes takes simple key-value structured HCL and converts them to HCL
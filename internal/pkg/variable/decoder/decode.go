@@ -74,8 +74,9 @@ func DecodeVariableBlock(block *hcl.Block) (*variables.Variable, hcl.Diagnostics
 	// A variable doesn't need to declare a default. If it does, process this
 	// and store it, along with any processing errors.
 	if attr, exists := content.Attributes[schema.VariableAttributeDefault]; exists {
-		val, valDiags := attr.Expr.Value(nil)
+		val, valDiags := attr.Expr.Value(defaultEvalContext())
 		diags = packdiags.SafeDiagnosticsExtend(diags, valDiags)
+		v.DynamicDefault = usesDynamicFunction(attr.Expr)
 
 		// Attempt to convert the default to the variable's declared type
 		// to produce an informative error if they are not compatible.
@@ -88,6 +89,73 @@ func DecodeVariableBlock(block *hcl.Block) (*variables.Variable, hcl.Diagnostics
 		v.Value = val
 	}
 
+	// A variable doesn't need to declare a default provider. If it does, the
+	// reference is stored so the default can be resolved when parsing.
+	if attr, exists := content.Attributes[schema.VariableAttributeDefaultFrom]; exists {
+		val, fromDiags := attr.Expr.Value(nil)
+		diags = packdiags.SafeDiagnosticsExtend(diags, fromDiags)
+
+		if val.Type() == cty.String && !val.IsNull() {
+			v.DefaultFrom = val.AsString()
+		} else {
+			diags = packdiags.SafeDiagnosticsAppend(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid type for default_from",
+				Detail: fmt.Sprintf("The default_from attribute is expected to be of type string, got %s",
+					val.Type().FriendlyName()),
+				Subject: attr.Range.Ptr(),
+			})
+		}
+	}
+
+	// A variable doesn't need to be deprecated. If it is, the message is
+	// stored so a warning can be emitted when the variable is set.
+	if attr, exists := content.Attributes[schema.VariableAttributeDeprecated]; exists {
+		val, depDiags := attr.Expr.Value(nil)
+		diags = packdiags.SafeDiagnosticsExtend(diags, depDiags)
+
+		if val.Type() == cty.String && !val.IsNull() {
+			v.Deprecated = val.AsString()
+		} else {
+			diags = packdiags.SafeDiagnosticsAppend(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid type for deprecated",
+				Detail: fmt.Sprintf("The deprecated attribute is expected to be of type string, got %s",
+					val.Type().FriendlyName()),
+				Subject: attr.Range.Ptr(),
+			})
+		}
+	}
+
+	// A variable doesn't need to be in a group. If it is, the group is used
+	// to filter and section the variables output by the info command.
+	if attr, exists := content.Attributes[schema.VariableAttributeGroup]; exists {
+		val, groupDiags := attr.Expr.Value(nil)
+		diags = packdiags.SafeDiagnosticsExtend(diags, groupDiags)
+
+		if val.Type() == cty.String && !val.IsNull() {
+			v.Group = val.AsString()
+		} else {
+			diags = packdiags.SafeDiagnosticsAppend(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid type for group",
+				Detail: fmt.Sprintf("The group attribute is expected to be of type string, got %s",
+					val.Type().FriendlyName()),
+				Subject: attr.Range.Ptr(),
+			})
+		}
+	}
+
+	// A variable doesn't need to declare validations. If it does, the
+	// conditions are stored so they can be evaluated against its value.
+	for _, block := range content.Blocks.OfType(schema.VariableBlockValidation) {
+		validation, valDiags := decodeValidationBlock(v.Name, block)
+		diags = packdiags.SafeDiagnosticsExtend(diags, valDiags)
+		if validation != nil {
+			v.Validations = append(v.Validations, validation)
+		}
+	}
+
 	if diags.HasErrors() {
 		return nil, diags
 	}
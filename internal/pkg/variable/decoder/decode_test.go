@@ -5,6 +5,7 @@ package decoder
 
 import (
 	"testing"
+	"time"
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/hashicorp/hcl/v2"
@@ -176,6 +177,46 @@ variable "object" {
 			}(),
 			expectDiags: hcl.Diagnostics{},
 		},
+		{
+			name: "passes/on deprecated",
+			input: testGetHCLBlock(t, testLoadPackFile(t, []byte(`
+variable "instances" {
+	deprecated = "use count instead"
+}`))),
+			expectOut: func() *variables.Variable {
+				out := variables.Variable{
+					Name:       "instances",
+					Deprecated: "use count instead",
+					DeclRange: hcl.Range{
+						Filename: "/fake/test/path",
+						Start:    hcl.Pos{Line: 2, Column: 1, Byte: 1},
+						End:      hcl.Pos{Line: 2, Column: 21, Byte: 21},
+					},
+				}
+				return &out
+			}(),
+			expectDiags: hcl.Diagnostics{},
+		},
+		{
+			name: "passes/on group",
+			input: testGetHCLBlock(t, testLoadPackFile(t, []byte(`
+variable "port" {
+	group = "networking"
+}`))),
+			expectOut: func() *variables.Variable {
+				out := variables.Variable{
+					Name:  "port",
+					Group: "networking",
+					DeclRange: hcl.Range{
+						Filename: "/fake/test/path",
+						Start:    hcl.Pos{Line: 2, Column: 1, Byte: 1},
+						End:      hcl.Pos{Line: 2, Column: 16, Byte: 16},
+					},
+				}
+				return &out
+			}(),
+			expectDiags: hcl.Diagnostics{},
+		},
 		{
 			name:      "fails/on bad content",
 			input:     testGetHCLBlock(t, testLoadPackFile(t, []byte(badContent))),
@@ -315,6 +356,63 @@ variable "bad_object" {
 	}
 }
 
+func TestDecoder_DefaultFunctions(t *testing.T) {
+	t.Setenv("NOMAD_PACK_TEST_REGION", "eu-west-1")
+
+	testCases := []struct {
+		name          string
+		input         string
+		expectDefault cty.Value
+		expectDynamic bool
+		expectErr     string
+	}{
+		{
+			name:          "pure function",
+			input:         `variable "v" { default = upper(format("%s-%d", "web", 2)) }`,
+			expectDefault: cty.StringVal("WEB-2"),
+		},
+		{
+			name:          "env",
+			input:         `variable "v" { default = env("NOMAD_PACK_TEST_REGION") }`,
+			expectDefault: cty.StringVal("eu-west-1"),
+			expectDynamic: true,
+		},
+		{
+			name:          "nested env",
+			input:         `variable "v" { default = ["dc-${env("NOMAD_PACK_TEST_REGION")}"] }`,
+			expectDefault: cty.TupleVal([]cty.Value{cty.StringVal("dc-eu-west-1")}),
+			expectDynamic: true,
+		},
+		{
+			name:      "side effecting function",
+			input:     `variable "v" { default = file("/etc/passwd") }`,
+			expectErr: "Call to unknown function",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, diags := DecodeVariableBlock(testGetHCLBlock(t, testLoadPackFile(t, []byte(tc.input))))
+			if tc.expectErr != "" {
+				must.True(t, diags.HasErrors())
+				must.StrContains(t, diags.Error(), tc.expectErr)
+				return
+			}
+			must.False(t, diags.HasErrors(), must.Sprint(diags.Error()))
+			must.True(t, tc.expectDefault.RawEquals(out.Default), must.Sprintf("got %#v", out.Default))
+			must.Eq(t, tc.expectDynamic, out.DynamicDefault)
+		})
+	}
+
+	t.Run("timestamp", func(t *testing.T) {
+		out, diags := DecodeVariableBlock(testGetHCLBlock(t, testLoadPackFile(t, []byte(`variable "v" { default = timestamp() }`))))
+		must.False(t, diags.HasErrors(), must.Sprint(diags.Error()))
+		must.True(t, out.DynamicDefault)
+
+		_, err := time.Parse(time.RFC3339, out.Default.AsString())
+		must.NoError(t, err)
+	})
+}
+
 const goodMinimalVariableHCL = `variable "good" {}`
 
 const goodCompleteVariableHCL = `variable "example" {
@@ -335,6 +433,111 @@ const badDescriptionType = `variable "bad" {
 
 // loadPackFile takes a pack.File and parses this using a hclparse.Parser. The
 // file can be either HCL and JSON format.
+func TestDecoder_Validations(t *testing.T) {
+	testCases := []struct {
+		name       string
+		input      string
+		value      cty.Value
+		expectErrs []string
+	}{
+		{
+			name: "passes",
+			input: `variable "port" {
+  validation {
+    condition     = var.port > 0 && var.port < 65536
+    error_message = "port must be between 1 and 65535"
+  }
+}`,
+			value: cty.NumberIntVal(8080),
+		},
+		{
+			name: "fails condition",
+			input: `variable "port" {
+  validation {
+    condition     = var.port > 0
+    error_message = "port must be positive"
+  }
+}`,
+			value:      cty.NumberIntVal(-1),
+			expectErrs: []string{`The value of the variable "port" is invalid: port must be positive`},
+		},
+		{
+			name: "fails each condition",
+			input: `variable "name" {
+  validation {
+    condition     = contains(["web", "api"], var.name)
+    error_message = "name must be web or api"
+  }
+  validation {
+    condition     = can(regex("^[a-z]+$", var.name))
+    error_message = "name must be lowercase"
+  }
+}`,
+			value:      cty.StringVal("DB"),
+			expectErrs: []string{"name must be web or api", "name must be lowercase"},
+		},
+		{
+			name: "non-boolean condition",
+			input: `variable "name" {
+  validation {
+    condition     = var.name
+    error_message = "name is invalid"
+  }
+}`,
+			value:      cty.StringVal("web"),
+			expectErrs: []string{"must return true or false"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			v, diags := DecodeVariableBlock(testGetHCLBlock(t, testLoadPackFile(t, []byte(tc.input))))
+			must.False(t, diags.HasErrors(), must.Sprint(diags.Error()))
+
+			diags = CheckValidations(v, tc.value)
+			must.Len(t, len(tc.expectErrs), diags)
+			for i, expectErr := range tc.expectErrs {
+				must.StrContains(t, diags[i].Detail, expectErr)
+			}
+		})
+	}
+
+	t.Run("fails on references to other variables", func(t *testing.T) {
+		_, diags := DecodeVariableBlock(testGetHCLBlock(t, testLoadPackFile(t, []byte(`variable "port" {
+  validation {
+    condition     = var.port > var.min_port
+    error_message = "port is too low"
+  }
+}`))))
+		must.True(t, diags.HasErrors())
+		must.StrContains(t, diags.Error(), `can only refer to the variable itself, using var.port`)
+	})
+
+	t.Run("sets condition sources", func(t *testing.T) {
+		src := []byte(`variable "port" {
+  validation {
+    condition = (var.port > 0 &&
+      var.port < 65536)
+    error_message = "port must be between 1 and 65535"
+  }
+}`)
+		v, diags := DecodeVariableBlock(testGetHCLBlock(t, testLoadPackFile(t, src)))
+		must.False(t, diags.HasErrors(), must.Sprint(diags.Error()))
+
+		SetConditionSources(v, src)
+		must.Eq(t, "(var.port > 0 && var.port < 65536)", v.Validations[0].ConditionSource)
+	})
+
+	t.Run("fails without an error message", func(t *testing.T) {
+		_, diags := DecodeVariableBlock(testGetHCLBlock(t, testLoadPackFile(t, []byte(`variable "port" {
+  validation {
+    condition = var.port > 0
+  }
+}`))))
+		must.True(t, diags.HasErrors())
+		must.StrContains(t, diags.Error(), `The argument "error_message" is required`)
+	})
+}
+
 func testLoadPackFile(t *testing.T, b []byte) hcl.Body {
 	t.Helper()
 
@@ -0,0 +1,129 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package decoder
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+
+	"github.com/hashicorp/nomad-pack/internal/pkg/errors/packdiags"
+	"github.com/hashicorp/nomad-pack/internal/pkg/variable/schema"
+	"github.com/hashicorp/nomad-pack/sdk/pack/variables"
+)
+
+// decodeValidationBlock parses a validation block of the named variable. The
+// condition may only reference the variable itself, as var.<name>, so that it
+// can be evaluated without the values of any other variable.
+func decodeValidationBlock(name variables.ID, block *hcl.Block) (*variables.Validation, hcl.Diagnostics) {
+	content, diags := block.Body.Content(schema.VariableValidationSchema)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	v := &variables.Validation{
+		Condition: content.Attributes[schema.ValidationAttributeCondition].Expr,
+		DeclRange: block.DefRange,
+	}
+
+	for _, traversal := range v.Condition.Variables() {
+		if validationReference(traversal) == name.String() {
+			continue
+		}
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid reference in variable validation",
+			Detail: fmt.Sprintf("The condition for variable %q can only refer to the variable itself, using var.%s.",
+				name, name),
+			Subject: traversal.SourceRange().Ptr(),
+		})
+	}
+
+	attr := content.Attributes[schema.ValidationAttributeErrorMessage]
+	val, msgDiags := attr.Expr.Value(nil)
+	diags = diags.Extend(msgDiags)
+	if !msgDiags.HasErrors() {
+		if val.Type() == cty.String && !val.IsNull() {
+			v.ErrorMessage = val.AsString()
+		} else {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid type for error_message",
+				Detail: fmt.Sprintf("The error_message attribute is expected to be of type string, got %s",
+					val.Type().FriendlyName()),
+				Subject: attr.Range.Ptr(),
+			})
+		}
+	}
+
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	return v, diags
+}
+
+// SetConditionSources sets the condition source of each validation of the
+// variable, using src, which is the content of the file the variable was
+// decoded from. Whitespace within the condition is collapsed, so conditions
+// spanning multiple lines can be output on a single line.
+func SetConditionSources(v *variables.Variable, src []byte) {
+	for _, validation := range v.Validations {
+		cond := validation.Condition.Range().SliceBytes(src)
+		validation.ConditionSource = strings.Join(strings.Fields(string(cond)), " ")
+	}
+}
+
+// validationReference returns the name of the variable referenced by the
+// traversal, when it is of the form var.<name>.
+func validationReference(traversal hcl.Traversal) string {
+	if len(traversal) < 2 || traversal.RootName() != "var" {
+		return ""
+	}
+	if attr, ok := traversal[1].(hcl.TraverseAttr); ok {
+		return attr.Name
+	}
+	return ""
+}
+
+// CheckValidations evaluates the validation conditions of the variable with
+// val as its value, returning an error for each condition which is not met.
+// The conditions may use the same functions as variable defaults.
+func CheckValidations(v *variables.Variable, val cty.Value) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	if len(v.Validations) == 0 {
+		return diags
+	}
+
+	ctx := defaultEvalContext()
+	ctx.Variables = map[string]cty.Value{
+		"var": cty.ObjectVal(map[string]cty.Value{v.Name.String(): val}),
+	}
+
+	for _, validation := range v.Validations {
+		result, condDiags := validation.Condition.Value(ctx)
+		diags = diags.Extend(condDiags)
+		if condDiags.HasErrors() {
+			continue
+		}
+
+		result, err := convert.Convert(result, cty.Bool)
+		if err != nil || result.IsNull() || !result.IsKnown() {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid validation result",
+				Detail:   fmt.Sprintf("The condition for variable %q must return true or false.", v.Name),
+				Subject:  validation.Condition.Range().Ptr(),
+			})
+			continue
+		}
+
+		if result.False() {
+			diags = diags.Append(packdiags.DiagFailedVariableValidation(v.Name.String(), validation.ErrorMessage, validation.DeclRange.Ptr()))
+		}
+	}
+	return diags
+}
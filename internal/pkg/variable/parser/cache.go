@@ -0,0 +1,293 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package parser
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+
+	"github.com/hashicorp/nomad-pack/internal/pkg/variable/parser/config"
+	"github.com/hashicorp/nomad-pack/internal/pkg/version"
+	"github.com/hashicorp/nomad-pack/sdk/pack"
+	"github.com/hashicorp/nomad-pack/sdk/pack/variables"
+)
+
+// CachingParser wraps a Parser and stores successful V2 parse results on disk,
+// so that subsequent runs with identical inputs can skip parsing entirely.
+// Any failure to read or write the cache falls back to the wrapped parser.
+type CachingParser struct {
+	parser Parser
+	path   string
+}
+
+// NewCachingParser returns a Parser which caches the results of p within dir.
+// The key must uniquely identify the parser inputs and is usually generated
+// using ParseCacheKey.
+func NewCachingParser(p Parser, dir, key string) *CachingParser {
+	return &CachingParser{
+		parser: p,
+		path:   filepath.Join(dir, key+".json"),
+	}
+}
+
+func (c *CachingParser) Parse() (*ParsedVariables, hcl.Diagnostics) {
+	if pv, err := c.read(); err == nil {
+		return pv, deprecationWarnings(pv)
+	}
+
+	pv, diags := c.parser.Parse()
+	if diags.HasErrors() || pv == nil || !pv.IsV2() || usesExternalDefaults(pv) {
+		return pv, diags
+	}
+
+	// Writing the cache is best effort; a failure only means the next run
+	// parses the variables again.
+	_ = c.write(pv)
+	return pv, diags
+}
+
+// usesExternalDefaults returns whether any variable references a default
+// provider, has a dynamic default, or has a value resolved from a reference.
+// The values of providers and resolvers, and the time or environment read by
+// dynamic defaults, are external to the pack, so they are not covered by the
+// cache key and the results must not be cached. This also ensures sensitive
+// values are never written to the cache.
+func usesExternalDefaults(pv *ParsedVariables) bool {
+	for _, vars := range pv.GetVars() {
+		for _, v := range vars {
+			if v.DefaultFrom != "" || v.DynamicDefault || v.ValueFrom != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ParseCacheKey generates the cache key for the passed parser configuration.
+// The key covers the pack reference, the root variable files, the contents of
+// any variable and profile files, the selected profile, the variable flags and
+// environment variables, whether they are resolved as references, and the
+// version of nomad-pack, so that a change to any of these results in a miss.
+func ParseCacheKey(packRef string, cfg *config.ParserConfig) (string, error) {
+	h := sha256.New()
+	write := func(parts ...string) {
+		for _, part := range parts {
+			fmt.Fprintf(h, "%d:%s;", len(part), part)
+		}
+	}
+
+	write("version", version.HumanVersion(), "pack", packRef)
+
+	ids := make([]string, 0, len(cfg.RootVariableFiles))
+	for id := range cfg.RootVariableFiles {
+		ids = append(ids, string(id))
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		f := cfg.RootVariableFiles[pack.ID(id)]
+		write("root", id, f.Path, string(f.Content))
+	}
+
+	files := append([]string{}, cfg.FileOverrides...)
+	sort.Strings(files)
+	for _, file := range files {
+		src, err := os.ReadFile(file)
+		if err != nil {
+			return "", err
+		}
+		write("file", file, string(src))
+	}
+
+	if cfg.Profile != "" {
+		src, err := os.ReadFile(cfg.ProfileFile)
+		if err != nil {
+			return "", err
+		}
+		write("profile", cfg.Profile, cfg.ProfileFile, string(src))
+	}
+
+	for _, m := range []struct {
+		name string
+		vars map[string]string
+	}{{"env", cfg.EnvOverrides}, {"flag", cfg.FlagOverrides}, {"patch", cfg.PatchOverrides}} {
+		keys := make([]string, 0, len(m.vars))
+		for k := range m.vars {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			write(m.name, k, m.vars[k])
+		}
+	}
+
+	write("ignore-missing", fmt.Sprint(cfg.IgnoreMissingVars))
+	write("resolve-refs", fmt.Sprint(cfg.ResolveValueRefs))
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// cachedVariable is the on-disk representation of a variables.Variable.
+type cachedVariable struct {
+	Name        string          `json:"name"`
+	Description *string         `json:"description,omitempty"`
+	Type        json.RawMessage `json:"type,omitempty"`
+	Default     json.RawMessage `json:"default,omitempty"`
+	Value       json.RawMessage `json:"value"`
+	ValueSource string          `json:"value_source,omitempty"`
+	Deprecated  string          `json:"deprecated,omitempty"`
+	Group       string          `json:"group,omitempty"`
+	DeclRange   hcl.Range       `json:"decl_range"`
+}
+
+func (c *CachingParser) read() (*ParsedVariables, error) {
+	src, err := os.ReadFile(c.path)
+	if err != nil {
+		return nil, err
+	}
+
+	var cached map[pack.ID][]cachedVariable
+	if err := json.Unmarshal(src, &cached); err != nil {
+		return nil, err
+	}
+
+	out := make(map[pack.ID]map[variables.ID]*variables.Variable, len(cached))
+	for pID, vars := range cached {
+		out[pID] = make(map[variables.ID]*variables.Variable, len(vars))
+		for _, cv := range vars {
+			v, err := cv.decode()
+			if err != nil {
+				return nil, err
+			}
+			out[pID][v.Name] = v
+		}
+	}
+
+	pv := new(ParsedVariables)
+	if err := pv.LoadV2Result(out); err != nil {
+		return nil, err
+	}
+	return pv, nil
+}
+
+func (c *CachingParser) write(pv *ParsedVariables) error {
+	cached := make(map[pack.ID][]cachedVariable, len(pv.v2Vars))
+	for pID, vars := range pv.v2Vars {
+		for _, v := range vars {
+			cv, err := encodeCachedVariable(v)
+			if err != nil {
+				return err
+			}
+			cached[pID] = append(cached[pID], cv)
+		}
+	}
+
+	out, err := json.Marshal(cached)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so a concurrent reader never sees a
+	// partially written cache entry.
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, out, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, c.path)
+}
+
+func encodeCachedVariable(v *variables.Variable) (cachedVariable, error) {
+	cv := cachedVariable{
+		Name:        string(v.Name),
+		ValueSource: v.ValueSource,
+		Deprecated:  v.Deprecated,
+		Group:       v.Group,
+		DeclRange:   v.DeclRange,
+	}
+
+	if v.Description != "" {
+		cv.Description = &v.Description
+	}
+
+	var err error
+	if v.Type != cty.NilType {
+		if cv.Type, err = ctyjson.MarshalType(v.Type); err != nil {
+			return cv, err
+		}
+	}
+	if cv.Default, err = marshalCachedValue(v.Default); err != nil {
+		return cv, err
+	}
+	if cv.Value, err = marshalCachedValue(v.Value); err != nil {
+		return cv, err
+	}
+	return cv, nil
+}
+
+func (cv cachedVariable) decode() (*variables.Variable, error) {
+	v := &variables.Variable{
+		Name:        variables.ID(cv.Name),
+		ValueSource: cv.ValueSource,
+		Deprecated:  cv.Deprecated,
+		Group:       cv.Group,
+		DeclRange:   cv.DeclRange,
+	}
+
+	if cv.Description != nil {
+		v.SetDescription(*cv.Description)
+	}
+	if len(cv.Type) > 0 {
+		t, err := ctyjson.UnmarshalType(cv.Type)
+		if err != nil {
+			return nil, err
+		}
+		v.SetType(t)
+	}
+	if len(cv.Default) > 0 {
+		d, err := unmarshalCachedValue(cv.Default)
+		if err != nil {
+			return nil, err
+		}
+		v.SetDefault(d)
+	}
+
+	val, err := unmarshalCachedValue(cv.Value)
+	if err != nil {
+		return nil, err
+	}
+	v.Value = val
+	return v, nil
+}
+
+// marshalCachedValue encodes a cty value along with its type, so that it can
+// be decoded without knowing the type in advance. Values which have not been
+// set are omitted.
+func marshalCachedValue(val cty.Value) (json.RawMessage, error) {
+	if val.Type() == cty.NilType {
+		return nil, nil
+	}
+	if !val.IsWhollyKnown() {
+		return nil, errors.New("cannot cache unknown value")
+	}
+	return ctyjson.Marshal(val, cty.DynamicPseudoType)
+}
+
+func unmarshalCachedValue(src json.RawMessage) (cty.Value, error) {
+	if len(src) == 0 {
+		return cty.NilVal, nil
+	}
+	return ctyjson.Unmarshal(src, cty.DynamicPseudoType)
+}
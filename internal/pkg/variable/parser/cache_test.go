@@ -0,0 +1,108 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package parser
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/nomad-pack/internal/pkg/variable/parser/config"
+	"github.com/shoenig/test/must"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// failingParser is a Parser which always errors, used to confirm results are
+// served from the cache.
+type failingParser struct{}
+
+func (failingParser) Parse() (*ParsedVariables, hcl.Diagnostics) {
+	return nil, hcl.Diagnostics{{Severity: hcl.DiagError, Summary: "parser called"}}
+}
+
+func TestCachingParser_Parse(t *testing.T) {
+	dir := t.TempDir()
+
+	p := NewTestInputParserV2(WithCliVar("input", "flag"))
+	p.rootVars["example"]["list"] = NewStringVariableV2("list", "", "root")
+	p.rootVars["example"]["list"].Type = cty.List(cty.String)
+	p.rootVars["example"]["list"].Value = cty.ListVal([]cty.Value{cty.StringVal("a")})
+	p.rootVars["example"]["list"].SetDescription("a list")
+
+	pv, diags := NewCachingParser(p, dir, "key").Parse()
+	must.SliceEmpty(t, diags)
+	must.Eq(t, "flag", pv.v2Vars["example"]["input"].Value.AsString())
+
+	cached, diags := NewCachingParser(failingParser{}, dir, "key").Parse()
+	must.SliceEmpty(t, diags)
+	must.True(t, cached.IsV2())
+
+	input := cached.v2Vars["example"]["input"]
+	must.Eq(t, "flag", input.Value.AsString())
+	must.Eq(t, "<value for var input from rootVars>", input.DeclRange.Filename)
+
+	list := cached.v2Vars["example"]["list"]
+	must.Eq(t, "a list", list.Description)
+	must.True(t, list.Type.Equals(cty.List(cty.String)))
+	must.True(t, list.Value.RawEquals(cty.ListVal([]cty.Value{cty.StringVal("a")})))
+
+	// A different key misses the cache and calls the wrapped parser.
+	_, diags = NewCachingParser(failingParser{}, dir, "other").Parse()
+	must.True(t, diags.HasErrors())
+}
+
+func TestParseCacheKey(t *testing.T) {
+	cfg := &config.ParserConfig{
+		FlagOverrides: map[string]string{"a": "1", "b": "2"},
+	}
+
+	key, err := ParseCacheKey("example@latest", cfg)
+	must.NoError(t, err)
+
+	same, err := ParseCacheKey("example@latest", &config.ParserConfig{
+		FlagOverrides: map[string]string{"b": "2", "a": "1"},
+	})
+	must.NoError(t, err)
+	must.Eq(t, key, same)
+
+	otherRef, err := ParseCacheKey("example@v1", cfg)
+	must.NoError(t, err)
+	must.NotEq(t, key, otherRef)
+
+	otherVar, err := ParseCacheKey("example@latest", &config.ParserConfig{
+		FlagOverrides: map[string]string{"a": "1", "b": "3"},
+	})
+	must.NoError(t, err)
+	must.NotEq(t, key, otherVar)
+
+	_, err = ParseCacheKey("example@latest", &config.ParserConfig{
+		FileOverrides: []string{"/does/not/exist.hcl"},
+	})
+	must.Error(t, err)
+}
+
+func TestCachingParser_SkipsDefaultProviders(t *testing.T) {
+	dir := t.TempDir()
+
+	p := NewTestInputParserV2()
+	p.rootVars["example"]["input"].DefaultFrom = "env:NOMAD_PACK_TEST_INPUT"
+
+	_, diags := NewCachingParser(p, dir, "key").Parse()
+	must.SliceEmpty(t, diags)
+
+	_, diags = NewCachingParser(failingParser{}, dir, "key").Parse()
+	must.True(t, diags.HasErrors())
+}
+
+func TestCachingParser_SkipsDynamicDefaults(t *testing.T) {
+	dir := t.TempDir()
+
+	p := NewTestInputParserV2()
+	p.rootVars["example"]["input"].DynamicDefault = true
+
+	_, diags := NewCachingParser(p, dir, "key").Parse()
+	must.SliceEmpty(t, diags)
+
+	_, diags = NewCachingParser(failingParser{}, dir, "key").Parse()
+	must.True(t, diags.HasErrors())
+}
@@ -9,6 +9,10 @@ import (
 
 type ParserVersion int
 
+// DefaultMaxVariableFileSize is the maximum size, in bytes, of the variable
+// files read by the parser when MaxVariableFileSize is not set.
+const DefaultMaxVariableFileSize int64 = 4 << 20
+
 const (
 	VUnknown ParserVersion = iota
 	V1
@@ -37,6 +41,15 @@ type ParserConfig struct {
 	// all sources. If the same key is supplied twice, the last wins.
 	EnvOverrides map[string]string
 
+	// Profile is the name of the profile, defined in ProfileFile, whose
+	// variable overrides are applied. Its overrides take precedence over
+	// EnvOverrides, and are replaced by FileOverrides and FlagOverrides. Used
+	// for ParserV2.
+	Profile string
+
+	// ProfileFile is the path to the file defining the profiles.
+	ProfileFile string
+
 	// FileOverrides is a list of files which contain variable overrides in the
 	// form key=value. The files will be stored before processing to ensure a
 	// consistent processing experience. Overrides here will replace any
@@ -47,6 +60,22 @@ type ParserConfig struct {
 	// all sources. If the same key is supplied twice, the last wins.
 	FlagOverrides map[string]string
 
+	// PatchOverrides are JSON Patch (RFC 6902) documents keyed by the name of
+	// the map or object variable they modify. They are applied once all other
+	// sources have been merged. Used for ParserV2.
+	PatchOverrides map[string]string
+
+	// ResolveValueRefs determines whether values of EnvOverrides and
+	// FlagOverrides in the form "<resolver>:<reference>", such as
+	// "vault:secret/data/app#password", are resolved using the referenced
+	// value resolver. Used for ParserV2.
+	ResolveValueRefs bool
+
+	// MaxVariableFileSize is the maximum size, in bytes, of the variable
+	// override and profile files. Larger files are rejected rather than read.
+	// If zero, DefaultMaxVariableFileSize is used.
+	MaxVariableFileSize int64
+
 	// IgnoreMissingVars determines whether we error or not on variable overrides
 	// that don't have corresponding vars in the pack.
 	IgnoreMissingVars bool
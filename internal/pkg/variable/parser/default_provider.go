@@ -0,0 +1,129 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package parser
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/hashicorp/nomad-pack/internal/pkg/errors/packdiags"
+	"github.com/hashicorp/nomad-pack/internal/pkg/variable/internal/hclhelp"
+	"github.com/hashicorp/nomad-pack/sdk/pack/variables"
+)
+
+// DefaultProvider resolves variable defaults from a source external to the
+// pack. Variables reference a provider using the default_from attribute in the
+// form "<provider>:<reference>", such as "env:REGION".
+type DefaultProvider interface {
+	// Resolve returns the raw value identified by ref. The value is parsed in
+	// the same way as a variable set from the environment. The returned bool
+	// is false when the value does not exist, in which case the default
+	// declared within the pack is used.
+	Resolve(ref string) (string, bool, error)
+}
+
+var (
+	defaultProvidersLock sync.RWMutex
+
+	// defaultProviders are the registered providers keyed by name. Only the
+	// providers which do not require network access are registered by
+	// default.
+	defaultProviders = map[string]DefaultProvider{
+		"env":  EnvDefaultProvider{},
+		"file": FileDefaultProvider{},
+	}
+)
+
+// RegisterDefaultProvider registers the provider using the name referenced by
+// the default_from attribute of variables, replacing any provider already
+// registered with the name. It allows additional providers, such as one which
+// reads defaults over HTTP, to be added.
+func RegisterDefaultProvider(name string, p DefaultProvider) {
+	defaultProvidersLock.Lock()
+	defer defaultProvidersLock.Unlock()
+	defaultProviders[name] = p
+}
+
+func getDefaultProvider(name string) (DefaultProvider, bool) {
+	defaultProvidersLock.RLock()
+	defer defaultProvidersLock.RUnlock()
+	p, ok := defaultProviders[name]
+	return p, ok
+}
+
+// EnvDefaultProvider resolves defaults from the environment variable with the
+// referenced name. Unset environment variables do not have a value.
+type EnvDefaultProvider struct{}
+
+func (EnvDefaultProvider) Resolve(ref string) (string, bool, error) {
+	val, ok := os.LookupEnv(ref)
+	return val, ok, nil
+}
+
+// FileDefaultProvider resolves defaults from the contents of the referenced
+// file, excluding any trailing newline. Relative paths are resolved from the
+// working directory.
+type FileDefaultProvider struct{}
+
+func (FileDefaultProvider) Resolve(ref string) (string, bool, error) {
+	content, err := os.ReadFile(ref)
+	if err != nil {
+		return "", false, err
+	}
+	return strings.TrimRight(string(content), "\r\n"), true, nil
+}
+
+// resolveProvidedDefault resolves the default of the variable from the
+// provider referenced by its DefaultFrom field. The resolved value is parsed
+// using the type of the variable, and replaces the default declared within the
+// pack.
+func resolveProvidedDefault(v *variables.Variable) hcl.Diagnostics {
+	name, ref, ok := strings.Cut(v.DefaultFrom, ":")
+	if !ok {
+		return hcl.Diagnostics{packdiags.DiagInvalidDefaultFrom(v.DefaultFrom,
+			fmt.Errorf("expected the form <provider>:<reference>"), v.DeclRange.Ptr())}
+	}
+
+	provider, ok := getDefaultProvider(name)
+	if !ok {
+		return hcl.Diagnostics{packdiags.DiagInvalidDefaultFrom(v.DefaultFrom,
+			fmt.Errorf("unknown default provider %q", name), v.DeclRange.Ptr())}
+	}
+
+	raw, found, err := provider.Resolve(ref)
+	if err != nil {
+		return hcl.Diagnostics{packdiags.DiagInvalidDefaultFrom(v.DefaultFrom, err, v.DeclRange.Ptr())}
+	}
+	if !found {
+		return nil
+	}
+
+	filename := fmt.Sprintf("<default for var %s from %s>", v.Name, v.DefaultFrom)
+	expr, diags := hclhelp.ExpressionFromVariableDefinition(filename, raw, v.Type)
+	if diags.HasErrors() {
+		return diags
+	}
+
+	val, diags := expr.Value(nil)
+	if diags.HasErrors() {
+		return diags
+	}
+
+	if v.Type != cty.NilType {
+		var diag *hcl.Diagnostic
+		val, diag = hclhelp.ConvertValUsingType(val, v.Type, v.DeclRange.Ptr())
+		if diag != nil {
+			return hcl.Diagnostics{diag}
+		}
+	}
+
+	v.SetProvidedDefault(val)
+	v.Value = val
+	return nil
+}
@@ -0,0 +1,118 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shoenig/test/must"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/hashicorp/nomad-pack/internal/pkg/variable/parser/config"
+	"github.com/hashicorp/nomad-pack/sdk/pack"
+	"github.com/hashicorp/nomad-pack/sdk/pack/variables"
+)
+
+// staticProvider is a DefaultProvider which resolves references from a map.
+type staticProvider map[string]string
+
+func (s staticProvider) Resolve(ref string) (string, bool, error) {
+	val, ok := s[ref]
+	return val, ok, nil
+}
+
+func TestParserV2_DefaultProviders(t *testing.T) {
+	dir := t.TempDir()
+	portFile := filepath.Join(dir, "port")
+	must.NoError(t, os.WriteFile(portFile, []byte("8080\n"), 0o644))
+
+	t.Setenv("NOMAD_PACK_TEST_REGION", "eu-west-1")
+	RegisterDefaultProvider("static", staticProvider{"dcs": `["dc1", "dc2"]`})
+	t.Cleanup(func() {
+		defaultProvidersLock.Lock()
+		defer defaultProvidersLock.Unlock()
+		delete(defaultProviders, "static")
+	})
+
+	newParser := func(src string) *ParserV2 {
+		return &ParserV2{
+			cfg: &config.ParserConfig{
+				ParentPack: testpack(),
+				RootVariableFiles: map[pack.ID]*pack.File{
+					"example": {Name: "variables.hcl", Path: "variables.hcl", Content: []byte(src)},
+				},
+			},
+			rootVars:         make(map[pack.ID]map[variables.ID]*variables.Variable),
+			envOverrideVars:  make(variables.PackIDKeyedVarMap),
+			fileOverrideVars: make(variables.PackIDKeyedVarMap),
+			flagOverrideVars: make(variables.PackIDKeyedVarMap),
+		}
+	}
+
+	t.Run("resolves defaults", func(t *testing.T) {
+		pv, diags := newParser(`
+variable "region" {
+  type         = string
+  default      = "us-east-1"
+  default_from = "env:NOMAD_PACK_TEST_REGION"
+}
+variable "zone" {
+  type         = string
+  default      = "a"
+  default_from = "env:NOMAD_PACK_TEST_UNSET"
+}
+variable "port" {
+  type         = number
+  default_from = "file:` + filepath.ToSlash(portFile) + `"
+}
+variable "datacenters" {
+  type         = list(string)
+  default_from = "static:dcs"
+}
+`).Parse()
+		must.SliceEmpty(t, diags)
+
+		vars := pv.v2Vars["example"]
+		must.Eq(t, "eu-west-1", vars["region"].Value.AsString())
+		must.True(t, vars["region"].HasProvidedDefault())
+		must.Eq(t, "a", vars["zone"].Value.AsString())
+		must.False(t, vars["zone"].HasProvidedDefault())
+		must.True(t, vars["port"].Value.RawEquals(cty.NumberIntVal(8080)))
+		must.True(t, vars["datacenters"].Value.RawEquals(cty.ListVal([]cty.Value{
+			cty.StringVal("dc1"), cty.StringVal("dc2"),
+		})))
+	})
+
+	t.Run("errors on unknown provider", func(t *testing.T) {
+		_, diags := newParser(`
+variable "region" {
+  default_from = "vault:secret/region"
+}
+`).Parse()
+		must.True(t, diags.HasErrors())
+		must.StrContains(t, diags.Error(), `unknown default provider "vault"`)
+	})
+
+	t.Run("errors on missing file", func(t *testing.T) {
+		_, diags := newParser(`
+variable "region" {
+  default_from = "file:` + filepath.ToSlash(filepath.Join(dir, "missing")) + `"
+}
+`).Parse()
+		must.True(t, diags.HasErrors())
+		must.StrContains(t, diags.Error(), "Failed to resolve default value")
+	})
+
+	t.Run("errors on value not matching type", func(t *testing.T) {
+		_, diags := newParser(`
+variable "port" {
+  type         = number
+  default_from = "env:NOMAD_PACK_TEST_REGION"
+}
+`).Parse()
+		must.True(t, diags.HasErrors())
+	})
+}
@@ -4,8 +4,14 @@
 package parser
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/nomad-pack/internal/pkg/variable/parser/config"
+	"github.com/spf13/afero"
 )
 
 type Parser interface {
@@ -18,3 +24,53 @@ func NewParser(cfg *config.ParserConfig) (Parser, error) {
 	}
 	return NewParserV2(cfg)
 }
+
+// maxVariableFileSize returns the maximum size of the variable files read by
+// the parser, which is the default when not configured.
+func maxVariableFileSize(cfg *config.ParserConfig) int64 {
+	if cfg.MaxVariableFileSize > 0 {
+		return cfg.MaxVariableFileSize
+	}
+	return config.DefaultMaxVariableFileSize
+}
+
+// checkVariableFile returns an error if the variable file is not a regular
+// file, such as a device or named pipe which could block or never end, or is
+// larger than maxSize bytes.
+func checkVariableFile(info os.FileInfo, maxSize int64) error {
+	if !info.Mode().IsRegular() {
+		return errors.New("not a regular file")
+	}
+	if info.Size() > maxSize {
+		return fmt.Errorf("size of %d bytes exceeds the maximum of %d bytes", info.Size(), maxSize)
+	}
+	return nil
+}
+
+// readVariableFile reads the variable file, checking it is a regular file no
+// larger than maxSize bytes. The read is limited to maxSize bytes, so a file
+// which grows after being checked cannot exhaust memory.
+func readVariableFile(fs afero.Afero, file string, maxSize int64) ([]byte, error) {
+	f, err := fs.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if err := checkVariableFile(info, maxSize); err != nil {
+		return nil, err
+	}
+
+	src, err := io.ReadAll(io.LimitReader(f, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(src)) > maxSize {
+		return nil, fmt.Errorf("size exceeds the maximum of %d bytes", maxSize)
+	}
+	return src, nil
+}
@@ -50,14 +50,21 @@ func NewParserV1(cfg *config.ParserConfig) (*ParserV1, error) {
 		return nil, errors.New("variable parser config requires ParentName to be set")
 	}
 
+	if cfg.Profile != "" {
+		return nil, errors.New("variable profiles are not supported by the v1 parser")
+	}
+
 	// Sort the file overrides to ensure variable merging is consistent on
 	// multiple passes.
 	sort.Strings(cfg.FileOverrides)
 	for _, file := range cfg.FileOverrides {
-		_, err := os.Stat(file)
+		info, err := os.Stat(file)
 		if err != nil {
 			return nil, fmt.Errorf("variable file %q not found", file)
 		}
+		if err := checkVariableFile(info, maxVariableFileSize(cfg)); err != nil {
+			return nil, fmt.Errorf("error loading variable file %q: %w", file, err)
+		}
 	}
 
 	return &ParserV1{
@@ -126,7 +133,7 @@ func (p *ParserV1) Parse() (*ParsedVariables, hcl.Diagnostics) {
 
 func (p *ParserV1) loadOverrideFile(file string) (hcl.Body, hcl.Diagnostics) {
 
-	src, err := p.fs.ReadFile(file)
+	src, err := readVariableFile(p.fs, file, maxVariableFileSize(p.cfg))
 	// FIXME - Workaround for ending heredoc with no linefeed.
 	// Variables files shouldn't care about the extra linefeed, but jamming one
 	// in all the time feels bad.
@@ -136,7 +143,7 @@ func (p *ParserV1) loadOverrideFile(file string) (hcl.Body, hcl.Diagnostics) {
 			{
 				Severity: hcl.DiagError,
 				Summary:  "Failed to read file",
-				Detail:   fmt.Sprintf("The file %q could not be read.", file),
+				Detail:   fmt.Sprintf("The file %q could not be read: %s.", file, err),
 			},
 		}
 	}
@@ -274,7 +281,7 @@ func (p *ParserV1) parseEnvVariable(name string, rawVal string) hcl.Diagnostics
 
 	// Generate a filename based on the CLI var, so we have some context for any
 	// HCL diagnostics.
-	fakeRange := hcl.Range{Filename: fmt.Sprintf("<value for var.%s from environment>", name)}
+	fakeRange := hcl.Range{Filename: fmt.Sprintf("<value for var.%s from environment variable %s%s>", name, VarEnvPrefix, name)}
 
 	// If the variable has not been configured in the root then ignore it. This
 	// is a departure from the way in which flags and var-files are handled.
@@ -403,6 +410,9 @@ func (p *ParserV1) parseRootFiles() hcl.Diagnostics {
 
 		rootVars, parseDiags := p.parseRootBodyContent(content)
 		diags = packdiags.SafeDiagnosticsExtend(diags, parseDiags)
+		for _, v := range rootVars {
+			decoder.SetConditionSources(v, file.Content)
+		}
 
 		// If we don't have any errors processing the file, and it's content,
 		// add an entry.
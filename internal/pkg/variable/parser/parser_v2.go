@@ -6,13 +6,18 @@ package parser
 import (
 	"errors"
 	"fmt"
+	"maps"
 	"os"
+	"slices"
 	"sort"
 	"strings"
 
 	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
 	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/hashicorp/nomad-pack/internal/pkg/errors/packdiags"
+	"github.com/hashicorp/nomad-pack/internal/pkg/helper/jsonpatch"
 	"github.com/hashicorp/nomad-pack/internal/pkg/varfile"
 	"github.com/hashicorp/nomad-pack/internal/pkg/variable/decoder"
 	"github.com/hashicorp/nomad-pack/internal/pkg/variable/envloader"
@@ -23,6 +28,8 @@ import (
 	"github.com/hashicorp/nomad-pack/sdk/pack/variables"
 	"github.com/spf13/afero"
 	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
 )
 
 type ParserV2 struct {
@@ -34,11 +41,37 @@ type ParserV2 struct {
 	// the second is by the variable name.
 	rootVars map[pack.ID]map[variables.ID]*variables.Variable
 
-	// envOverrideVars, fileOverrideVars, cliOverrideVars are the override
-	// variables. The maps are keyed by the pack name they are associated to.
-	envOverrideVars  variables.PackIDKeyedVarMap
-	fileOverrideVars variables.PackIDKeyedVarMap
-	flagOverrideVars variables.PackIDKeyedVarMap
+	// envOverrideVars, profileOverrideVars, fileOverrideVars, and
+	// flagOverrideVars are the override variables. The maps are keyed by the
+	// pack name they are associated to.
+	envOverrideVars     variables.PackIDKeyedVarMap
+	profileOverrideVars variables.PackIDKeyedVarMap
+	fileOverrideVars    variables.PackIDKeyedVarMap
+	flagOverrideVars    variables.PackIDKeyedVarMap
+
+	// flagMapEntries are the individual map entries set by dotted CLI
+	// variables, such as labels.env=prod. They are keyed by the pack and
+	// variable name and merged into the variable once all other overrides
+	// have been applied.
+	flagMapEntries map[pack.ID]map[variables.ID][]*mapEntry
+}
+
+// The sources recorded as the ValueSource of overridden variables. Variables
+// overridden by a file record the path of the file, and those overridden by a
+// profile record its name.
+const (
+	sourceEnv     = "environment"
+	sourceProfile = "profile"
+	sourceFile    = "var-file"
+	sourceFlag    = "--var"
+	sourcePatch   = "--var-patch"
+)
+
+// mapEntry is a single entry of a map or object variable set from the CLI.
+type mapEntry struct {
+	key   string
+	value cty.Value
+	rng   hcl.Range
 }
 
 func NewParserV2(cfg *config.ParserConfig) (*ParserV2, error) {
@@ -55,21 +88,35 @@ func NewParserV2(cfg *config.ParserConfig) (*ParserV2, error) {
 	// multiple passes.
 	sort.Strings(cfg.FileOverrides)
 	for _, file := range cfg.FileOverrides {
-		_, err := os.Stat(file)
+		info, err := os.Stat(file)
 		if err != nil {
 			return nil, fmt.Errorf("error loading variable file %q: %w", file, err)
 		}
+		if err := checkVariableFile(info, maxVariableFileSize(cfg)); err != nil {
+			return nil, fmt.Errorf("error loading variable file %q: %w", file, err)
+		}
+	}
+
+	if cfg.Profile != "" {
+		info, err := os.Stat(cfg.ProfileFile)
+		if err != nil {
+			return nil, fmt.Errorf("error loading profile file %q: %w", cfg.ProfileFile, err)
+		}
+		if err := checkVariableFile(info, maxVariableFileSize(cfg)); err != nil {
+			return nil, fmt.Errorf("error loading profile file %q: %w", cfg.ProfileFile, err)
+		}
 	}
 
 	return &ParserV2{
 		fs: afero.Afero{
 			Fs: afero.OsFs{},
 		},
-		cfg:              cfg,
-		rootVars:         make(map[pack.ID]map[variables.ID]*variables.Variable),
-		envOverrideVars:  make(variables.PackIDKeyedVarMap),
-		fileOverrideVars: make(variables.PackIDKeyedVarMap),
-		flagOverrideVars: make(variables.PackIDKeyedVarMap),
+		cfg:                 cfg,
+		rootVars:            make(map[pack.ID]map[variables.ID]*variables.Variable),
+		envOverrideVars:     make(variables.PackIDKeyedVarMap),
+		profileOverrideVars: make(variables.PackIDKeyedVarMap),
+		fileOverrideVars:    make(variables.PackIDKeyedVarMap),
+		flagOverrideVars:    make(variables.PackIDKeyedVarMap),
 	}, nil
 }
 
@@ -82,12 +129,16 @@ func (p *ParserV2) Parse() (*ParsedVariables, hcl.Diagnostics) {
 		return nil, diags
 	}
 
-	// Parse env, file, and CLI overrides.
+	// Parse env, profile, file, and CLI overrides.
 	for k, v := range p.cfg.EnvOverrides {
 		envOverrideDiags := p.parseEnvVariable(k, v)
 		diags = packdiags.SafeDiagnosticsExtend(diags, envOverrideDiags)
 	}
 
+	if p.cfg.Profile != "" {
+		diags = packdiags.SafeDiagnosticsExtend(diags, p.parseProfile())
+	}
+
 	for _, fileOverride := range p.cfg.FileOverrides {
 		_, fileOverrideDiags := p.newParseOverridesFile(fileOverride)
 		diags = packdiags.SafeDiagnosticsExtend(diags, fileOverrideDiags)
@@ -104,8 +155,16 @@ func (p *ParserV2) Parse() (*ParsedVariables, hcl.Diagnostics) {
 
 	// Iterate all our override variables and merge these into our root
 	// variables with the CLI taking highest priority.
-	for _, override := range []variables.PackIDKeyedVarMap{p.envOverrideVars, p.fileOverrideVars, p.flagOverrideVars} {
-		for packName, variables := range override {
+	for _, override := range []struct {
+		vars   variables.PackIDKeyedVarMap
+		source string
+	}{
+		{vars: p.envOverrideVars, source: sourceEnv},
+		{vars: p.profileOverrideVars, source: sourceProfile},
+		{vars: p.fileOverrideVars, source: sourceFile},
+		{vars: p.flagOverrideVars, source: sourceFlag},
+	} {
+		for packName, variables := range override.vars {
 			for _, v := range variables {
 				existing, exists := p.rootVars[packName][v.Name]
 				if !exists {
@@ -116,23 +175,88 @@ func (p *ParserV2) Parse() (*ParsedVariables, hcl.Diagnostics) {
 				}
 				if mergeDiags := existing.Merge(v); mergeDiags.HasErrors() {
 					diags = diags.Extend(mergeDiags)
+					continue
+				}
+				existing.ValueSource = override.source
+				switch override.source {
+				case sourceFile:
+					existing.ValueSource = "var-file " + v.DeclRange.Filename
+				case sourceProfile:
+					existing.ValueSource = "profile " + p.cfg.Profile
 				}
 			}
 		}
 	}
 
+	diags = packdiags.SafeDiagnosticsExtend(diags, p.mergeFlagMapEntries())
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	diags = packdiags.SafeDiagnosticsExtend(diags, p.applyVarPatches())
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	diags = packdiags.SafeDiagnosticsExtend(diags, p.checkOverrideValidations())
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
 	out := new(ParsedVariables)
 	out.LoadV2Result(p.rootVars)
 
-	return out, diags
+	return out, packdiags.SafeDiagnosticsExtend(diags, deprecationWarnings(out))
+}
+
+// checkOverrideValidations evaluates the validations of each variable whose
+// value has been set, rather than using its default. Defaults are the
+// responsibility of the pack author, and are checked using info
+// --check-defaults instead.
+func (p *ParserV2) checkOverrideValidations() hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	for _, pID := range slices.Sorted(maps.Keys(p.rootVars)) {
+		for _, vID := range slices.Sorted(maps.Keys(p.rootVars[pID])) {
+			if v := p.rootVars[pID][vID]; v.ValueSource != "" {
+				diags = diags.Extend(decoder.CheckValidations(v, v.Value))
+			}
+		}
+	}
+	return diags
+}
+
+// deprecationWarnings returns a warning for each deprecated variable which has
+// been set, rather than using its default. The warnings are ordered by the
+// name of the variable.
+func deprecationWarnings(pv *ParsedVariables) hcl.Diagnostics {
+	var names []string
+	vars := make(map[string]*variables.Variable)
+	for pID, packVars := range pv.GetVars() {
+		for vID, v := range packVars {
+			if v.Deprecated == "" || v.ValueSource == "" {
+				continue
+			}
+			name := pID.Join(pack.ID(vID)).String()
+			names = append(names, name)
+			vars[name] = v
+		}
+	}
+	sort.Strings(names)
+
+	var diags hcl.Diagnostics
+	for _, name := range names {
+		v := vars[name]
+		diags = diags.Append(packdiags.DiagDeprecatedVariable(name, v.ValueSource, v.Deprecated, v.DeclRange.Ptr()))
+	}
+	return diags
 }
 
 func (p *ParserV2) newParseOverridesFile(file string) (map[string]*hcl.File, hcl.Diagnostics) {
 	var diags hcl.Diagnostics
 
-	src, err := p.fs.ReadFile(file)
+	src, err := readVariableFile(p.fs, file, maxVariableFileSize(p.cfg))
 	if err != nil {
-		return nil, diags.Append(packdiags.DiagFileNotFound(file))
+		return nil, diags.Append(packdiags.DiagFileNotReadable(file, err))
 	}
 
 	ovrds := make(variables.Overrides)
@@ -150,6 +274,31 @@ func (p *ParserV2) newParseOverridesFile(file string) (map[string]*hcl.File, hcl
 	return nil, diags
 }
 
+// parseProfile decodes the variable overrides of the selected profile,
+// including those it inherits, from the profile file.
+func (p *ParserV2) parseProfile() hcl.Diagnostics {
+	src, err := readVariableFile(p.fs, p.cfg.ProfileFile, maxVariableFileSize(p.cfg))
+	if err != nil {
+		return hcl.Diagnostics{packdiags.DiagFileNotReadable(p.cfg.ProfileFile, err)}
+	}
+
+	ovrds, diags := varfile.DecodeProfile(p.cfg.ParentPack, p.cfg.ProfileFile, src, p.cfg.Profile)
+	if diags.HasErrors() {
+		return diags
+	}
+	for _, o := range ovrds {
+		if _, ok := p.cfg.RootVariableFiles[o.Path]; ok {
+			p.profileOverrideVars[o.Path] = append(p.profileOverrideVars[o.Path], &variables.Variable{
+				Name:      o.Name,
+				Type:      o.Type,
+				Value:     o.Value,
+				DeclRange: o.Range,
+			})
+		}
+	}
+	return diags
+}
+
 func (p *ParserV2) newHandleOverride(o *variables.Override) {
 	// Is Pack Variable Object?
 	// Check whether the name has an associated entry within the root variable
@@ -215,6 +364,9 @@ func (p *ParserV2) parseRootFiles() hcl.Diagnostics {
 
 		rootVars, parseDiags := p.parseRootBodyContent(content)
 		diags = packdiags.SafeDiagnosticsExtend(diags, parseDiags)
+		for _, v := range rootVars {
+			decoder.SetConditionSources(v, file.Content)
+		}
 
 		// If we don't have any errors processing the file, and its content,
 		// add an entry.
@@ -240,6 +392,9 @@ func (p *ParserV2) parseRootBodyContent(body *hcl.BodyContent) (map[variables.ID
 		cfg, cfgDiags := decoder.DecodeVariableBlock(block)
 		diags = packdiags.SafeDiagnosticsExtend(diags, cfgDiags)
 		if cfg != nil {
+			if cfg.DefaultFrom != "" {
+				diags = packdiags.SafeDiagnosticsExtend(diags, resolveProvidedDefault(cfg))
+			}
 			packRootVars[cfg.Name] = cfg
 		}
 	}
@@ -247,16 +402,394 @@ func (p *ParserV2) parseRootBodyContent(body *hcl.BodyContent) (map[variables.ID
 }
 
 func (p *ParserV2) parseEnvVariable(name string, rawVal string) hcl.Diagnostics {
-	return p.parseVariableImpl(name, rawVal, p.envOverrideVars, name, "environment")
+	return p.parseVariableImpl(name, rawVal, cty.NilType, p.envOverrideVars, name, "environment")
 
 }
 func (p *ParserV2) parseFlagVariable(name string, rawVal string) hcl.Diagnostics {
-	return p.parseVariableImpl(name, rawVal, p.flagOverrideVars, "-var", "arguments")
+	name, hint, diags := flagTypeHint(name)
+	if diags.HasErrors() {
+		return diags
+	}
+	if pID, vID, key, ok := p.mapEntryName(name); ok {
+		return p.parseFlagMapEntry(pID, vID, key, name, rawVal, hint)
+	}
+	return p.parseVariableImpl(name, rawVal, hint, p.flagOverrideVars, "-var", "arguments")
+}
+
+// flagTypeHint splits an explicit type hint, such as the number within
+// port:number, from the name of a CLI variable. The hint uses the same syntax
+// as the type of a variable declaration. cty.NilType is returned when the name
+// does not include a type hint.
+func flagTypeHint(name string) (string, cty.Type, hcl.Diagnostics) {
+	name, hint, ok := strings.Cut(name, ":")
+	if !ok {
+		return name, cty.NilType, nil
+	}
+
+	fakeRange := hcl.Range{
+		Filename: fmt.Sprintf("<type hint for var %s from arguments>", name),
+		Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+		End:      hcl.Pos{Line: 1, Column: len(hint) + 1, Byte: len(hint)},
+	}
+
+	expr, diags := hclsyntax.ParseExpression([]byte(hint), fakeRange.Filename, fakeRange.Start)
+	if diags.HasErrors() {
+		return name, cty.NilType, hcl.Diagnostics{packdiags.DiagInvalidTypeHint(hint, &fakeRange)}
+	}
+
+	typ, diags := typeexpr.TypeConstraint(expr)
+	if diags.HasErrors() {
+		return name, cty.NilType, hcl.Diagnostics{packdiags.DiagInvalidTypeHint(hint, &fakeRange)}
+	}
+	return name, typ, nil
+}
+
+// parseTypeHintedValue parses the raw value of a CLI variable using the type
+// hint passed with it, rather than the type of the variable, and ensures the
+// value is compatible with the hint.
+func parseTypeHintedValue(filename, rawVal string, hint cty.Type) (cty.Value, hcl.Diagnostics) {
+	expr, diags := hclhelp.ExpressionFromVariableDefinition(filename, rawVal, hint)
+	if diags.HasErrors() {
+		return cty.DynamicVal, diags
+	}
+
+	val, diags := expr.Value(nil)
+	if diags.HasErrors() {
+		return cty.DynamicVal, diags
+	}
+
+	val, err := convert.Convert(val, hint)
+	if err != nil {
+		return cty.DynamicVal, hcl.Diagnostics{packdiags.DiagInvalidValueForTypeHint(hint.FriendlyNameForConstraint(), err, expr.Range().Ptr())}
+	}
+	return val, nil
+}
+
+// mapEntryName determines whether a dotted CLI variable name refers to an
+// entry within a map or object variable, such as labels.env, rather than a
+// variable within a dependent pack. Variables of dependent packs take
+// precedence when both are possible.
+func (p *ParserV2) mapEntryName(name string) (pack.ID, variables.ID, string, bool) {
+	splitName := strings.Split(name, ".")
+	if len(splitName) < 2 {
+		return "", "", "", false
+	}
+
+	last := len(splitName) - 1
+	packPID := p.cfg.ParentPack.ID().Join(pack.ID(strings.Join(splitName[0:last], ".")))
+	if _, exists := p.rootVars[packPID][variables.ID(splitName[last])]; exists {
+		return "", "", "", false
+	}
+
+	varPID := p.cfg.ParentPack.ID()
+	if last > 1 {
+		varPID = varPID.Join(pack.ID(strings.Join(splitName[0:last-1], ".")))
+	}
+	varVID := variables.ID(splitName[last-1])
+
+	existing, exists := p.rootVars[varPID][varVID]
+	if !exists {
+		return "", "", "", false
+	}
+	if typ := variableType(existing); !typ.IsMapType() && !typ.IsObjectType() {
+		return "", "", "", false
+	}
+	return varPID, varVID, splitName[last], true
 }
 
-func (p *ParserV2) parseVariableImpl(name, rawVal string, tgt variables.PackIDKeyedVarMap, typeTxt, rangeDesc string) hcl.Diagnostics {
+// parseFlagMapEntry parses the value of a single map entry set from the CLI
+// and stores it, so it can be merged into the variable once parsing of all
+// other overrides has completed.
+func (p *ParserV2) parseFlagMapEntry(pID pack.ID, vID variables.ID, key, name, rawVal string, hint cty.Type) hcl.Diagnostics {
+	existing := p.rootVars[pID][vID]
+	typ := variableType(existing)
+
+	lines := strings.Split(rawVal, "\n")
+	fakeRange := hcl.Range{
+		Filename: fmt.Sprintf("<value for var %s from arguments>", name),
+		Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+		End:      hcl.Pos{Line: len(lines), Column: len(lines[len(lines)-1]), Byte: len(rawVal)},
+	}
+
+	// Work out the type of the entry, so the raw value can be parsed in the
+	// same way as a whole variable would be. Entries of untyped maps and new
+	// attributes of untyped objects are treated as strings.
+	entryType := cty.NilType
+	switch {
+	case typ.IsMapType():
+		entryType = typ.ElementType()
+	case typ.HasAttribute(key):
+		entryType = typ.AttributeType(key)
+	case existing.Type.IsObjectType():
+		return hcl.Diagnostics{packdiags.DiagInvalidValueForType(
+			fmt.Errorf("attribute %q is not defined on variable %q", key, vID), &fakeRange)}
+	}
+	if entryType == cty.DynamicPseudoType {
+		entryType = cty.NilType
+	}
+
+	var val cty.Value
+	var diags hcl.Diagnostics
+	if hint != cty.NilType {
+		val, diags = parseTypeHintedValue(fakeRange.Filename, rawVal, hint)
+	} else {
+		var expr hcl.Expression
+		expr, diags = hclhelp.ExpressionFromVariableDefinition(fakeRange.Filename, rawVal, entryType)
+		if !diags.HasErrors() {
+			val, diags = expr.Value(nil)
+		}
+	}
+	if diags.HasErrors() {
+		return diags
+	}
+
+	if entryType != cty.NilType {
+		var err *hcl.Diagnostic
+		val, err = hclhelp.ConvertValUsingType(val, entryType, fakeRange.Ptr())
+		if err != nil {
+			return hcl.Diagnostics{err}
+		}
+	}
+
+	if p.flagMapEntries == nil {
+		p.flagMapEntries = make(map[pack.ID]map[variables.ID][]*mapEntry)
+	}
+	if p.flagMapEntries[pID] == nil {
+		p.flagMapEntries[pID] = make(map[variables.ID][]*mapEntry)
+	}
+	p.flagMapEntries[pID][vID] = append(p.flagMapEntries[pID][vID], &mapEntry{key: key, value: val, rng: fakeRange})
+
+	return nil
+}
+
+// mergeFlagMapEntries merges the map entries set from the CLI into the
+// current value of their variables. Setting both the whole variable and
+// individual entries from the CLI is ambiguous and results in an error.
+func (p *ParserV2) mergeFlagMapEntries() hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	for pID, vars := range p.flagMapEntries {
+		for vID, entries := range vars {
+			if p.hasFlagOverride(pID, vID) {
+				diags = diags.Append(packdiags.DiagConflictingMapEntry(vID.String(), entries[0].rng.Ptr()))
+				continue
+			}
+
+			existing := p.rootVars[pID][vID]
+
+			vals := make(map[string]cty.Value)
+			if existing.Value.Type() != cty.NilType && !existing.Value.IsNull() && existing.Value.IsKnown() {
+				for k, v := range existing.Value.AsValueMap() {
+					vals[k] = v
+				}
+			}
+			for _, entry := range entries {
+				vals[entry.key] = entry.value
+			}
+
+			// Build the merged value as an object and convert it to the
+			// variable's type, which unifies the types of the map elements.
+			merged := cty.ObjectVal(vals)
+			target := existing.Type
+			if target == cty.NilType || target == cty.DynamicPseudoType {
+				target = cty.NilType
+				if variableType(existing).IsMapType() {
+					target = cty.Map(cty.DynamicPseudoType)
+				}
+			}
+			if target != cty.NilType {
+				var err *hcl.Diagnostic
+				if merged, err = hclhelp.ConvertValUsingType(merged, target, entries[0].rng.Ptr()); err != nil {
+					diags = diags.Append(err)
+					continue
+				}
+			}
+			existing.Value = merged
+			existing.ValueSource = sourceFlag
+		}
+	}
+
+	return diags
+}
+
+// applyVarPatches applies the JSON Patch documents set from the CLI to the
+// resolved values of their variables, in the order of the variable names.
+func (p *ParserV2) applyVarPatches() hcl.Diagnostics {
+	names := make([]string, 0, len(p.cfg.PatchOverrides))
+	for name := range p.cfg.PatchOverrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var diags hcl.Diagnostics
+	for _, name := range names {
+		diags = packdiags.SafeDiagnosticsExtend(diags, p.applyVarPatch(name, p.cfg.PatchOverrides[name]))
+	}
+	return diags
+}
+
+// applyVarPatch applies a single JSON Patch document to the variable. The
+// value is patched in its JSON form and converted back to the variable's type.
+func (p *ParserV2) applyVarPatch(name, rawPatch string) hcl.Diagnostics {
+	lines := strings.Split(rawPatch, "\n")
+	fakeRange := hcl.Range{
+		Filename: fmt.Sprintf("<patch for var %s from arguments>", name),
+		Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+		End:      hcl.Pos{Line: len(lines), Column: len(lines[len(lines)-1]), Byte: len(rawPatch)},
+	}
+
+	splitName := strings.Split(name, ".")
+	last := len(splitName) - 1
+	pID := p.cfg.ParentPack.ID()
+	if last > 0 {
+		pID = pID.Join(pack.ID(strings.Join(splitName[0:last], ".")))
+	}
+	vID := variables.ID(splitName[last])
+
+	existing, exists := p.rootVars[pID][vID]
+	if !exists {
+		if p.cfg.IgnoreMissingVars {
+			return nil
+		}
+		return hcl.Diagnostics{packdiags.DiagMissingRootVar(name, &fakeRange)}
+	}
+
+	typ := variableType(existing)
+	if !typ.IsMapType() && !typ.IsObjectType() {
+		return hcl.Diagnostics{packdiags.DiagInvalidVarPatch(name,
+			fmt.Errorf("only map and object variables can be patched, but the variable is a %s", typ.FriendlyName()), &fakeRange)}
+	}
+
+	patch, err := jsonpatch.Decode([]byte(rawPatch))
+	if err != nil {
+		return hcl.Diagnostics{packdiags.DiagInvalidVarPatch(name, err, &fakeRange)}
+	}
+
+	doc := []byte("null")
+	if !existing.Value.IsNull() {
+		if doc, err = ctyjson.Marshal(existing.Value, existing.Value.Type()); err != nil {
+			return hcl.Diagnostics{packdiags.DiagFailedToConvertCty(err, &fakeRange)}
+		}
+	}
+	if doc, err = patch.Apply(doc); err != nil {
+		return hcl.Diagnostics{packdiags.DiagInvalidVarPatch(name, err, &fakeRange)}
+	}
+
+	impliedType, err := ctyjson.ImpliedType(doc)
+	if err != nil {
+		return hcl.Diagnostics{packdiags.DiagInvalidVarPatch(name, err, &fakeRange)}
+	}
+	if impliedType == cty.DynamicPseudoType {
+		return hcl.Diagnostics{packdiags.DiagInvalidVarPatch(name, errors.New("the patched value must not be null"), &fakeRange)}
+	}
+	val, err := ctyjson.Unmarshal(doc, impliedType)
+	if err != nil {
+		return hcl.Diagnostics{packdiags.DiagInvalidVarPatch(name, err, &fakeRange)}
+	}
+
+	// Converting to an object type discards undeclared attributes, which would
+	// hide a mistyped patch path, so these are an error.
+	if path := undeclaredAttribute(val, existing.Type); path != "" {
+		return hcl.Diagnostics{packdiags.DiagInvalidVarPatch(name,
+			fmt.Errorf("attribute %s is not defined on variable %q", path, vID), &fakeRange)}
+	}
+
+	target := existing.Type
+	if target == cty.NilType || target == cty.DynamicPseudoType {
+		target = cty.NilType
+		if typ.IsMapType() {
+			target = cty.Map(cty.DynamicPseudoType)
+		}
+	}
+	if target != cty.NilType {
+		var diag *hcl.Diagnostic
+		if val, diag = hclhelp.ConvertValUsingType(val, target, &fakeRange); diag != nil {
+			return hcl.Diagnostics{diag}
+		}
+	}
+
+	existing.Value = val
+	existing.ValueSource = sourcePatch
+	return nil
+}
+
+// undeclaredAttribute returns the JSON Pointer of the first attribute of the
+// object value which is not declared by the object type, or an empty string
+// when all attributes are declared.
+func undeclaredAttribute(val cty.Value, typ cty.Type) string {
+	if !typ.IsObjectType() || !val.Type().IsObjectType() || val.IsNull() {
+		return ""
+	}
+
+	names := make([]string, 0, len(val.Type().AttributeTypes()))
+	for name := range val.Type().AttributeTypes() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		token := "/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(name)
+		if !typ.HasAttribute(name) {
+			return token
+		}
+		if path := undeclaredAttribute(val.GetAttr(name), typ.AttributeType(name)); path != "" {
+			return token + path
+		}
+	}
+	return ""
+}
+
+// hasFlagOverride returns whether the whole variable has been set from the
+// CLI.
+func (p *ParserV2) hasFlagOverride(pID pack.ID, vID variables.ID) bool {
+	for _, v := range p.flagOverrideVars[pID] {
+		if v.Name == vID {
+			return true
+		}
+	}
+	return false
+}
+
+// variableType returns the declared type of the variable, or the type of its
+// value when it has no concrete declared type.
+func variableType(v *variables.Variable) cty.Type {
+	if v.Type != cty.NilType && v.Type != cty.DynamicPseudoType {
+		return v.Type
+	}
+	return v.Value.Type()
+}
+
+// ParseVariableValue parses a raw value for the variable, such as one entered
+// at a prompt, in the same way as a value passed using --var. The value is
+// converted to the type of the variable.
+func ParseVariableValue(v *variables.Variable, rawVal string) (cty.Value, hcl.Diagnostics) {
+	filename := fmt.Sprintf("<value for var %s>", v.Name)
+
+	expr, diags := hclhelp.ExpressionFromVariableDefinition(filename, rawVal, v.Type)
+	if diags.HasErrors() {
+		return cty.NilVal, diags
+	}
+	val, diags := expr.Value(nil)
+	if diags.HasErrors() {
+		return cty.NilVal, diags
+	}
+
+	if v.Type != cty.NilType {
+		rng := hcl.Range{Filename: filename}
+		var err *hcl.Diagnostic
+		if val, err = hclhelp.ConvertValUsingType(val, v.Type, &rng); err != nil {
+			return cty.NilVal, hcl.Diagnostics{err}
+		}
+	}
+	return val, nil
+}
+
+func (p *ParserV2) parseVariableImpl(name, rawVal string, hint cty.Type, tgt variables.PackIDKeyedVarMap, typeTxt, rangeDesc string) hcl.Diagnostics {
+	// Diagnostics for values from the environment name the environment
+	// variable, so it can be found without knowing the prefix.
 	if rangeDesc == "environment" {
 		name = strings.TrimPrefix(name, envloader.DefaultPrefix)
+		rangeDesc = "environment variable " + envloader.DefaultPrefix + name
 	}
 
 	// Split the name to see if we have a namespace CLI variable for a child
@@ -303,12 +836,34 @@ func (p *ParserV2) parseVariableImpl(name, rawVal string, tgt variables.PackIDKe
 		return hcl.Diagnostics{packdiags.DiagMissingRootVar(name, &fakeRange)}
 	}
 
-	expr, diags := hclhelp.ExpressionFromVariableDefinition(fakeRange.Filename, rawVal, existing.Type)
-	if diags.HasErrors() {
-		return diags
+	// A value passed by reference is resolved before it is parsed, so the
+	// resolved value is interpreted in the same way as one passed directly.
+	var valueFrom string
+	var sensitive bool
+	if p.cfg.ResolveValueRefs {
+		resolved, resolver, isRef, err := resolveValueRef(rawVal)
+		if err != nil {
+			return hcl.Diagnostics{packdiags.DiagInvalidValueRef(rawVal, err, &fakeRange)}
+		}
+		if isRef {
+			valueFrom, sensitive = rawVal, resolver.Sensitive()
+			rawVal = resolved
+		}
 	}
 
-	val, diags := expr.Value(nil)
+	// A type hint overrides how the raw value is interpreted, otherwise it is
+	// interpreted using the type of the variable.
+	var val cty.Value
+	var diags hcl.Diagnostics
+	if hint != cty.NilType {
+		val, diags = parseTypeHintedValue(fakeRange.Filename, rawVal, hint)
+	} else {
+		var expr hcl.Expression
+		expr, diags = hclhelp.ExpressionFromVariableDefinition(fakeRange.Filename, rawVal, existing.Type)
+		if !diags.HasErrors() {
+			val, diags = expr.Value(nil)
+		}
+	}
 	if diags.HasErrors() {
 		return diags
 	}
@@ -317,7 +872,7 @@ func (p *ParserV2) parseVariableImpl(name, rawVal string, tgt variables.PackIDKe
 	// variable, so we know they are compatible.
 	if existing.Type != cty.NilType {
 		var err *hcl.Diagnostic
-		val, err = hclhelp.ConvertValUsingType(val, existing.Type, expr.Range().Ptr())
+		val, err = hclhelp.ConvertValUsingType(val, existing.Type, fakeRange.Ptr())
 		if err != nil {
 			return hcl.Diagnostics{err}
 		}
@@ -328,6 +883,8 @@ func (p *ParserV2) parseVariableImpl(name, rawVal string, tgt variables.PackIDKe
 		Name:      varVID,
 		Type:      val.Type(),
 		Value:     val,
+		ValueFrom: valueFrom,
+		Sensitive: sensitive,
 		DeclRange: fakeRange,
 	}
 	tgt[varPID] = append(tgt[varPID], &v)
@@ -5,11 +5,13 @@ package parser
 
 import (
 	"fmt"
+	"os"
 	"path"
 	"strings"
 	"testing"
 
 	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/hashicorp/nomad-pack/internal/pkg/loader"
 	"github.com/hashicorp/nomad-pack/internal/pkg/testfixture"
 	"github.com/hashicorp/nomad-pack/internal/pkg/variable/envloader"
@@ -58,6 +60,26 @@ func TestParserV2_NewParserV2(t *testing.T) {
 		must.Error(t, err)
 		must.ErrorContains(t, err, "error loading variable file")
 	})
+	t.Run("fails/with override file larger than the maximum size", func(t *testing.T) {
+		file := path.Join(t.TempDir(), "large.hcl")
+		must.NoError(t, os.WriteFile(file, []byte(`input = "0123456789"`), 0o644))
+		p, err := NewParserV2(&config.ParserConfig{
+			ParentPack:          testpack("example"),
+			FileOverrides:       []string{file},
+			MaxVariableFileSize: 8,
+		})
+		must.Nil(t, p)
+		must.ErrorContains(t, err, fmt.Sprintf("error loading variable file %q: size of 20 bytes exceeds the maximum of 8 bytes", file))
+	})
+	t.Run("fails/with override file which is not a regular file", func(t *testing.T) {
+		dir := t.TempDir()
+		p, err := NewParserV2(&config.ParserConfig{
+			ParentPack:    testpack("example"),
+			FileOverrides: []string{dir},
+		})
+		must.Nil(t, p)
+		must.ErrorContains(t, err, fmt.Sprintf("error loading variable file %q: not a regular file", dir))
+	})
 	t.Run("passes", func(t *testing.T) {
 		p, err := NewParserV2(&config.ParserConfig{
 			ParentPack: testpack("example"),
@@ -67,6 +89,21 @@ func TestParserV2_NewParserV2(t *testing.T) {
 	})
 }
 
+func TestParserV2_ReadVariableFileSize(t *testing.T) {
+	file := path.Join(t.TempDir(), "overrides.hcl")
+	must.NoError(t, os.WriteFile(file, []byte(`input = "0123456789"`), 0o644))
+
+	// The size is checked again when the file is read, in case it has grown
+	// since the parser was created.
+	p := NewTestInputParserV2()
+	p.cfg.MaxVariableFileSize = 20
+	must.NoError(t, os.WriteFile(file, []byte(`input = "01234567890123456789"`), 0o644))
+
+	_, diags := p.newParseOverridesFile(file)
+	must.True(t, diags.HasErrors())
+	must.StrContains(t, diags.Error(), "size of 30 bytes exceeds the maximum of 20 bytes")
+}
+
 func TestParserV2_parseFlagVariable(t *testing.T) {
 	testCases := []struct {
 		inputParser      *ParserV2
@@ -337,6 +374,23 @@ func TestParserV2_parseEnvVariable(t *testing.T) {
 	}
 }
 
+func TestParserV2_EnvVariableDiagnostics(t *testing.T) {
+	p := NewTestInputParserV2()
+	p.cfg.EnvOverrides = map[string]string{"count": "many"}
+	p.rootVars["example"]["count"] = &variables.Variable{
+		Name:  "count",
+		Type:  cty.Number,
+		Value: cty.NumberIntVal(1),
+	}
+
+	// The value is converted using the type of the variable, and the
+	// diagnostic names the environment variable it was read from.
+	_, diags := p.Parse()
+	must.True(t, diags.HasErrors())
+	must.NotNil(t, diags[0].Subject)
+	must.Eq(t, "<value for var count from environment variable NOMAD_PACK_VAR_count>", diags[0].Subject.Filename)
+}
+
 func TestParserV2_parseHeredocAtEOF(t *testing.T) {
 	inputParser := &ParserV2{
 		fs: afero.Afero{Fs: afero.OsFs{}},
@@ -468,9 +522,10 @@ func NewTestInputParserV2(opts ...testParserV2Option) *ParserV2 {
 				},
 			},
 		},
-		envOverrideVars:  make(variables.PackIDKeyedVarMap),
-		fileOverrideVars: make(variables.PackIDKeyedVarMap),
-		flagOverrideVars: make(variables.PackIDKeyedVarMap),
+		envOverrideVars:     make(variables.PackIDKeyedVarMap),
+		profileOverrideVars: make(variables.PackIDKeyedVarMap),
+		fileOverrideVars:    make(variables.PackIDKeyedVarMap),
+		flagOverrideVars:    make(variables.PackIDKeyedVarMap),
 	}
 
 	// Loop through each option
@@ -489,3 +544,388 @@ func NewStringVariableV2(key, value, kind string) *variables.Variable {
 		DeclRange: hcl.Range{Filename: fmt.Sprintf("<value for var %s from %s>", key, kind)},
 	}
 }
+
+func TestParserV2_MapEntryFlags(t *testing.T) {
+	newParser := func(flags map[string]string) *ParserV2 {
+		p := NewTestInputParserV2()
+		p.cfg.FlagOverrides = flags
+		p.rootVars["example"]["labels"] = &variables.Variable{
+			Name:  "labels",
+			Type:  cty.Map(cty.String),
+			Value: cty.MapVal(map[string]cty.Value{"app": cty.StringVal("web")}),
+		}
+		p.rootVars["example"]["limits"] = &variables.Variable{
+			Name: "limits",
+			Type: cty.Object(map[string]cty.Type{"cpu": cty.Number, "memory": cty.Number}),
+			Value: cty.ObjectVal(map[string]cty.Value{
+				"cpu":    cty.NumberIntVal(100),
+				"memory": cty.NumberIntVal(256),
+			}),
+		}
+		return p
+	}
+
+	t.Run("merges entries with the default", func(t *testing.T) {
+		pv, diags := newParser(map[string]string{
+			"labels.env":    "prod",
+			"labels.app":    "api",
+			"limits.memory": "512",
+		}).Parse()
+		must.SliceEmpty(t, diags)
+
+		must.True(t, pv.v2Vars["example"]["labels"].Value.RawEquals(cty.MapVal(map[string]cty.Value{
+			"app": cty.StringVal("api"),
+			"env": cty.StringVal("prod"),
+		})))
+		must.True(t, pv.v2Vars["example"]["limits"].Value.RawEquals(cty.ObjectVal(map[string]cty.Value{
+			"cpu":    cty.NumberIntVal(100),
+			"memory": cty.NumberIntVal(512),
+		})))
+	})
+
+	t.Run("errors on conflicting assignments", func(t *testing.T) {
+		_, diags := newParser(map[string]string{
+			"labels":     `{env = "dev"}`,
+			"labels.env": "prod",
+		}).Parse()
+		must.True(t, diags.HasErrors())
+		must.StrContains(t, diags.Error(), "Conflicting variable assignments")
+	})
+
+	t.Run("errors on unknown object attribute", func(t *testing.T) {
+		_, diags := newParser(map[string]string{"limits.disk": "10"}).Parse()
+		must.True(t, diags.HasErrors())
+		must.StrContains(t, diags.Error(), `attribute "disk" is not defined`)
+	})
+
+	t.Run("errors on invalid entry value", func(t *testing.T) {
+		_, diags := newParser(map[string]string{"limits.cpu": "lots"}).Parse()
+		must.True(t, diags.HasErrors())
+	})
+}
+
+func TestParserV2_TypeHintFlags(t *testing.T) {
+	newParser := func(flags map[string]string) *ParserV2 {
+		p := NewTestInputParserV2()
+		p.cfg.FlagOverrides = flags
+		p.rootVars["example"]["port"] = &variables.Variable{Name: "port"}
+		p.rootVars["example"]["name"] = &variables.Variable{Name: "name", Type: cty.String}
+		p.rootVars["example"]["count"] = &variables.Variable{Name: "count", Type: cty.Number}
+		p.rootVars["example"]["labels"] = &variables.Variable{Name: "labels", Type: cty.Map(cty.DynamicPseudoType)}
+		return p
+	}
+
+	t.Run("overrides inference", func(t *testing.T) {
+		pv, diags := newParser(map[string]string{
+			"port:number":       "8080",
+			"name:string":       "8080",
+			"labels.rev:number": "3",
+		}).Parse()
+		must.SliceEmpty(t, diags)
+
+		must.True(t, pv.v2Vars["example"]["port"].Value.RawEquals(cty.NumberIntVal(8080)))
+		must.True(t, pv.v2Vars["example"]["name"].Value.RawEquals(cty.StringVal("8080")))
+		must.True(t, pv.v2Vars["example"]["labels"].Value.RawEquals(cty.MapVal(map[string]cty.Value{
+			"rev": cty.NumberIntVal(3),
+		})))
+	})
+
+	t.Run("errors on invalid hint", func(t *testing.T) {
+		_, diags := newParser(map[string]string{"port:integer": "8080"}).Parse()
+		must.True(t, diags.HasErrors())
+		must.StrContains(t, diags.Error(), "Invalid variable type hint")
+	})
+
+	t.Run("errors on value not matching hint", func(t *testing.T) {
+		_, diags := newParser(map[string]string{"port:number": "http"}).Parse()
+		must.True(t, diags.HasErrors())
+		must.StrContains(t, diags.Error(), "Invalid value for variable type hint")
+	})
+
+	t.Run("errors on hint not matching variable type", func(t *testing.T) {
+		_, diags := newParser(map[string]string{"count:bool": "true"}).Parse()
+		must.True(t, diags.HasErrors())
+		must.StrContains(t, diags.Error(), "Invalid value for variable")
+	})
+}
+
+func TestParserV2_ValueSource(t *testing.T) {
+	p := NewTestInputParserV2()
+	p.cfg.EnvOverrides = map[string]string{"NOMAD_PACK_VAR_region": "eu"}
+	p.cfg.FlagOverrides = map[string]string{"count": "3", "labels.env": "prod"}
+	p.rootVars["example"]["region"] = &variables.Variable{Name: "region", Type: cty.String}
+	p.rootVars["example"]["count"] = &variables.Variable{Name: "count", Type: cty.Number}
+	p.rootVars["example"]["labels"] = &variables.Variable{Name: "labels", Type: cty.Map(cty.String)}
+	p.rootVars["example"]["name"] = &variables.Variable{Name: "name", Type: cty.String}
+
+	pv, diags := p.Parse()
+	must.SliceEmpty(t, diags)
+
+	must.Eq(t, sourceEnv, pv.v2Vars["example"]["region"].ValueSource)
+	must.Eq(t, sourceFlag, pv.v2Vars["example"]["count"].ValueSource)
+	must.Eq(t, sourceFlag, pv.v2Vars["example"]["labels"].ValueSource)
+	must.Eq(t, "", pv.v2Vars["example"]["name"].ValueSource)
+}
+
+func TestParserV2_Profile(t *testing.T) {
+	profileFile := path.Join(t.TempDir(), "profiles.hcl")
+	must.NoError(t, os.WriteFile(profileFile, []byte(`
+profile "base" {
+  variables = {
+    region = "eu"
+    count  = 2
+    name   = "base"
+  }
+}
+
+profile "prod" {
+  inherits  = ["base"]
+  variables = {
+    count = 3
+  }
+}
+`), 0o644))
+
+	newParser := func(profile string) *ParserV2 {
+		p := NewTestInputParserV2()
+		p.cfg.RootVariableFiles = map[pack.ID]*pack.File{"example": {
+			Name: "variables.hcl",
+			Path: "example/variables.hcl",
+			Content: []byte(`
+variable "region" { type = string }
+variable "count" { type = number }
+variable "name" { type = string }
+`),
+		}}
+		p.cfg.Profile = profile
+		p.cfg.ProfileFile = profileFile
+		return p
+	}
+
+	t.Run("applies inherited overrides by precedence", func(t *testing.T) {
+		p := newParser("prod")
+		p.cfg.EnvOverrides = map[string]string{"NOMAD_PACK_VAR_region": "us"}
+		p.cfg.FlagOverrides = map[string]string{"name": "web"}
+
+		pv, diags := p.Parse()
+		must.SliceEmpty(t, diags)
+
+		vars := pv.v2Vars["example"]
+		must.True(t, vars["region"].Value.RawEquals(cty.StringVal("eu")))
+		must.Eq(t, "profile prod", vars["region"].ValueSource)
+		must.True(t, vars["count"].Value.RawEquals(cty.NumberIntVal(3)))
+		must.Eq(t, "profile prod", vars["count"].ValueSource)
+		must.True(t, vars["name"].Value.RawEquals(cty.StringVal("web")))
+		must.Eq(t, sourceFlag, vars["name"].ValueSource)
+	})
+
+	t.Run("errors on unknown profile", func(t *testing.T) {
+		_, diags := newParser("staging").Parse()
+		must.True(t, diags.HasErrors())
+		must.StrContains(t, diags.Error(), `The profile "staging" is not defined`)
+	})
+}
+
+func TestParserV2_JSONVariableFiles(t *testing.T) {
+	dir := t.TempDir()
+	hclFile := path.Join(dir, "a.hcl")
+	must.NoError(t, os.WriteFile(hclFile, []byte("input = \"hcl\"\ncount = 2\n"), 0o644))
+	jsonFile := path.Join(dir, "b.json")
+	must.NoError(t, os.WriteFile(jsonFile, []byte(`{"input": "json"}`), 0o644))
+
+	newParser := func(files ...string) *ParserV2 {
+		p := NewTestInputParserV2()
+		p.cfg.RootVariableFiles = map[pack.ID]*pack.File{"example": {
+			Name: "variables.hcl",
+			Path: "example/variables.hcl",
+			Content: []byte(`
+variable "input" { type = string }
+variable "count" { type = number }
+`),
+		}}
+		p.cfg.FileOverrides = files
+		return p
+	}
+
+	t.Run("merges HCL and JSON files", func(t *testing.T) {
+		pv, diags := newParser(hclFile, jsonFile).Parse()
+		must.SliceEmpty(t, diags)
+
+		vars := pv.v2Vars["example"]
+		must.Eq(t, "json", vars["input"].Value.AsString())
+		must.True(t, vars["count"].Value.RawEquals(cty.NumberIntVal(2)))
+	})
+
+	t.Run("reports malformed JSON", func(t *testing.T) {
+		badFile := path.Join(dir, "bad.json")
+		must.NoError(t, os.WriteFile(badFile, []byte("{\n  \"input\": \"json\",\n}"), 0o644))
+
+		_, diags := newParser(badFile).Parse()
+		must.True(t, diags.HasErrors())
+		must.NotNil(t, diags[0].Subject)
+		must.Eq(t, badFile, diags[0].Subject.Filename)
+		// The trailing comma is reported on the line it is on.
+		must.Eq(t, 2, diags[0].Subject.Start.Line)
+	})
+}
+
+func TestParserV2_ParseVariableValue(t *testing.T) {
+	val, diags := ParseVariableValue(&variables.Variable{Name: "count", Type: cty.Number}, "3")
+	must.SliceEmpty(t, diags)
+	must.True(t, val.RawEquals(cty.NumberIntVal(3)))
+
+	val, diags = ParseVariableValue(&variables.Variable{Name: "dcs", Type: cty.List(cty.String)}, `["dc1", "dc2"]`)
+	must.SliceEmpty(t, diags)
+	must.True(t, val.RawEquals(cty.ListVal([]cty.Value{cty.StringVal("dc1"), cty.StringVal("dc2")})))
+
+	val, diags = ParseVariableValue(&variables.Variable{Name: "region"}, "global")
+	must.SliceEmpty(t, diags)
+	must.True(t, val.RawEquals(cty.StringVal("global")))
+
+	_, diags = ParseVariableValue(&variables.Variable{Name: "count", Type: cty.Number}, "three")
+	must.True(t, diags.HasErrors())
+}
+
+func TestParserV2_DeprecatedVariable(t *testing.T) {
+	p := NewTestInputParserV2()
+	p.cfg.FlagOverrides = map[string]string{"instances": "3"}
+	p.rootVars["example"]["instances"] = &variables.Variable{Name: "instances", Type: cty.Number, Deprecated: "use count instead"}
+	p.rootVars["example"]["old_region"] = &variables.Variable{Name: "old_region", Type: cty.String, Deprecated: "use region instead"}
+
+	pv, diags := p.Parse()
+	must.False(t, diags.HasErrors())
+	must.Len(t, 1, diags)
+	must.Eq(t, hcl.DiagWarning, diags[0].Severity)
+	must.Eq(t, `The variable "example.instances" set by --var is deprecated: use count instead`, diags[0].Detail)
+	must.Eq(t, "use count instead", pv.v2Vars["example"]["instances"].Deprecated)
+}
+
+func TestParserV2_Validations(t *testing.T) {
+	newParser := func(flags map[string]string) *ParserV2 {
+		cond, diags := hclsyntax.ParseExpression([]byte("var.count > 0"), "variables.hcl", hcl.InitialPos)
+		must.SliceEmpty(t, diags)
+
+		p := NewTestInputParserV2()
+		p.cfg.FlagOverrides = flags
+		// The default does not satisfy the validation, which is only checked
+		// once the variable is set.
+		p.rootVars["example"]["count"] = &variables.Variable{
+			Name:        "count",
+			Type:        cty.Number,
+			Value:       cty.NumberIntVal(0),
+			Validations: []*variables.Validation{{Condition: cond, ErrorMessage: "count must be positive"}},
+		}
+		return p
+	}
+
+	_, diags := newParser(nil).Parse()
+	must.SliceEmpty(t, diags)
+
+	pv, diags := newParser(map[string]string{"count": "2"}).Parse()
+	must.SliceEmpty(t, diags)
+	must.True(t, pv.v2Vars["example"]["count"].Value.RawEquals(cty.NumberIntVal(2)))
+
+	_, diags = newParser(map[string]string{"count": "-1"}).Parse()
+	must.True(t, diags.HasErrors())
+	must.Eq(t, `The value of the variable "count" is invalid: count must be positive`, diags[0].Detail)
+}
+
+func TestParserV2_VarPatch(t *testing.T) {
+	configType := cty.Object(map[string]cty.Type{
+		"name": cty.String,
+		"server": cty.Object(map[string]cty.Type{
+			"port": cty.Number,
+			"tags": cty.List(cty.String),
+		}),
+	})
+
+	newParser := func(patches map[string]string) *ParserV2 {
+		p := NewTestInputParserV2()
+		p.cfg.PatchOverrides = patches
+		p.rootVars["example"]["config"] = &variables.Variable{
+			Name: "config",
+			Type: configType,
+			Value: cty.ObjectVal(map[string]cty.Value{
+				"name": cty.StringVal("web"),
+				"server": cty.ObjectVal(map[string]cty.Value{
+					"port": cty.NumberIntVal(8080),
+					"tags": cty.ListVal([]cty.Value{cty.StringVal("a")}),
+				}),
+			}),
+		}
+		p.rootVars["example"]["labels"] = &variables.Variable{
+			Name:  "labels",
+			Value: cty.MapVal(map[string]cty.Value{"env": cty.StringVal("dev")}),
+		}
+		return p
+	}
+
+	t.Run("patches nested values", func(t *testing.T) {
+		pv, diags := newParser(map[string]string{
+			"config": `[{"op":"replace","path":"/server/port","value":9090},{"op":"add","path":"/server/tags/-","value":"b"}]`,
+			"labels": `[{"op":"remove","path":"/env"},{"op":"add","path":"/team","value":"ops"}]`,
+		}).Parse()
+		must.SliceEmpty(t, diags)
+
+		config := pv.v2Vars["example"]["config"]
+		must.Eq(t, sourcePatch, config.ValueSource)
+		must.True(t, config.Value.RawEquals(cty.ObjectVal(map[string]cty.Value{
+			"name": cty.StringVal("web"),
+			"server": cty.ObjectVal(map[string]cty.Value{
+				"port": cty.NumberIntVal(9090),
+				"tags": cty.ListVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b")}),
+			}),
+		})))
+
+		labels := pv.v2Vars["example"]["labels"]
+		must.True(t, labels.Value.RawEquals(cty.MapVal(map[string]cty.Value{"team": cty.StringVal("ops")})))
+	})
+
+	t.Run("applies after flag overrides", func(t *testing.T) {
+		p := newParser(map[string]string{"labels": `[{"op":"test","path":"/env","value":"prod"}]`})
+		p.cfg.FlagOverrides = map[string]string{"labels.env": "prod"}
+		_, diags := p.Parse()
+		must.SliceEmpty(t, diags)
+	})
+
+	t.Run("errors on invalid path", func(t *testing.T) {
+		_, diags := newParser(map[string]string{
+			"config": `[{"op":"replace","path":"/server/missing/port","value":1}]`,
+		}).Parse()
+		must.True(t, diags.HasErrors())
+		must.StrContains(t, diags.Error(), "Invalid variable patch")
+		must.StrContains(t, diags.Error(), `path not found: "missing"`)
+	})
+
+	t.Run("errors on value not matching type", func(t *testing.T) {
+		_, diags := newParser(map[string]string{
+			"config": `[{"op":"replace","path":"/server/port","value":"http"}]`,
+		}).Parse()
+		must.True(t, diags.HasErrors())
+		must.StrContains(t, diags.Error(), "Invalid value for variable")
+	})
+
+	t.Run("errors on undeclared attribute", func(t *testing.T) {
+		_, diags := newParser(map[string]string{
+			"config": `[{"op":"add","path":"/server/prot","value":9090}]`,
+		}).Parse()
+		must.True(t, diags.HasErrors())
+		must.StrContains(t, diags.Error(), `attribute /server/prot is not defined on variable "config"`)
+	})
+
+	t.Run("errors on non-object variable", func(t *testing.T) {
+		_, diags := newParser(map[string]string{
+			"input": `[{"op":"add","path":"/a","value":1}]`,
+		}).Parse()
+		must.True(t, diags.HasErrors())
+		must.StrContains(t, diags.Error(), "only map and object variables can be patched")
+	})
+
+	t.Run("errors on missing variable", func(t *testing.T) {
+		_, diags := newParser(map[string]string{
+			"missing": `[{"op":"add","path":"/a","value":1}]`,
+		}).Parse()
+		must.True(t, diags.HasErrors())
+		must.StrContains(t, diags.Error(), "Missing base variable declaration")
+	})
+}
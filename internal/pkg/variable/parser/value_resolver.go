@@ -0,0 +1,183 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package parser
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	consulapi "github.com/hashicorp/consul/api"
+	vaultapi "github.com/hashicorp/vault/api"
+)
+
+// ValueResolver resolves variable values passed by reference, rather than
+// directly, from a source external to the pack, such as a secret store.
+// Values passed using --var or the environment reference a resolver in the
+// form "<resolver>:<reference>", such as "vault:secret/data/app#password".
+type ValueResolver interface {
+	// Resolve returns the raw value identified by ref. The value is parsed in
+	// the same way as the value it replaces. Unlike a DefaultProvider, there
+	// is no fallback, so a value which does not exist is an error.
+	Resolve(ref string) (string, error)
+
+	// Sensitive returns whether the resolved values are secrets, which must
+	// not be output or stored with the deployed pack.
+	Sensitive() bool
+}
+
+var (
+	valueResolversLock sync.RWMutex
+
+	// valueResolvers are the registered resolvers keyed by name.
+	valueResolvers = map[string]ValueResolver{
+		"env":    EnvValueResolver{},
+		"vault":  &VaultValueResolver{},
+		"consul": &ConsulValueResolver{},
+	}
+)
+
+// RegisterValueResolver registers the resolver using the name referenced by
+// variable values, replacing any resolver already registered with the name.
+// It allows additional resolvers, such as one for a cloud secret manager, to
+// be added.
+func RegisterValueResolver(name string, r ValueResolver) {
+	valueResolversLock.Lock()
+	defer valueResolversLock.Unlock()
+	valueResolvers[name] = r
+}
+
+func getValueResolver(name string) (ValueResolver, bool) {
+	valueResolversLock.RLock()
+	defer valueResolversLock.RUnlock()
+	r, ok := valueResolvers[name]
+	return r, ok
+}
+
+// resolveValueRef resolves the raw value of a variable when it references a
+// registered resolver. The returned bool is false when the raw value is not a
+// reference, in which case it is used as is.
+func resolveValueRef(rawVal string) (string, ValueResolver, bool, error) {
+	name, ref, ok := strings.Cut(rawVal, ":")
+	if !ok {
+		return rawVal, nil, false, nil
+	}
+	resolver, ok := getValueResolver(name)
+	if !ok {
+		return rawVal, nil, false, nil
+	}
+
+	val, err := resolver.Resolve(ref)
+	if err != nil {
+		return "", resolver, true, err
+	}
+	return val, resolver, true, nil
+}
+
+// EnvValueResolver resolves values from the environment variable with the
+// referenced name. Unset environment variables are an error.
+type EnvValueResolver struct{}
+
+func (EnvValueResolver) Resolve(ref string) (string, error) {
+	val, ok := os.LookupEnv(ref)
+	if !ok {
+		return "", fmt.Errorf("environment variable %q is not set", ref)
+	}
+	return val, nil
+}
+
+func (EnvValueResolver) Sensitive() bool { return false }
+
+// VaultValueResolver resolves values from a field of a Vault secret, which is
+// referenced in the form "<path>#<field>", such as "secret/data/app#password".
+// The fields of KV version 2 secrets are read from within their data. The
+// client is configured using the VAULT_ADDR and VAULT_TOKEN environment
+// variables, unless Config is set. Fields which are not strings are resolved
+// as JSON.
+type VaultValueResolver struct {
+	Config *vaultapi.Config
+}
+
+func (r *VaultValueResolver) Resolve(ref string) (string, error) {
+	path, field, ok := strings.Cut(ref, "#")
+	if !ok || path == "" || field == "" {
+		return "", errors.New("expected the form <path>#<field>")
+	}
+
+	cfg := r.Config
+	if cfg == nil {
+		cfg = vaultapi.DefaultConfig()
+	}
+	client, err := vaultapi.NewClient(cfg)
+	if err != nil {
+		return "", fmt.Errorf("failed to create Vault client: %w", err)
+	}
+
+	secret, err := client.Logical().Read(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read Vault secret %q: %w", path, err)
+	}
+	if secret == nil || secret.Data == nil {
+		return "", fmt.Errorf("secret %q does not exist in Vault", path)
+	}
+
+	data := secret.Data
+	if inner, ok := data["data"].(map[string]any); ok {
+		if _, ok := data["metadata"]; ok {
+			data = inner
+		}
+	}
+
+	val, ok := data[field]
+	if !ok {
+		return "", fmt.Errorf("secret %q in Vault does not have the field %q", path, field)
+	}
+	if s, ok := val.(string); ok {
+		return s, nil
+	}
+	b, err := json.Marshal(val)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode field %q of secret %q in Vault: %w", field, path, err)
+	}
+	return string(b), nil
+}
+
+func (r *VaultValueResolver) Sensitive() bool { return true }
+
+// ConsulValueResolver resolves values from the referenced key of the Consul
+// KV store, such as "config/app/region". The client is configured using the
+// CONSUL_HTTP_ADDR and CONSUL_HTTP_TOKEN environment variables, unless Config
+// is set.
+type ConsulValueResolver struct {
+	Config *consulapi.Config
+}
+
+func (r *ConsulValueResolver) Resolve(ref string) (string, error) {
+	if ref == "" {
+		return "", errors.New("expected the form <key>")
+	}
+
+	cfg := r.Config
+	if cfg == nil {
+		cfg = consulapi.DefaultConfig()
+	}
+	client, err := consulapi.NewClient(cfg)
+	if err != nil {
+		return "", fmt.Errorf("failed to create Consul client: %w", err)
+	}
+
+	pair, _, err := client.KV().Get(ref, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to read Consul key %q: %w", ref, err)
+	}
+	if pair == nil {
+		return "", fmt.Errorf("key %q does not exist in Consul", ref)
+	}
+	return string(pair.Value), nil
+}
+
+func (r *ConsulValueResolver) Sensitive() bool { return false }
@@ -0,0 +1,127 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package parser
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	consulapi "github.com/hashicorp/consul/api"
+	vaultapi "github.com/hashicorp/vault/api"
+	"github.com/shoenig/test/must"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/hashicorp/nomad-pack/internal/pkg/variable/parser/config"
+	"github.com/hashicorp/nomad-pack/sdk/pack"
+	"github.com/hashicorp/nomad-pack/sdk/pack/variables"
+)
+
+func TestParserV2_ValueResolvers(t *testing.T) {
+	// The fake Vault server responds with a KV version 2 secret, and the fake
+	// Consul server with a single key.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/secret/data/app":
+			w.Write([]byte(`{"data": {"data": {"password": "hunter2", "port": 8080}, "metadata": {}}}`))
+		case "/v1/kv/config/region":
+			w.Write([]byte(`[{"Key": "config/region", "Value": "ZXUtd2VzdC0x"}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	vaultCfg := vaultapi.DefaultConfig()
+	vaultCfg.Address = srv.URL
+	consulCfg := consulapi.DefaultConfig()
+	consulCfg.Address = srv.URL
+	RegisterValueResolver("vault", &VaultValueResolver{Config: vaultCfg})
+	RegisterValueResolver("consul", &ConsulValueResolver{Config: consulCfg})
+	t.Cleanup(func() {
+		RegisterValueResolver("vault", &VaultValueResolver{})
+		RegisterValueResolver("consul", &ConsulValueResolver{})
+	})
+
+	t.Setenv("NOMAD_PACK_TEST_IMAGE", "web:1")
+
+	newParser := func(resolve bool, flags map[string]string) *ParserV2 {
+		return &ParserV2{
+			cfg: &config.ParserConfig{
+				ParentPack: testpack(),
+				RootVariableFiles: map[pack.ID]*pack.File{
+					"example": {Name: "variables.hcl", Path: "variables.hcl", Content: []byte(`
+variable "password" {
+  type = string
+}
+variable "port" {
+  type = number
+}
+variable "region" {
+  type = string
+}
+variable "image" {
+  type = string
+}
+`)},
+				},
+				FlagOverrides:    flags,
+				ResolveValueRefs: resolve,
+			},
+			rootVars:         make(map[pack.ID]map[variables.ID]*variables.Variable),
+			envOverrideVars:  make(variables.PackIDKeyedVarMap),
+			fileOverrideVars: make(variables.PackIDKeyedVarMap),
+			flagOverrideVars: make(variables.PackIDKeyedVarMap),
+		}
+	}
+
+	t.Run("resolves references", func(t *testing.T) {
+		pv, diags := newParser(true, map[string]string{
+			"password": "vault:secret/data/app#password",
+			"port":     "vault:secret/data/app#port",
+			"region":   "consul:config/region",
+			"image":    "env:NOMAD_PACK_TEST_IMAGE",
+		}).Parse()
+		must.SliceEmpty(t, diags)
+
+		vars := pv.v2Vars["example"]
+		must.Eq(t, "hunter2", vars["password"].Value.AsString())
+		must.Eq(t, "vault:secret/data/app#password", vars["password"].ValueFrom)
+		must.True(t, vars["password"].Sensitive)
+		must.True(t, vars["port"].Value.RawEquals(cty.NumberIntVal(8080)))
+		must.Eq(t, "eu-west-1", vars["region"].Value.AsString())
+		must.False(t, vars["region"].Sensitive)
+		must.Eq(t, "web:1", vars["image"].Value.AsString())
+		must.False(t, vars["image"].Sensitive)
+	})
+
+	t.Run("ignores references when disabled", func(t *testing.T) {
+		pv, diags := newParser(false, map[string]string{"image": "env:NOMAD_PACK_TEST_IMAGE"}).Parse()
+		must.SliceEmpty(t, diags)
+		must.Eq(t, "env:NOMAD_PACK_TEST_IMAGE", pv.v2Vars["example"]["image"].Value.AsString())
+		must.Eq(t, "", pv.v2Vars["example"]["image"].ValueFrom)
+	})
+
+	t.Run("uses values of unknown resolvers as is", func(t *testing.T) {
+		pv, diags := newParser(true, map[string]string{"image": "docker.io/web:1"}).Parse()
+		must.SliceEmpty(t, diags)
+		must.Eq(t, "docker.io/web:1", pv.v2Vars["example"]["image"].Value.AsString())
+	})
+
+	t.Run("errors on missing values", func(t *testing.T) {
+		for ref, msg := range map[string]string{
+			"vault:secret/data/app#token":   `secret "secret/data/app" in Vault does not have the field "token"`,
+			"vault:secret/data/other#token": `secret "secret/data/other" does not exist in Vault`,
+			"vault:secret/data/app":         "expected the form <path>#<field>",
+			"consul:config/zone":            `key "config/zone" does not exist in Consul`,
+			"env:NOMAD_PACK_TEST_UNSET":     `environment variable "NOMAD_PACK_TEST_UNSET" is not set`,
+		} {
+			_, diags := newParser(true, map[string]string{"password": ref}).Parse()
+			must.True(t, diags.HasErrors())
+			must.StrContains(t, diags.Error(), "Failed to resolve variable value")
+			must.StrContains(t, diags.Error(), msg)
+		}
+	})
+}
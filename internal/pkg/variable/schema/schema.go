@@ -9,6 +9,14 @@ const (
 	VariableAttributeType        = "type"
 	VariableAttributeDefault     = "default"
 	VariableAttributeDescription = "description"
+	VariableAttributeDefaultFrom = "default_from"
+	VariableAttributeDeprecated  = "deprecated"
+	VariableAttributeGroup       = "group"
+
+	VariableBlockValidation = "validation"
+
+	ValidationAttributeCondition    = "condition"
+	ValidationAttributeErrorMessage = "error_message"
 )
 
 // VariableFileSchema defines the hcl.BlockHeaderSchema for each root variable
@@ -28,6 +36,21 @@ var VariableBlockSchema = &hcl.BodySchema{
 	Attributes: []hcl.AttributeSchema{
 		{Name: VariableAttributeDescription},
 		{Name: VariableAttributeDefault},
+		{Name: VariableAttributeDefaultFrom},
+		{Name: VariableAttributeDeprecated},
+		{Name: VariableAttributeGroup},
 		{Name: VariableAttributeType},
 	},
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: VariableBlockValidation},
+	},
+}
+
+// VariableValidationSchema defines the hcl.BodySchema for a validation block
+// within a root variable block.
+var VariableValidationSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: ValidationAttributeCondition, Required: true},
+		{Name: ValidationAttributeErrorMessage, Required: true},
+	},
 }
@@ -11,8 +11,9 @@ import (
 )
 
 const (
-	validationSubjParseFailed = "failed to parse job specification"
-	validationSubjConflict    = "failed job conflict validation"
+	validationSubjParseFailed    = "failed to parse job specification"
+	validationSubjConflict       = "failed job conflict validation"
+	validationSubjPackValidation = "failed pack validation"
 )
 
 var (
@@ -12,6 +12,7 @@ import (
 	"github.com/hashicorp/nomad/api"
 	"github.com/ryanuber/columnize"
 
+	"github.com/hashicorp/nomad-pack/internal/pkg/helper"
 	"github.com/hashicorp/nomad-pack/terminal"
 )
 
@@ -59,7 +60,7 @@ func formatTime(t time.Time) string {
 // truncating to a passed unit.
 // E.g. formatTimeDifference(first=1m22s33ms, second=1m28s55ms, time.Second) -> 6s
 func formatTimeDifference(first, second time.Time, d time.Duration) string {
-	return second.Truncate(d).Sub(first.Truncate(d)).String()
+	return helper.FormatDuration(second.Truncate(d).Sub(first.Truncate(d)))
 }
 
 func formatJobDiff(job api.JobDiff, verbose bool, ui terminal.UI) {
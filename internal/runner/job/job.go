@@ -102,8 +102,9 @@ func (r *Runner) Deploy(ui terminal.UI, errorContext *errors.UIErrorContext) *er
 
 		// submit the source of the job to Nomad, too
 		submission := &api.JobSubmission{
-			Source: r.rawTemplates[tplName],
-			Format: "hcl2",
+			Source:        r.rawTemplates[tplName],
+			Format:        "hcl2",
+			VariableFlags: r.runnerCfg.Variables,
 		}
 
 		registerOpts := api.RegisterOptions{
@@ -0,0 +1,120 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package job
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/tryfunc"
+	"github.com/hashicorp/nomad/api"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+
+	"github.com/hashicorp/nomad-pack/internal/pkg/errors"
+	"github.com/hashicorp/nomad-pack/sdk/pack"
+)
+
+// validationFunctions are the functions available to pack validation
+// conditions.
+var validationFunctions = map[string]function.Function{
+	"can":      tryfunc.CanFunc,
+	"concat":   stdlib.ConcatFunc,
+	"contains": stdlib.ContainsFunc,
+	"flatten":  stdlib.FlattenFunc,
+	"keys":     stdlib.KeysFunc,
+	"length":   stdlib.LengthFunc,
+	"lookup":   stdlib.LookupFunc,
+	"lower":    stdlib.LowerFunc,
+	"max":      stdlib.MaxFunc,
+	"min":      stdlib.MinFunc,
+	"regex":    stdlib.RegexFunc,
+	"try":      tryfunc.TryFunc,
+	"upper":    stdlib.UpperFunc,
+	"values":   stdlib.ValuesFunc,
+}
+
+// ValidateTemplates satisfies the ValidateTemplates function of the
+// runner.Runner interface.
+func (r *Runner) ValidateTemplates(rules []*pack.Validation) []*errors.WrappedUIContext {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	// Iterate the templates in a consistent order, so the output of multiple
+	// failures is stable.
+	tplNames := make([]string, 0, len(r.parsedTemplates))
+	for tplName := range r.parsedTemplates {
+		tplNames = append(tplNames, tplName)
+	}
+	sort.Strings(tplNames)
+
+	var outputErrors []*errors.WrappedUIContext
+
+	for _, tplName := range tplNames {
+		jobVal, err := jobToCty(r.parsedTemplates[tplName].canonical)
+		if err != nil {
+			outputErrors = append(outputErrors, newValidationDeployerError(err, validationSubjPackValidation, tplName))
+			continue
+		}
+
+		evalCtx := &hcl.EvalContext{
+			Variables: map[string]cty.Value{"job": jobVal},
+			Functions: validationFunctions,
+		}
+
+		for _, rule := range rules {
+			if err := evalValidation(rule, evalCtx); err != nil {
+				outputErrors = append(outputErrors, newValidationDeployerError(err, validationSubjPackValidation, tplName))
+			}
+		}
+	}
+
+	return outputErrors
+}
+
+// evalValidation evaluates the condition of the rule, returning an error when
+// the condition is not met or could not be evaluated.
+func evalValidation(rule *pack.Validation, evalCtx *hcl.EvalContext) error {
+	result, diags := rule.Condition.Value(evalCtx)
+	if diags.HasErrors() {
+		return fmt.Errorf("validation %q: %s", rule.Name, diags.Error())
+	}
+
+	if !result.IsKnown() || result.IsNull() {
+		return fmt.Errorf("validation %q: condition must not be null", rule.Name)
+	}
+	if result.Type() != cty.Bool {
+		return fmt.Errorf("validation %q: condition must be a bool, got %s", rule.Name, result.Type().FriendlyName())
+	}
+
+	if result.False() {
+		return fmt.Errorf("validation %q: %s", rule.Name, rule.ErrorMessage)
+	}
+	return nil
+}
+
+// jobToCty converts the job to a cty object, using the same field names as
+// the Nomad JSON job specification.
+func jobToCty(job *api.Job) (cty.Value, error) {
+	raw, err := json.Marshal(job)
+	if err != nil {
+		return cty.NilVal, fmt.Errorf("failed to encode job: %w", err)
+	}
+
+	ty, err := ctyjson.ImpliedType(raw)
+	if err != nil {
+		return cty.NilVal, fmt.Errorf("failed to determine job type: %w", err)
+	}
+
+	val, err := ctyjson.Unmarshal(raw, ty)
+	if err != nil {
+		return cty.NilVal, fmt.Errorf("failed to decode job: %w", err)
+	}
+	return val, nil
+}
@@ -0,0 +1,81 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package job
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/nomad/api"
+	"github.com/shoenig/test/must"
+
+	"github.com/hashicorp/nomad-pack/internal/pkg/helper/pointer"
+	"github.com/hashicorp/nomad-pack/sdk/pack"
+)
+
+func TestRunner_ValidateTemplates(t *testing.T) {
+	testJob := api.NewServiceJob("example", "example", "global", 50)
+	testJob.AddDatacenter("dc1")
+	testJob.AddTaskGroup(
+		api.NewTaskGroup("web", 1).AddTask(
+			api.NewTask("server", "docker").Require(&api.Resources{MemoryMB: pointer.Of(512)}),
+		),
+	)
+
+	r := &Runner{
+		parsedTemplates: map[string]ParsedTemplate{
+			"example/templates/example.nomad.tpl": {original: testJob, canonical: testJob},
+		},
+	}
+
+	newRule := func(name, condition string) *pack.Validation {
+		expr, diags := hclsyntax.ParseExpression([]byte(condition), "metadata.hcl", hcl.InitialPos)
+		must.False(t, diags.HasErrors())
+		return &pack.Validation{Name: name, Condition: expr, ErrorMessage: name + " failed"}
+	}
+
+	testCases := []struct {
+		desc        string
+		rules       []*pack.Validation
+		expectedErr []string
+	}{
+		{
+			desc:  "no rules",
+			rules: nil,
+		},
+		{
+			desc: "satisfied rules",
+			rules: []*pack.Validation{
+				newRule("memory", `max(flatten([for tg in job.TaskGroups : [for t in tg.Tasks : t.Resources.MemoryMB]])...) <= 1024`),
+				newRule("datacenter", `contains(job.Datacenters, "dc1")`),
+			},
+		},
+		{
+			desc: "unsatisfied rule",
+			rules: []*pack.Validation{
+				newRule("memory", `max(flatten([for tg in job.TaskGroups : [for t in tg.Tasks : t.Resources.MemoryMB]])...) <= 256`),
+			},
+			expectedErr: []string{`validation "memory": memory failed`},
+		},
+		{
+			desc: "non-bool condition",
+			rules: []*pack.Validation{
+				newRule("name", `job.Name`),
+			},
+			expectedErr: []string{`validation "name": condition must be a bool, got string`},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			errs := r.ValidateTemplates(tc.rules)
+			must.Len(t, len(tc.expectedErr), errs)
+			for i, err := range errs {
+				must.Eq(t, validationSubjPackValidation, err.Subject)
+				must.EqError(t, err.Err, tc.expectedErr[i])
+			}
+		})
+	}
+}
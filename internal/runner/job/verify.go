@@ -0,0 +1,77 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package job
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/hashicorp/nomad/api"
+
+	"github.com/hashicorp/nomad-pack/internal/pkg/errors"
+	"github.com/hashicorp/nomad-pack/terminal"
+)
+
+// diffType* are the types of api.JobDiff returned by a plan.
+const (
+	diffTypeNone  = "None"
+	diffTypeAdded = "Added"
+)
+
+// VerifyDeployment satisfies the VerifyDeployment function of the
+// runner.Runner interface.
+func (r *Runner) VerifyDeployment(ui terminal.UI, errCtx *errors.UIErrorContext) ([]string, []*errors.WrappedUIContext) {
+	if len(r.parsedTemplates) < 1 {
+		return nil, []*errors.WrappedUIContext{newNoParsedTemplatesError("failed to verify deployment", errCtx)}
+	}
+
+	var (
+		drifted      []string
+		outputErrors []*errors.WrappedUIContext
+	)
+
+	// The plan only compares the job with the running version, so the
+	// scheduler dry-run is not needed.
+	planOpts := &api.PlanOptions{Diff: true}
+
+	for _, tplName := range slices.Sorted(maps.Keys(r.parsedTemplates)) {
+		parsedJob := r.parsedTemplates[tplName]
+		jobName := parsedJob.GetName()
+
+		tplErrorContext := errCtx.Copy()
+		tplErrorContext.Add(errors.UIContextPrefixTemplateName, tplName)
+		tplErrorContext.Add(errors.UIContextPrefixJobName, jobName)
+
+		planResponse, _, err := r.client.Jobs().PlanOpts(parsedJob.Job(), planOpts, r.newWriteOptsFromJob(parsedJob))
+		if err != nil {
+			outputErrors = append(outputErrors, &errors.WrappedUIContext{
+				Err:     err,
+				Subject: "failed to perform plan",
+				Context: tplErrorContext,
+			})
+			continue
+		}
+
+		switch diff := planResponse.Diff; {
+		case diff == nil:
+			outputErrors = append(outputErrors, &errors.WrappedUIContext{
+				Err:     fmt.Errorf("plan of job %q did not return a diff", jobName),
+				Subject: "failed to perform plan",
+				Context: tplErrorContext,
+			})
+		case diff.Type == diffTypeNone:
+			ui.Success(fmt.Sprintf("Job %q matches the pack", jobName))
+		case diff.Type == diffTypeAdded:
+			ui.Warning(fmt.Sprintf("Job %q is rendered by the pack but is not running", jobName))
+			drifted = append(drifted, jobName)
+		default:
+			ui.Warning(fmt.Sprintf("Job %q has drifted from the pack", jobName))
+			formatJobDiff(*diff, r.cfg.PlanConfig.Verbose, ui)
+			drifted = append(drifted, jobName)
+		}
+	}
+
+	return drifted, outputErrors
+}
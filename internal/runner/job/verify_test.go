@@ -0,0 +1,67 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package job
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/shoenig/test/must"
+
+	"github.com/hashicorp/nomad-pack/internal/pkg/errors"
+	"github.com/hashicorp/nomad-pack/terminal"
+)
+
+func TestRunner_VerifyDeployment(t *testing.T) {
+	// The fake Nomad server returns a diff for each job depending on its ID.
+	diffs := map[string]*api.JobDiff{
+		"same":    {Type: diffTypeNone, ID: "same"},
+		"new":     {Type: diffTypeAdded, ID: "new"},
+		"changed": {Type: "Edited", ID: "changed", Fields: []*api.FieldDiff{{Type: "Edited", Name: "Priority", Old: "70", New: "50"}}},
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v1/job/"), "/plan")
+		diff, ok := diffs[id]
+		if !ok {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(&api.JobPlanResponse{Diff: diff})
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := api.NewClient(&api.Config{Address: srv.URL})
+	must.NoError(t, err)
+
+	parsed := make(map[string]ParsedTemplate)
+	for _, id := range []string{"same", "new", "changed", "failed"} {
+		j := api.NewServiceJob(id, id, "global", 50)
+		parsed[id+".nomad.tpl"] = ParsedTemplate{original: j, canonical: j}
+	}
+
+	r := &Runner{
+		client:          client,
+		cfg:             &CLIConfig{PlanConfig: &PlanCLIConfig{Diff: true}},
+		parsedTemplates: parsed,
+	}
+
+	var out bytes.Buffer
+	ui := terminal.NonInteractiveUI(context.Background(), terminal.WithWriters(&out, &out))
+
+	drifted, errs := r.VerifyDeployment(ui, errors.NewUIErrorContext())
+	must.Eq(t, []string{"changed", "new"}, drifted)
+	must.Len(t, 1, errs)
+	must.Eq(t, "failed to perform plan", errs[0].Subject)
+
+	must.StrContains(t, out.String(), `Job "same" matches the pack`)
+	must.StrContains(t, out.String(), `Job "new" is rendered by the pack but is not running`)
+	must.StrContains(t, out.String(), `Job "changed" has drifted from the pack`)
+	must.StrContains(t, out.String(), `Priority: "70" => "50"`)
+}
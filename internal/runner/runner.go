@@ -5,6 +5,7 @@ package runner
 
 import (
 	"github.com/hashicorp/nomad-pack/internal/pkg/errors"
+	"github.com/hashicorp/nomad-pack/sdk/pack"
 	"github.com/hashicorp/nomad-pack/terminal"
 )
 
@@ -17,6 +18,11 @@ type Config struct {
 	PathPath       string
 	PackRef        string
 	RegistryName   string
+
+	// Variables are the values of the pack variables, keyed by their name
+	// relative to the parent pack. They are stored with the deployed objects,
+	// so the values a deployment used can be retrieved later.
+	Variables map[string]string
 }
 
 // PlanCode* is the set of expected error codes that Runner.PlanDeployment
@@ -96,4 +102,14 @@ type Runner interface {
 	// implementor should store these to avoid having to do this again when
 	// deploying.
 	ParseTemplates() []*errors.WrappedUIContext
+
+	// VerifyDeployment compares the templates with the running objects of the
+	// deployment and outputs any differences via the terminal.UI. The names of
+	// the objects which differ, or which are not running, are returned.
+	VerifyDeployment(terminal.UI, *errors.UIErrorContext) ([]string, []*errors.WrappedUIContext)
+
+	// ValidateTemplates evaluates the validation rules declared within the
+	// pack metadata against the parsed templates, returning an error for each
+	// rule which a template does not satisfy.
+	ValidateTemplates([]*pack.Validation) []*errors.WrappedUIContext
 }
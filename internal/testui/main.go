@@ -168,9 +168,7 @@ func (ui *nonInteractiveTestUI) Table(tbl *terminal.Table, opts ...terminal.Opti
 	ui.mu.Lock()
 	defer ui.mu.Unlock()
 
-	table := terminal.TableWithSettings(ui.OutWriter, tbl.Headers)
-	table.Bulk(tbl.Rows)
-	table.Render()
+	terminal.RenderTable(ui.OutWriter, tbl, opts...)
 }
 
 // Debug implements UI
@@ -5,6 +5,10 @@ package pack
 
 import (
 	"errors"
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
 )
 
 // Metadata is the contents of the Pack metadata.hcl file. It contains
@@ -15,6 +19,7 @@ type Metadata struct {
 	Pack         *MetadataPack        `hcl:"pack,block"`
 	Integration  *MetadataIntegration `hcl:"integration,block"`
 	Dependencies []*Dependency        `hcl:"dependency,block"`
+	Validations  []*Validation        `hcl:"validation,block"`
 }
 
 // MetadataApp contains information regarding the application that the pack is
@@ -60,6 +65,11 @@ type MetadataPack struct {
 	// Version is the version of the pack which is acts as a convenience when
 	// managing packs within a registry.
 	Version string `hcl:"version"`
+
+	// Custom holds any other attributes of the pack block, such as the
+	// maintainer, license, or tags of the pack. They are not used by Nomad
+	// Pack, but are output by the info command.
+	Custom map[string]cty.Value `hcl:",remain"`
 }
 
 // MetadataIntegration contains information pertaining to the HashiCorp
@@ -90,6 +100,22 @@ type MetadataIntegration struct {
 	Name string `hcl:"name,optional"`
 }
 
+// Validation is a rule declared by the pack which each rendered job must
+// satisfy before it is planned or run.
+type Validation struct {
+
+	// Name identifies the rule within validation failure output.
+	Name string `hcl:"name,label"`
+
+	// Condition is an expression evaluated against the rendered job, which is
+	// available as the "job" variable. The rule passes when the expression
+	// evaluates to true.
+	Condition hcl.Expression `hcl:"condition"`
+
+	// ErrorMessage is output when the condition is not met.
+	ErrorMessage string `hcl:"error_message"`
+}
+
 // ConvertToMapInterface returns a map[string]any representation of the
 // metadata object. The conversion doesn't take into account empty values and
 // will add them.
@@ -121,6 +147,7 @@ func (md *Metadata) ConvertToMapInterface() map[string]any {
 				"name":    d.Name,
 				"alias":   d.Alias,
 				"source":  d.Source,
+				"ref":     d.Ref,
 				"enabled": d.Enabled,
 			},
 		}
@@ -151,6 +178,21 @@ func (md *Metadata) Validate() error {
 			return err
 		}
 	}
+
+	for _, v := range md.Validations {
+		if err := v.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validate the Validation object to ensure it meets requirements and doesn't
+// contain invalid or incorrect data.
+func (v *Validation) validate() error {
+	if v.ErrorMessage == "" {
+		return fmt.Errorf("validation %q requires an error_message", v.Name)
+	}
 	return nil
 }
 
@@ -98,6 +98,7 @@ func TestMetadata_ConvertToMapInterface(t *testing.T) {
 							"alias":   "",
 							"id":      "dep1",
 							"source":  "",
+							"ref":     "",
 							"enabled": pointerOf(true),
 						},
 					},
@@ -107,6 +108,7 @@ func TestMetadata_ConvertToMapInterface(t *testing.T) {
 							"alias":   "dep2",
 							"id":      "dep2",
 							"source":  "",
+							"ref":     "",
 							"enabled": pointerOf(true),
 						},
 					},
@@ -57,6 +57,12 @@ func printTypeR(t cty.Type) string {
 	}
 }
 
+// FormatValue formats the value in the same way as it would be written in a
+// variable file, such as ["dc1", "dc2"], on a single line.
+func FormatValue(v cty.Value) string {
+	return printDefault(v)
+}
+
 // printDefault recursively prints out a cty.Value specification in a format
 // that matched the way it is defined. This allows us to not have to capture
 // or replicate the original presentation. However, could this be captured in
@@ -5,6 +5,7 @@ package variables
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/hashicorp/nomad-pack/internal/pkg/errors/packdiags"
@@ -41,6 +42,29 @@ type Variable struct {
 	Default    cty.Value
 	hasDefault bool
 
+	// DefaultFrom is an optional reference to a default provider, such as
+	// env:REGION, which the default value is resolved from when parsing. The
+	// Default field is used when the provider does not have a value.
+	DefaultFrom     string
+	defaultProvided bool
+
+	// DynamicDefault is true when the default expression calls a function,
+	// such as timestamp or env, whose result can differ between runs.
+	DynamicDefault bool
+
+	// Deprecated is an optional message, such as "use count instead", which
+	// marks the variable as deprecated. Setting a deprecated variable results
+	// in a warning containing the message.
+	Deprecated string
+
+	// Group is an optional name, such as "networking", which groups related
+	// variables together when the pack information is output.
+	Group string
+
+	// Validations are optional rules, declared using validation blocks, which
+	// the value of the variable must satisfy.
+	Validations []*Validation
+
 	// Type represents the concrete cty type of this variable. If the type is
 	// unable to be parsed into a cty type, it is invalid.
 	Type    cty.Type
@@ -50,15 +74,64 @@ type Variable struct {
 	// value into a Go type value.
 	Value cty.Value
 
+	// ValueSource describes where the value was overridden from, such as
+	// --var or the path of a variable file. It is empty when the variable
+	// uses its default value.
+	ValueSource string
+
+	// ValueFrom is the reference the value was resolved from, such as
+	// vault:secret/data/app#password, when the override passed a reference
+	// rather than the value itself.
+	ValueFrom string
+
+	// Sensitive is true when the value was resolved from a source of secrets,
+	// so it must not be output or stored with the deployed pack.
+	Sensitive bool
+
 	// DeclRange is the position marker of the variable within the file it was
 	// read from. This is used for diagnostics.
 	DeclRange hcl.Range
 }
 
+// Validation is a rule declared within a variable block. The condition is an
+// expression which references the variable as var.<name>, and must evaluate
+// to true for the value of the variable to be valid.
+type Validation struct {
+	Condition    hcl.Expression
+	ErrorMessage string
+
+	// ConditionSource is the condition as it is declared, such as
+	// var.count > 0, which is used when outputting the validation. It is
+	// empty if the source of the variable file is not known.
+	ConditionSource string
+
+	// DeclRange is the position marker of the validation block, which is
+	// used for diagnostics.
+	DeclRange hcl.Range
+}
+
+// Equal returns whether the validations were declared with the same error
+// message at the same position, which implies the same condition.
+func (v *Validation) Equal(ov *Validation) bool {
+	return v.ErrorMessage == ov.ErrorMessage && v.DeclRange == ov.DeclRange
+}
+
 func (v *Variable) SetDescription(d string) { v.Description = d; v.hasDescription = true }
 func (v *Variable) SetDefault(d cty.Value)  { v.Default = d; v.hasDefault = true }
 func (v *Variable) SetType(t cty.Type)      { v.Type = t; v.hasType = true }
 
+// SetProvidedDefault sets the default value to one resolved from the default
+// provider referenced by DefaultFrom.
+func (v *Variable) SetProvidedDefault(d cty.Value) {
+	v.SetDefault(d)
+	v.defaultProvided = true
+}
+
+// HasProvidedDefault returns whether the default value was resolved from the
+// default provider referenced by DefaultFrom, rather than declared within the
+// pack.
+func (v *Variable) HasProvidedDefault() bool { return v.defaultProvided }
+
 func (v *Variable) Equal(ivp *Variable) bool {
 	if v == ivp {
 		return true
@@ -69,9 +142,18 @@ func (v *Variable) Equal(ivp *Variable) bool {
 		cv.hasDescription == ov.hasDescription &&
 		cv.Default == ov.Default &&
 		cv.hasDefault == ov.hasDefault &&
+		cv.DefaultFrom == ov.DefaultFrom &&
+		cv.defaultProvided == ov.defaultProvided &&
+		cv.DynamicDefault == ov.DynamicDefault &&
+		cv.Deprecated == ov.Deprecated &&
+		cv.Group == ov.Group &&
+		slices.EqualFunc(cv.Validations, ov.Validations, (*Validation).Equal) &&
 		cv.Type == ov.Type &&
 		cv.hasType == ov.hasType &&
-		cv.Value == ov.Value
+		cv.Value == ov.Value &&
+		cv.ValueSource == ov.ValueSource &&
+		cv.ValueFrom == ov.ValueFrom &&
+		cv.Sensitive == ov.Sensitive
 
 	return eq
 }
@@ -98,6 +180,12 @@ func (v *Variable) AsOverrideString(pID pack.ID) string {
 	if v.hasType {
 		out.WriteString(fmt.Sprintf("#   type: %s\n", printType(v.Type)))
 	}
+	if v.Deprecated != "" {
+		out.WriteString(fmt.Sprintf("#   deprecated: %s\n", v.Deprecated))
+	}
+	if v.Group != "" {
+		out.WriteString(fmt.Sprintf("#   group: %s\n", v.Group))
+	}
 
 	if v.hasDefault {
 		out.WriteString(fmt.Sprintf("#   default: %s\n", printDefault(v.Default)))
@@ -119,6 +207,8 @@ func (v *Variable) Merge(in *Variable) hcl.Diagnostics {
 
 	if in.Value != cty.NilVal {
 		v.Value = in.Value
+		v.ValueFrom = in.ValueFrom
+		v.Sensitive = in.Sensitive
 	}
 
 	if in.Type != cty.NilType {
@@ -12,6 +12,7 @@ import (
 	"os"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"golang.org/x/term"
 
@@ -30,24 +31,42 @@ type basicUI struct {
 // Returns a UI which will write to the current processes
 // stdout/stderr.
 func ConsoleUI(ctx context.Context) UI {
+	return NewUI(ctx)
+}
+
+// NewUI returns a UI which writes to the configured writers, or to the
+// stdout/stderr of the current process if none are configured. The glint-based
+// UI is only used when stdout is a terminal, so the output written to any
+// other writer is plain text which can be captured.
+func NewUI(ctx context.Context, opts ...UIOption) UI {
+	if isConsole(newUIConfig(opts...).stdout) {
+		return GlintUI(ctx, opts...)
+	}
+	return NonInteractiveUI(ctx, opts...)
+}
+
+// isConsole returns true if the writer is a terminal with a size.
+func isConsole(w io.Writer) bool {
+	if w == color.Output {
+		w = os.Stdout
+	}
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
 	// We do both of these checks because some sneaky environments fool
 	// one or the other and we really only want the glint-based UI in
 	// truly interactive environments.
-	glint := isatty.IsTerminal(os.Stdout.Fd()) && term.IsTerminal(int(os.Stdout.Fd()))
-	if glint {
-		glint = false
-		if c, err := console.ConsoleFromFile(os.Stdout); err == nil {
-			if sz, err := c.Size(); err == nil {
-				glint = sz.Height > 0 && sz.Width > 0
-			}
-		}
+	if !isatty.IsTerminal(f.Fd()) || !term.IsTerminal(int(f.Fd())) {
+		return false
 	}
-
-	if glint {
-		return GlintUI(ctx)
-	} else {
-		return NonInteractiveUI(ctx)
+	c, err := console.ConsoleFromFile(f)
+	if err != nil {
+		return false
 	}
+	sz, err := c.Size()
+	return err == nil && sz.Height > 0 && sz.Width > 0
 }
 
 // Input implements UI
@@ -161,6 +180,13 @@ func (ui *basicUI) NamedValues(rows []NamedValue, opts ...Option) {
 				continue
 			}
 			fmt.Fprintf(tr, "  %s: \t%s\n", row.Name, row.Value)
+		case time.Duration:
+			fmt.Fprintf(tr, "  %s: \t%s\n", row.Name, v)
+		case time.Time:
+			if v.IsZero() {
+				continue
+			}
+			fmt.Fprintf(tr, "  %s: \t%s\n", row.Name, v.Format(time.RFC3339))
 		default:
 			fmt.Fprintf(tr, "  %s: \t%s\n", row.Name, row.Value)
 		}
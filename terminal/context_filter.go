@@ -0,0 +1,95 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package terminal
+
+import (
+	"io"
+	"strings"
+
+	"github.com/hashicorp/nomad-pack/internal/pkg/errors"
+)
+
+// alwaysShownContext are the context prefixes which describe the error itself,
+// rather than where it occurred, so they are output regardless of the keys a
+// ContextFilter allows.
+var alwaysShownContext = []string{
+	errors.UIContextErrorDetail,
+	errors.UIContextErrorSuggestion,
+}
+
+// ContextFilter is a UI which only passes the error context entries whose key
+// is allowed to ErrorWithContext, along with the details and suggestions of
+// the error. The key of an entry is the text before its first colon, such as
+// "Pack Name". All other UI calls are passed straight through to the wrapped
+// UI.
+type ContextFilter struct {
+	UI
+
+	keys map[string]struct{}
+}
+
+// NewContextFilter returns a ContextFilter which wraps the passed UI and
+// allows the context entries with the passed keys. Keys are matched
+// case-insensitively, and dashes or underscores match spaces, so "pack-name"
+// allows the "Pack Name" entry. If no keys are passed, every entry is
+// allowed.
+func NewContextFilter(ui UI, keys []string) *ContextFilter {
+	f := &ContextFilter{UI: ui, keys: make(map[string]struct{}, len(keys))}
+	for _, key := range keys {
+		if key = normalizeContextKey(key); key != "" {
+			f.keys[key] = struct{}{}
+		}
+	}
+	return f
+}
+
+// ErrorWithContext implements UI by removing the context entries which are
+// not allowed before passing the error to the wrapped UI.
+func (f *ContextFilter) ErrorWithContext(err error, sub string, ctx ...string) {
+	f.UI.ErrorWithContext(err, sub, f.Filter(ctx)...)
+}
+
+// Filter returns the context entries which are allowed, in order.
+func (f *ContextFilter) Filter(ctx []string) []string {
+	if len(f.keys) == 0 {
+		return ctx
+	}
+
+	out := make([]string, 0, len(ctx))
+	for _, entry := range ctx {
+		if f.allowed(entry) {
+			out = append(out, entry)
+		}
+	}
+	return out
+}
+
+// Close closes the wrapped UI if it implements io.Closer.
+func (f *ContextFilter) Close() error {
+	if closer, ok := f.UI.(io.Closer); ok && closer != nil {
+		return closer.Close()
+	}
+	return nil
+}
+
+func (f *ContextFilter) allowed(entry string) bool {
+	for _, prefix := range alwaysShownContext {
+		if strings.HasPrefix(entry, prefix) {
+			return true
+		}
+	}
+	key, _, ok := strings.Cut(entry, ":")
+	if !ok {
+		return false
+	}
+	_, ok = f.keys[normalizeContextKey(key)]
+	return ok
+}
+
+// normalizeContextKey returns the key in lowercase, with dashes and
+// underscores replaced by spaces.
+func normalizeContextKey(key string) string {
+	key = strings.NewReplacer("-", " ", "_", " ").Replace(key)
+	return strings.ToLower(strings.TrimSpace(key))
+}
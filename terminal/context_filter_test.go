@@ -0,0 +1,48 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package terminal_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/shoenig/test/must"
+
+	"github.com/hashicorp/nomad-pack/internal/testui"
+	"github.com/hashicorp/nomad-pack/terminal"
+)
+
+func TestContextFilter_Filter(t *testing.T) {
+	ctx := []string{
+		"Details: the value is not a number",
+		"Pack Name: foo",
+		"Pack Path: /tmp/foo",
+		"Template Name: foo/job.nomad.tpl",
+		"Suggestions: set the count variable",
+	}
+
+	f := terminal.NewContextFilter(nil, []string{"pack-name", "TEMPLATE_NAME"})
+	must.Eq(t, []string{
+		"Details: the value is not a number",
+		"Pack Name: foo",
+		"Template Name: foo/job.nomad.tpl",
+		"Suggestions: set the count variable",
+	}, f.Filter(ctx))
+
+	// Without any keys, every entry is allowed.
+	f = terminal.NewContextFilter(nil, nil)
+	must.Eq(t, ctx, f.Filter(ctx))
+}
+
+func TestContextFilter_ErrorWithContext(t *testing.T) {
+	var out bytes.Buffer
+	ui := testui.NonInteractiveTestUI(context.Background(), &out, &out)
+
+	f := terminal.NewContextFilter(ui, []string{"pack name"})
+	f.ErrorWithContext(errors.New("one"), "failed", "Pack Name: foo", "Pack Path: /tmp/foo")
+	must.StrContains(t, out.String(), "Pack Name: foo")
+	must.StrNotContains(t, out.String(), "Pack Path")
+}
@@ -0,0 +1,192 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package terminal
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/hashicorp/nomad-pack/internal/pkg/errors"
+	"github.com/hashicorp/nomad-pack/internal/pkg/helper"
+)
+
+// errorSpecificContext are the context prefixes which describe an individual
+// error rather than the operation that produced it. They are listed under
+// each error when errors are grouped, while all other context is shared by the
+// group.
+var errorSpecificContext = []string{
+	errors.UIContextErrorDetail,
+	errors.UIContextErrorSuggestion,
+	errors.UIContextErrorFilename,
+	errors.UIContextErrorPosition,
+	errors.UIContextPrefixHCLRange,
+}
+
+// ErrorCollector is a UI which holds back calls to ErrorWithContext so that
+// errors produced during a single command run can be output together. Errors
+// that share the same context, such as the pack name, are grouped under a
+// single context block and duplicate errors are only output once. All other
+// UI calls are passed straight through to the wrapped UI.
+//
+// Collected errors are written when Flush or Close is called.
+type ErrorCollector struct {
+	UI
+
+	groups []*errorGroup
+}
+
+// errorGroup is a set of collected errors which share the same context.
+type errorGroup struct {
+	ctx    []string
+	errors []*collectedError
+}
+
+// collectedError is a single error held by an errorGroup along with the
+// subject and context specific to it.
+type collectedError struct {
+	err error
+	sub string
+	ctx []string
+}
+
+// NewErrorCollector returns an ErrorCollector which wraps the passed UI.
+func NewErrorCollector(ui UI) *ErrorCollector {
+	return &ErrorCollector{UI: ui}
+}
+
+// ErrorWithContext implements UI by recording the error for output once the
+// collector is flushed.
+func (c *ErrorCollector) ErrorWithContext(err error, sub string, ctx ...string) {
+	var shared, specific []string
+	for _, entry := range ctx {
+		if isErrorSpecificContext(entry) {
+			specific = append(specific, entry)
+		} else {
+			shared = append(shared, entry)
+		}
+	}
+
+	ce := &collectedError{err: err, sub: sub, ctx: specific}
+
+	key := strings.Join(shared, "\n")
+	for _, g := range c.groups {
+		if strings.Join(g.ctx, "\n") != key {
+			continue
+		}
+		for _, e := range g.errors {
+			if e.equal(ce) {
+				return
+			}
+		}
+		g.errors = append(g.errors, ce)
+		return
+	}
+
+	c.groups = append(c.groups, &errorGroup{ctx: shared, errors: []*collectedError{ce}})
+}
+
+// Len returns the number of errors currently held by the collector.
+func (c *ErrorCollector) Len() int {
+	var n int
+	for _, g := range c.groups {
+		n += len(g.errors)
+	}
+	return n
+}
+
+// Flush outputs all collected errors to the wrapped UI, one block per group,
+// and resets the collector so that it can be reused.
+func (c *ErrorCollector) Flush() {
+	groups := c.groups
+	c.groups = nil
+
+	for _, g := range groups {
+		// A group holding a single error is output exactly as it would have
+		// been without the collector.
+		if len(g.errors) == 1 {
+			e := g.errors[0]
+			c.UI.ErrorWithContext(e.err, e.sub, append(e.ctx, g.ctx...)...)
+			continue
+		}
+		g.output(c.UI)
+	}
+}
+
+// Close flushes any collected errors and closes the wrapped UI if it
+// implements io.Closer.
+func (c *ErrorCollector) Close() error {
+	c.Flush()
+	if closer, ok := c.UI.(io.Closer); ok && closer != nil {
+		return closer.Close()
+	}
+	return nil
+}
+
+// output writes the group to the UI. Each error is written on its own line
+// followed by its specific context, and the shared context is written once at
+// the end of the block.
+func (g *errorGroup) output(ui UI) {
+	sameSubject := true
+	for _, e := range g.errors[1:] {
+		if e.sub != g.errors[0].sub {
+			sameSubject = false
+			break
+		}
+	}
+
+	if sameSubject {
+		ui.Error(fmt.Sprintf("%s (%d errors)", helper.Title(g.errors[0].sub), len(g.errors)))
+	} else {
+		ui.Error(fmt.Sprintf("%d Errors Occurred", len(g.errors)))
+	}
+
+	for _, e := range g.errors {
+		msg := e.err.Error()
+		if !sameSubject {
+			msg = helper.Title(e.sub) + ": " + msg
+		}
+		for i, line := range strings.Split(msg, "\n") {
+			if i == 0 {
+				ui.Error("  Error: " + line)
+				continue
+			}
+			ui.Error("         " + line)
+		}
+		for _, entry := range e.ctx {
+			ui.Error("         " + entry)
+		}
+	}
+
+	if len(g.ctx) == 0 {
+		return
+	}
+
+	ui.Error("  Context:")
+	max := 0
+	for _, entry := range g.ctx {
+		if loc := strings.Index(entry, ":") + 1; loc > max {
+			max = loc
+		}
+	}
+	for _, entry := range g.ctx {
+		padding := max - strings.Index(entry, ":") + 1
+		ui.Error("  " + strings.Repeat(" ", padding) + entry)
+	}
+}
+
+func (e *collectedError) equal(o *collectedError) bool {
+	return e.sub == o.sub &&
+		e.err.Error() == o.err.Error() &&
+		strings.Join(e.ctx, "\n") == strings.Join(o.ctx, "\n")
+}
+
+func isErrorSpecificContext(entry string) bool {
+	for _, prefix := range errorSpecificContext {
+		if strings.HasPrefix(entry, prefix) {
+			return true
+		}
+	}
+	return false
+}
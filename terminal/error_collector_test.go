@@ -0,0 +1,82 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package terminal_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/shoenig/test/must"
+
+	"github.com/hashicorp/nomad-pack/internal/testui"
+	"github.com/hashicorp/nomad-pack/terminal"
+)
+
+func newTestCollector() (*terminal.ErrorCollector, *bytes.Buffer) {
+	var out bytes.Buffer
+	ui := testui.NonInteractiveTestUI(context.Background(), &out, &out)
+	return terminal.NewErrorCollector(ui), &out
+}
+
+func TestErrorCollector_HoldsUntilFlush(t *testing.T) {
+	ec, out := newTestCollector()
+
+	ec.ErrorWithContext(errors.New("one"), "failed", "Pack Name: foo")
+	must.Eq(t, 1, ec.Len())
+	must.Eq(t, "", out.String())
+
+	ec.Flush()
+	must.Zero(t, ec.Len())
+	must.StrContains(t, out.String(), "! Failed\n")
+	must.StrContains(t, out.String(), "  Error: one\n")
+	must.StrContains(t, out.String(), "Pack Name: foo")
+}
+
+func TestErrorCollector_GroupsBySharedContext(t *testing.T) {
+	ec, out := newTestCollector()
+
+	ec.ErrorWithContext(errors.New("one"), "failed to process pack",
+		"HCL Range: a.hcl:1,1-2", "Pack Name: foo")
+	ec.ErrorWithContext(errors.New("two\nsecond line"), "failed to process pack",
+		"HCL Range: a.hcl:5,1-2", "Pack Name: foo")
+	ec.ErrorWithContext(errors.New("two\nsecond line"), "failed to process pack",
+		"HCL Range: a.hcl:5,1-2", "Pack Name: foo")
+	ec.ErrorWithContext(errors.New("three"), "failed to process pack",
+		"Pack Name: bar")
+	must.Eq(t, 3, ec.Len())
+
+	ec.Flush()
+
+	expect := strings.Join([]string{
+		"! Failed To Process Pack (2 errors)",
+		"!   Error: one",
+		"!          HCL Range: a.hcl:1,1-2",
+		"!   Error: two",
+		"!          second line",
+		"!          HCL Range: a.hcl:5,1-2",
+		"!   Context:",
+		"!     Pack Name: foo",
+		"! Failed To Process Pack",
+		"!   Error: three",
+		"!   Context:",
+		"!     Pack Name: bar",
+		"",
+	}, "\n")
+	must.Eq(t, expect, out.String())
+}
+
+func TestErrorCollector_MixedSubjects(t *testing.T) {
+	ec, out := newTestCollector()
+
+	ec.ErrorWithContext(errors.New("one"), "first", "Pack Name: foo")
+	ec.ErrorWithContext(errors.New("two"), "second", "Pack Name: foo")
+	ec.Flush()
+
+	must.StrContains(t, out.String(), "! 2 Errors Occurred\n")
+	must.StrContains(t, out.String(), "!   Error: First: one\n")
+	must.StrContains(t, out.String(), "!   Error: Second: two\n")
+}
@@ -13,6 +13,7 @@ import (
 	"slices"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/bgentry/speakeasy"
 	"github.com/fatih/color"
@@ -24,16 +25,28 @@ import (
 )
 
 type glintUI struct {
-	ctx context.Context
-	d   *glint.Document
-	row []glint.Component
+	ctx    context.Context
+	d      *glint.Document
+	row    []glint.Component
+	stdout io.Writer
+	stderr io.Writer
+
+	// noColor outputs the components without their styles.
+	noColor bool
 }
 
-func GlintUI(ctx context.Context) UI {
+func GlintUI(ctx context.Context, opts ...UIOption) UI {
+	cfg := newUIConfig(opts...)
 	result := &glintUI{
-		d:   glint.New(),
-		row: make([]glint.Component, 0),
-		ctx: ctx,
+		d:       glint.New(),
+		row:     make([]glint.Component, 0),
+		ctx:     ctx,
+		stdout:  cfg.stdout,
+		stderr:  cfg.stderr,
+		noColor: cfg.noColor,
+	}
+	if cfg.stdout != color.Output {
+		result.d.SetRenderer(&glint.TerminalRenderer{Output: cfg.stdout})
 	}
 
 	go result.d.Render(ctx)
@@ -50,8 +63,8 @@ func (ui *glintUI) Input(input *Input) (string, error) {
 
 	// Write the prompt, add a space.
 	ui.Output(input.Prompt, WithStyle(input.Style), WithWriter(&buf))
-	fmt.Fprint(color.Output, strings.TrimRight(buf.String(), "\r\n"))
-	fmt.Fprint(color.Output, " ")
+	fmt.Fprint(ui.stdout, strings.TrimRight(buf.String(), "\r\n"))
+	fmt.Fprint(ui.stdout, " ")
 
 	// Ask for input in a go-routine so that we can ignore it.
 	errCh := make(chan error, 1)
@@ -80,7 +93,7 @@ func (ui *glintUI) Input(input *Input) (string, error) {
 		return line, nil
 	case <-ui.ctx.Done():
 		// Print newline so that any further output starts properly
-		fmt.Fprintln(color.Output)
+		fmt.Fprintln(ui.stdout)
 		return "", ui.ctx.Err()
 	}
 }
@@ -90,6 +103,15 @@ func (ui *glintUI) Interactive() bool {
 	return isatty.IsTerminal(os.Stdin.Fd())
 }
 
+// style applies the style options to the component, unless color is disabled,
+// in which case the component is output as is.
+func (ui *glintUI) style(c glint.Component, opts ...glint.StyleOption) glint.Component {
+	if ui.noColor {
+		return c
+	}
+	return glint.Style(c, opts...)
+}
+
 // Output implements UI
 func (ui *glintUI) Output(msg string, raw ...any) {
 	// Render row and reset
@@ -118,7 +140,7 @@ func (ui *glintUI) Output(msg string, raw ...any) {
 		lines := strings.Split(msg, "\n")
 		if len(lines) > 0 {
 			ui.d.Append(glint.Finalize(
-				glint.Style(
+				ui.style(
 					glint.Text("! "+lines[0]),
 					cs...,
 				),
@@ -172,7 +194,7 @@ func (ui *glintUI) Output(msg string, raw ...any) {
 	}
 
 	ui.d.Append(glint.Finalize(
-		glint.Style(
+		ui.style(
 			glint.Text(msg),
 			cs...,
 		),
@@ -249,7 +271,7 @@ func (ui *glintUI) AppendToRow(msg string, raw ...any) {
 		cs = append(cs, glint.Color("lightYellow"))
 	}
 
-	ui.row = append(ui.row, glint.Style(
+	ui.row = append(ui.row, ui.style(
 		glint.Text(msg),
 		cs...,
 	))
@@ -277,6 +299,13 @@ func (ui *glintUI) NamedValues(rows []NamedValue, opts ...Option) {
 				continue
 			}
 			fmt.Fprintf(tr, "  %s: \t%s\n", row.Name, row.Value)
+		case time.Duration:
+			fmt.Fprintf(tr, "  %s: \t%s\n", row.Name, v)
+		case time.Time:
+			if v.IsZero() {
+				continue
+			}
+			fmt.Fprintf(tr, "  %s: \t%s\n", row.Name, v.Format(time.RFC3339))
 		default:
 			fmt.Fprintf(tr, "  %s: \t%s\n", row.Name, row.Value)
 		}
@@ -294,7 +323,7 @@ func (ui *glintUI) NamedValues(rows []NamedValue, opts ...Option) {
 
 // OutputWriters implements UI
 func (ui *glintUI) OutputWriters() (io.Writer, io.Writer, error) {
-	return os.Stdout, os.Stderr, nil
+	return ui.stdout, ui.stderr, nil
 }
 
 // Status implements UI
@@ -314,9 +343,7 @@ func (ui *glintUI) StepGroup() StepGroup {
 // Table implements UI
 func (ui *glintUI) Table(tbl *Table, opts ...Option) {
 	var buf bytes.Buffer
-	table := TableWithSettings(&buf, tbl.Headers)
-	table.Bulk(tbl.Rows)
-	table.Render()
+	RenderTable(&buf, tbl, opts...)
 	ui.d.Append(glint.Finalize(glint.Text(buf.String())))
 }
 
@@ -340,7 +367,7 @@ func (ui *glintUI) ErrorWithContext(err error, sub string, ctx ...string) {
 	// function in ui.go
 	// Title the error output in red with the subject.
 	d.Append(glint.Layout(
-		glint.Style(
+		ui.style(
 			glint.Text(fmt.Sprintf("! %s\n", helper.Title(sub))),
 			glint.Color("red"),
 		),
@@ -348,7 +375,7 @@ func (ui *glintUI) ErrorWithContext(err error, sub string, ctx ...string) {
 
 	// Add the error string as well as the error type to the output.
 	d.Append(glint.Layout(
-		glint.Style(glint.Text("    Error:   "), glint.Bold()),
+		ui.style(glint.Text("    Error:   "), glint.Bold()),
 		glint.Text(err.Error()),
 	).Row())
 
@@ -375,11 +402,11 @@ func (ui *glintUI) ErrorWithContext(err error, sub string, ctx ...string) {
 				// There is something odd going on if we don't get a 2 split
 				// if we get 1, print the whole thing out.
 				d.Append(glint.Layout(
-					glint.Style(glint.Text("    " + splits[0])),
+					ui.style(glint.Text("    " + splits[0])),
 				).Row())
 			default:
 				d.Append(glint.Layout(
-					glint.Style(glint.Text("    "+splits[0]+":   "), glint.Bold()),
+					ui.style(glint.Text("    "+splits[0]+":   "), glint.Bold()),
 					glint.Text(strings.Join(splits[1:], ": "))).Row())
 			}
 		}
@@ -392,14 +419,14 @@ func (ui *glintUI) ErrorWithContext(err error, sub string, ctx ...string) {
 	// this within the ctx loop.
 	if len(ctx) > 0 {
 		d.Append(glint.Layout(
-			glint.Style(glint.Text("    Context: "), glint.Bold()),
+			ui.style(glint.Text("    Context: "), glint.Bold()),
 		).Row())
 	}
 
 	// Iterate the addition context items and append these to the output.
 	for _, additionCTX := range ctx {
 		d.Append(glint.Layout(
-			glint.Style(glint.Text(fmt.Sprintf("        - %s", additionCTX))),
+			ui.style(glint.Text(fmt.Sprintf("        - %s", additionCTX))),
 		).Row())
 	}
 	// Add a new line
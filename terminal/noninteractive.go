@@ -8,14 +8,14 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"os"
 	"regexp"
 	"slices"
 	"strings"
 	"sync"
 	"text/tabwriter"
+	"time"
+	"unicode/utf8"
 
-	"github.com/fatih/color"
 	"github.com/mitchellh/go-wordwrap"
 
 	"github.com/hashicorp/nomad-pack/internal/pkg/errors"
@@ -23,11 +23,25 @@ import (
 )
 
 type nonInteractiveUI struct {
-	mu sync.Mutex
-}
-
-func NonInteractiveUI(ctx context.Context) UI {
-	result := &nonInteractiveUI{}
+	mu          sync.Mutex
+	stdout      io.Writer
+	stderr      io.Writer
+	bufferSteps bool
+	prefixes    StylePrefixes
+	noColor     bool
+	quiet       bool
+}
+
+func NonInteractiveUI(ctx context.Context, opts ...UIOption) UI {
+	cfg := newUIConfig(opts...)
+	result := &nonInteractiveUI{
+		stdout:      cfg.stdout,
+		stderr:      cfg.stderr,
+		bufferSteps: cfg.bufferSteps,
+		prefixes:    cfg.prefixes,
+		noColor:     cfg.noColor,
+		quiet:       cfg.quiet,
+	}
 	return result
 }
 
@@ -44,21 +58,19 @@ func (ui *nonInteractiveUI) Interactive() bool {
 func (ui *nonInteractiveUI) Output(msg string, raw ...any) {
 	ui.mu.Lock()
 	defer ui.mu.Unlock()
-	msg, style, w := Interpret(msg, raw...)
+	msg, style, w := interpret(nil, msg, raw...)
+	w, ok := ui.writer(style, w)
+	if !ok {
+		return
+	}
 
 	switch style {
 	case DebugStyle:
 		msg = colorDebug.Sprintf("debug: %s\n", msg)
 	case HeaderStyle:
-		msg = "\n» " + msg
+		msg = "\n" + ui.prefixes.Header + msg
 	case ErrorStyle, ErrorBoldStyle:
-		lines := strings.Split(msg, "\n")
-		if len(lines) > 0 {
-			fmt.Fprintln(w, "! "+lines[0])
-			for _, line := range lines[1:] {
-				fmt.Fprintln(w, "  "+line)
-			}
-		}
+		ui.outputError(w, msg)
 
 		return
 	case WarningStyle, WarningBoldStyle:
@@ -79,23 +91,62 @@ func (ui *nonInteractiveUI) Output(msg string, raw ...any) {
 	fmt.Fprintln(w, msg)
 }
 
+// outputError writes the lines of the error, with the error prefix before the
+// first line and the following lines indented to align with it.
+func (ui *nonInteractiveUI) outputError(w io.Writer, msg string) {
+	lines := strings.Split(msg, "\n")
+	indent := strings.Repeat(" ", utf8.RuneCountInString(ui.prefixes.Error))
+	fmt.Fprintln(w, ui.prefixes.Error+lines[0])
+	for _, line := range lines[1:] {
+		fmt.Fprintln(w, indent+line)
+	}
+}
+
+// writer returns the writer the output of the style is written to, which is
+// w when the output was directed to a writer, and stdout otherwise. When the
+// UI is quiet, errors and warnings are written to stderr instead, and the
+// returned bool is false for the styles whose output is dropped.
+func (ui *nonInteractiveUI) writer(style string, w io.Writer) (io.Writer, bool) {
+	if ui.quiet {
+		switch style {
+		case HeaderStyle, InfoStyle, SuccessStyle, SuccessBoldStyle, DebugStyle, TraceStyle:
+			return nil, false
+		case ErrorStyle, ErrorBoldStyle, WarningStyle, WarningBoldStyle:
+			if w == nil {
+				w = ui.stderr
+			}
+		}
+	}
+	if w == nil {
+		w = ui.stdout
+	}
+	return ui.plain(w), true
+}
+
+// plain returns w, or a writer which removes any ANSI escape codes from the
+// output when color is disabled.
+func (ui *nonInteractiveUI) plain(w io.Writer) io.Writer {
+	if !ui.noColor {
+		return w
+	}
+	return &stripAnsiWriter{Next: w}
+}
+
 // TODO: Added purely for compilation purposes. Untested
 func (ui *nonInteractiveUI) AppendToRow(msg string, raw ...any) {
 	ui.mu.Lock()
 	defer ui.mu.Unlock()
-	msg, style, w := Interpret(msg, raw...)
+	msg, style, w := interpret(nil, msg, raw...)
+	w, ok := ui.writer(style, w)
+	if !ok {
+		return
+	}
 
 	switch style {
 	case HeaderStyle:
-		msg = "\n» " + msg
+		msg = "\n" + ui.prefixes.Header + msg
 	case ErrorStyle, ErrorBoldStyle:
-		lines := strings.Split(msg, "\n")
-		if len(lines) > 0 {
-			fmt.Fprintln(w, "! "+lines[0])
-			for _, line := range lines[1:] {
-				fmt.Fprintln(w, "  "+line)
-			}
-		}
+		ui.outputError(w, msg)
 
 		return
 
@@ -118,10 +169,14 @@ func (ui *nonInteractiveUI) AppendToRow(msg string, raw ...any) {
 
 // NamedValues implements UI
 func (ui *nonInteractiveUI) NamedValues(rows []NamedValue, opts ...Option) {
+	if ui.quiet {
+		return
+	}
+
 	ui.mu.Lock()
 	defer ui.mu.Unlock()
 
-	cfg := &config{Writer: color.Output}
+	cfg := &config{Writer: ui.stdout}
 	for _, opt := range opts {
 		opt(cfg)
 	}
@@ -141,43 +196,55 @@ func (ui *nonInteractiveUI) NamedValues(rows []NamedValue, opts ...Option) {
 				continue
 			}
 			fmt.Fprintf(tr, "  %s: \t%s\n", row.Name, row.Value)
+		case time.Duration:
+			fmt.Fprintf(tr, "  %s: \t%s\n", row.Name, v)
+		case time.Time:
+			if v.IsZero() {
+				continue
+			}
+			fmt.Fprintf(tr, "  %s: \t%s\n", row.Name, v.Format(time.RFC3339))
 		default:
 			fmt.Fprintf(tr, "  %s: \t%s\n", row.Name, row.Value)
 		}
 	}
 	tr.Flush()
 
-	fmt.Fprintln(cfg.Writer, buf.String())
+	fmt.Fprintln(ui.plain(cfg.Writer), buf.String())
 }
 
 // OutputWriters implements UI
 func (ui *nonInteractiveUI) OutputWriters() (io.Writer, io.Writer, error) {
-	return os.Stdout, os.Stderr, nil
+	return ui.stdout, ui.stderr, nil
 }
 
 // Status implements UI
 func (ui *nonInteractiveUI) Status() Status {
-	return &nonInteractiveStatus{mu: &ui.mu}
+	if ui.quiet {
+		return &nonInteractiveStatus{mu: &ui.mu, w: io.Discard}
+	}
+	return &nonInteractiveStatus{mu: &ui.mu, w: ui.stdout}
 }
 
 func (ui *nonInteractiveUI) StepGroup() StepGroup {
-	return &nonInteractiveStepGroup{mu: &ui.mu}
+	return &nonInteractiveStepGroup{mu: &ui.mu, w: ui.stdout, buffered: ui.bufferSteps, prefix: ui.prefixes.Step}
 }
 
 // Table implements UI
 func (ui *nonInteractiveUI) Table(tbl *Table, opts ...Option) {
+	if ui.quiet {
+		return
+	}
+
 	ui.mu.Lock()
 	defer ui.mu.Unlock()
 
 	// Build our config and set our options
-	cfg := &config{Writer: color.Output}
+	cfg := &config{Writer: ui.stdout}
 	for _, opt := range opts {
 		opt(cfg)
 	}
 
-	table := TableWithSettings(cfg.Writer, tbl.Headers)
-	table.Bulk(tbl.Rows)
-	table.Render()
+	RenderTable(ui.plain(cfg.Writer), tbl, opts...)
 }
 
 // Debug implements UI
@@ -285,18 +352,19 @@ func (ui *nonInteractiveUI) WarningBold(msg string) {
 
 type nonInteractiveStatus struct {
 	mu *sync.Mutex
+	w  io.Writer
 }
 
 func (s *nonInteractiveStatus) Update(msg string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	fmt.Fprintln(color.Output, msg)
+	fmt.Fprintln(s.w, msg)
 }
 
 func (s *nonInteractiveStatus) Step(status, msg string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	fmt.Fprintf(color.Output, "%s: %s\n", textStatus[status], msg)
+	fmt.Fprintf(s.w, "%s: %s\n", textStatus[status], msg)
 }
 
 func (s *nonInteractiveStatus) Close() error {
@@ -305,14 +373,25 @@ func (s *nonInteractiveStatus) Close() error {
 
 type nonInteractiveStepGroup struct {
 	mu     *sync.Mutex
+	w      io.Writer
 	wg     sync.WaitGroup
 	closed bool
+
+	// buffered is true when the output of each step is held until it is
+	// done, as configured using WithBufferedSteps.
+	buffered bool
+
+	// prefix is output before the message of each step.
+	prefix string
 }
 
 // Start a step in the output
 func (f *nonInteractiveStepGroup) Add(str string, args ...any) Step {
 	// Build our step
-	step := &nonInteractiveStep{mu: f.mu}
+	step := &nonInteractiveStep{mu: f.mu, w: f.w, prefix: f.prefix}
+	if f.buffered {
+		step.buf = new(bytes.Buffer)
+	}
 
 	// Setup initial status
 	step.Update(str, args...)
@@ -344,18 +423,33 @@ func (f *nonInteractiveStepGroup) Wait() {
 
 type nonInteractiveStep struct {
 	mu   *sync.Mutex
+	w    io.Writer
 	wg   *sync.WaitGroup
 	done bool
+
+	// buf holds the output of the step until it is done, when the step group
+	// is buffered. It is guarded by mu.
+	buf *bytes.Buffer
+
+	prefix string
 }
 
 func (f *nonInteractiveStep) TermOutput() io.Writer {
-	return &stripAnsiWriter{Next: color.Output}
+	if f.buf != nil {
+		return &stripAnsiWriter{Next: &lockedWriter{mu: f.mu, w: f.buf}}
+	}
+	return &stripAnsiWriter{Next: f.w}
 }
 
 func (f *nonInteractiveStep) Update(str string, args ...any) {
 	f.mu.Lock()
 	defer f.mu.Unlock()
-	fmt.Fprintln(color.Output, "-> "+fmt.Sprintf(str, args...))
+
+	w := f.w
+	if f.buf != nil {
+		w = f.buf
+	}
+	fmt.Fprintln(w, f.prefix+fmt.Sprintf(str, args...))
 }
 
 func (f *nonInteractiveStep) Status(status string) {}
@@ -371,14 +465,35 @@ func (f *nonInteractiveStep) Done() {
 	// Set done
 	f.done = true
 
-	// Unset the waitgroup
-	f.wg.Done()
+	// Write the buffered output at once, while holding the lock shared with
+	// the other steps, so it is not interleaved with their output.
+	if f.buf != nil {
+		_, _ = f.buf.WriteTo(f.w)
+	}
+
+	// Unset the waitgroup, unless the step was added after the group was
+	// closed.
+	if f.wg != nil {
+		f.wg.Done()
+	}
 }
 
 func (f *nonInteractiveStep) Abort() {
 	f.Done()
 }
 
+// lockedWriter serializes the writes to w using mu.
+type lockedWriter struct {
+	mu *sync.Mutex
+	w  io.Writer
+}
+
+func (w *lockedWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.w.Write(p)
+}
+
 type stripAnsiWriter struct {
 	Next io.Writer
 }
@@ -0,0 +1,81 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package terminal
+
+import (
+	"io"
+	"sync"
+)
+
+// SummaryRecorder is a UI which records the warnings and counts the errors
+// output during a command run, so that they can be summarized once it
+// completes. All calls are passed straight through to the wrapped UI.
+type SummaryRecorder struct {
+	UI
+
+	mu       sync.Mutex
+	warnings []string
+	errors   int
+}
+
+// NewSummaryRecorder returns a SummaryRecorder which wraps the passed UI.
+func NewSummaryRecorder(ui UI) *SummaryRecorder {
+	return &SummaryRecorder{UI: ui}
+}
+
+// Error implements UI by counting the error before outputting it.
+func (r *SummaryRecorder) Error(msg string) {
+	r.mu.Lock()
+	r.errors++
+	r.mu.Unlock()
+	r.UI.Error(msg)
+}
+
+// ErrorWithContext implements UI by counting the error before outputting it.
+func (r *SummaryRecorder) ErrorWithContext(err error, sub string, ctx ...string) {
+	r.mu.Lock()
+	r.errors++
+	r.mu.Unlock()
+	r.UI.ErrorWithContext(err, sub, ctx...)
+}
+
+// Warning implements UI by recording the warning before outputting it.
+func (r *SummaryRecorder) Warning(msg string) {
+	r.record(msg)
+	r.UI.Warning(msg)
+}
+
+// WarningBold implements UI by recording the warning before outputting it.
+func (r *SummaryRecorder) WarningBold(msg string) {
+	r.record(msg)
+	r.UI.WarningBold(msg)
+}
+
+// Warnings returns the warnings output so far, in order.
+func (r *SummaryRecorder) Warnings() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string{}, r.warnings...)
+}
+
+// Errors returns the number of errors output so far.
+func (r *SummaryRecorder) Errors() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.errors
+}
+
+// Close closes the wrapped UI if it implements io.Closer.
+func (r *SummaryRecorder) Close() error {
+	if closer, ok := r.UI.(io.Closer); ok && closer != nil {
+		return closer.Close()
+	}
+	return nil
+}
+
+func (r *SummaryRecorder) record(msg string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.warnings = append(r.warnings, msg)
+}
@@ -0,0 +1,36 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package terminal_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/shoenig/test/must"
+
+	"github.com/hashicorp/nomad-pack/internal/testui"
+	"github.com/hashicorp/nomad-pack/terminal"
+)
+
+func TestSummaryRecorder(t *testing.T) {
+	var out bytes.Buffer
+	ui := testui.NonInteractiveTestUI(context.Background(), &out, &out)
+
+	r := terminal.NewSummaryRecorder(ui)
+	r.Warning("first")
+	r.Info("not recorded")
+	r.WarningBold("second")
+	r.Error("one")
+	r.ErrorWithContext(errors.New("two"), "failed", "Pack Name: foo")
+
+	must.Eq(t, []string{"first", "second"}, r.Warnings())
+	must.Eq(t, 2, r.Errors())
+
+	// Every call is still output by the wrapped UI.
+	must.StrContains(t, out.String(), "first")
+	must.StrContains(t, out.String(), "not recorded")
+	must.StrContains(t, out.String(), "Pack Name: foo")
+}
@@ -4,13 +4,28 @@
 package terminal
 
 import (
+	"bufio"
+	"cmp"
 	"io"
+	"strconv"
+	"strings"
 
 	"github.com/olekukonko/tablewriter"
+	"github.com/olekukonko/tablewriter/pkg/twwidth"
 	"github.com/olekukonko/tablewriter/renderer"
 	"github.com/olekukonko/tablewriter/tw"
 )
 
+const (
+	// StreamRowThreshold is the number of rows above which a Table is
+	// streamed, as if WithStreaming was passed.
+	StreamRowThreshold = 10000
+
+	// streamSampleRows is the number of rows used to size the columns of a
+	// streamed Table.
+	streamSampleRows = 100
+)
+
 // Passed to UI.Table to provide a nicely formatted table.
 type Table struct {
 	Headers []string
@@ -24,7 +39,33 @@ func NewTable(headers ...string) *Table {
 	}
 }
 
-func TableWithSettings(writer io.Writer, headers []string) *tablewriter.Table {
+// Alignment is the horizontal alignment of the cells within a table column.
+type Alignment int
+
+const (
+	// AlignDefault leaves the alignment of the column to the table renderer.
+	AlignDefault Alignment = iota
+	AlignLeft
+	AlignRight
+	AlignCenter
+)
+
+func (a Alignment) tw() tw.Align {
+	switch a {
+	case AlignLeft:
+		return tw.AlignLeft
+	case AlignRight:
+		return tw.AlignRight
+	case AlignCenter:
+		return tw.AlignCenter
+	default:
+		return tw.AlignNone
+	}
+}
+
+// TableWithSettings returns a tablewriter.Table using the nomad-pack table
+// style. Any alignments passed are applied to the column at the same index.
+func TableWithSettings(writer io.Writer, headers []string, alignments ...Alignment) *tablewriter.Table {
 	table := tablewriter.NewTable(writer,
 		tablewriter.WithRenderer(renderer.NewBlueprint(tw.Rendition{
 			Borders: tw.BorderNone,
@@ -33,7 +74,262 @@ func TableWithSettings(writer io.Writer, headers []string) *tablewriter.Table {
 	)
 	table.Configure(func(config *tablewriter.Config) {
 		config.Row.Formatting.AutoWrap = tw.WrapNone
+		if len(alignments) > 0 {
+			perColumn := make([]tw.Align, len(alignments))
+			for i, a := range alignments {
+				perColumn[i] = a.tw()
+			}
+			config.Row.Alignment.PerColumn = perColumn
+		}
 	})
 	table.Header(headers)
 	return table
 }
+
+// RenderTable writes the table to the writer, honoring any table specific
+// options such as WithColumnAlignment. It is shared by the UI implementations
+// so tables are output consistently regardless of the UI in use.
+func RenderTable(w io.Writer, tbl *Table, opts ...Option) {
+	cfg := &config{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	if cfg.Markdown {
+		renderMarkdownTable(w, tbl, cfg.Alignments)
+		return
+	}
+
+	if cfg.CSV != nil {
+		renderCSVTable(w, tbl, *cfg.CSV)
+		return
+	}
+
+	// Separators made up of spaces are ambiguous, so the columns are aligned
+	// as usual.
+	if strings.Trim(cfg.Separator, " ") != "" {
+		renderSeparatedTable(w, tbl, cfg.Separator)
+		return
+	}
+
+	if cfg.Stream || len(tbl.Rows) > StreamRowThreshold {
+		renderStreamedTable(w, tbl, cfg.Alignments)
+		return
+	}
+
+	table := TableWithSettings(w, tbl.Headers, cfg.Alignments...)
+	table.Bulk(tbl.Rows)
+	table.Render()
+}
+
+// renderSeparatedTable writes the table with the cells of each row, including
+// the headers, joined by the separator. Rows are written as they are joined,
+// so the table is never buffered as a whole.
+func renderSeparatedTable(w io.Writer, tbl *Table, sep string) {
+	bw := bufio.NewWriter(w)
+	_, _ = bw.WriteString(strings.Join(tbl.Headers, sep) + "\n")
+	for _, row := range tbl.Rows {
+		_, _ = bw.WriteString(strings.Join(row, sep) + "\n")
+	}
+	_ = bw.Flush()
+}
+
+// CSVDialect controls how a Table is formatted as CSV. The zero value formats
+// the table as described by RFC 4180, except that lines end with \n.
+type CSVDialect struct {
+	// Delimiter separates the fields of each record. Defaults to a comma.
+	Delimiter rune
+
+	// AlwaysQuote quotes every field, rather than only the fields which
+	// contain the delimiter, a quote, or a line break.
+	AlwaysQuote bool
+
+	// CRLF ends each record with \r\n rather than \n.
+	CRLF bool
+}
+
+// renderCSVTable writes the table as CSV, with the headers as the first
+// record. Like renderSeparatedTable, each record is written as it is
+// formatted. The encoding/csv writer is not used as it cannot quote every
+// field.
+func renderCSVTable(w io.Writer, tbl *Table, d CSVDialect) {
+	delim := string(cmp.Or(d.Delimiter, ','))
+	eol := "\n"
+	if d.CRLF {
+		eol = "\r\n"
+	}
+
+	bw := bufio.NewWriter(w)
+	writeRecord := func(fields []string) {
+		for i, field := range fields {
+			if i > 0 {
+				_, _ = bw.WriteString(delim)
+			}
+			if d.AlwaysQuote || csvFieldNeedsQuotes(field, delim) {
+				field = `"` + strings.ReplaceAll(field, `"`, `""`) + `"`
+			}
+			_, _ = bw.WriteString(field)
+		}
+		_, _ = bw.WriteString(eol)
+	}
+
+	writeRecord(tbl.Headers)
+	for _, row := range tbl.Rows {
+		writeRecord(row)
+	}
+	_ = bw.Flush()
+}
+
+// csvFieldNeedsQuotes returns whether the field must be quoted to be read
+// back as is. As with encoding/csv, fields with a leading space are quoted so
+// they are not trimmed by lenient readers.
+func csvFieldNeedsQuotes(field, delim string) bool {
+	return strings.ContainsAny(field, delim+"\"\r\n") || strings.HasPrefix(field, " ")
+}
+
+// renderMarkdownTable writes the table as a GitHub Flavored Markdown table,
+// with a separator row after the headers marking the alignment of each
+// column. Pipes within the cells are escaped, and line breaks are written as
+// <br> tags, so each row stays on a single line.
+func renderMarkdownTable(w io.Writer, tbl *Table, alignments []Alignment) {
+	bw := bufio.NewWriter(w)
+	writeRow := func(cells []string) {
+		_, _ = bw.WriteString("|")
+		for _, cell := range cells {
+			_, _ = bw.WriteString(" " + markdownCellEscaper.Replace(cell) + " |")
+		}
+		_, _ = bw.WriteString("\n")
+	}
+
+	writeRow(tbl.Headers)
+	separators := make([]string, len(tbl.Headers))
+	for i := range separators {
+		align := AlignDefault
+		if i < len(alignments) {
+			align = alignments[i]
+		}
+		separators[i] = markdownSeparator(align)
+	}
+	writeRow(separators)
+
+	for _, row := range tbl.Rows {
+		writeRow(row)
+	}
+	_ = bw.Flush()
+}
+
+// markdownCellEscaper escapes the characters which would otherwise end a cell
+// or row of a Markdown table.
+var markdownCellEscaper = strings.NewReplacer("|", "\\|", "\r\n", "<br>", "\n", "<br>")
+
+// markdownSeparator returns the cell of the Markdown separator row for a
+// column with the alignment.
+func markdownSeparator(align Alignment) string {
+	switch align {
+	case AlignLeft:
+		return ":---"
+	case AlignRight:
+		return "---:"
+	case AlignCenter:
+		return ":---:"
+	default:
+		return "---"
+	}
+}
+
+// renderStreamedTable writes the table in the same style as TableWithSettings,
+// writing each row as it is formatted. The width of each column is taken from
+// its header and the first streamSampleRows rows, and cells which are wider
+// are written in full rather than truncated.
+func renderStreamedTable(w io.Writer, tbl *Table, alignments []Alignment) {
+	headers := make([]string, len(tbl.Headers))
+	widths := make([]int, len(tbl.Headers))
+	for i, header := range tbl.Headers {
+		headers[i] = tw.Title(header)
+		widths[i] = twwidth.Width(headers[i])
+	}
+	for _, row := range tbl.Rows[:min(len(tbl.Rows), streamSampleRows)] {
+		for i, cell := range row {
+			if i < len(widths) {
+				widths[i] = max(widths[i], twwidth.Width(cell))
+			}
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	writeRow := func(row []string, align func(col int) Alignment) {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			width := 0
+			if i < len(widths) {
+				width = widths[i]
+			}
+			cells[i] = " " + padCell(cell, width, align(i)) + " "
+		}
+		_, _ = bw.WriteString(strings.Join(cells, "|") + "\n")
+	}
+
+	writeRow(headers, func(int) Alignment { return AlignCenter })
+	separators := make([]string, len(widths))
+	for i, width := range widths {
+		separators[i] = strings.Repeat("-", width+2)
+	}
+	_, _ = bw.WriteString(strings.Join(separators, "+") + "\n")
+
+	for _, row := range tbl.Rows {
+		writeRow(row, func(col int) Alignment {
+			if col < len(alignments) {
+				return alignments[col]
+			}
+			return AlignLeft
+		})
+	}
+	_ = bw.Flush()
+}
+
+// padCell pads the cell to the width using the alignment. Cells wider than
+// the width are returned unchanged.
+func padCell(cell string, width int, align Alignment) string {
+	switch align {
+	case AlignRight:
+		return tw.PadLeft(cell, " ", width)
+	case AlignCenter:
+		return tw.PadCenter(cell, " ", width)
+	default:
+		return tw.PadRight(cell, " ", width)
+	}
+}
+
+// DefaultColumnAlignment returns an alignment for each column of the table
+// based on its contents. Columns where every cell is numeric are right aligned
+// so the values line up, all other columns are left aligned.
+func DefaultColumnAlignment(tbl *Table) []Alignment {
+	out := make([]Alignment, len(tbl.Headers))
+	for col := range tbl.Headers {
+		out[col] = AlignLeft
+		if isNumericColumn(tbl, col) {
+			out[col] = AlignRight
+		}
+	}
+	return out
+}
+
+// isNumericColumn returns whether all the non-empty cells of the column can be
+// parsed as a number. Columns with no values are not considered numeric.
+func isNumericColumn(tbl *Table, col int) bool {
+	var found bool
+	for _, row := range tbl.Rows {
+		if col >= len(row) {
+			continue
+		}
+		cell := strings.TrimSpace(row[col])
+		if cell == "" {
+			continue
+		}
+		if _, err := strconv.ParseFloat(cell, 64); err != nil {
+			return false
+		}
+		found = true
+	}
+	return found
+}
@@ -0,0 +1,132 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package terminal
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/shoenig/test/must"
+)
+
+func TestDefaultColumnAlignment(t *testing.T) {
+	tbl := NewTable("Name", "Count", "Empty", "Mixed")
+	tbl.Rows = [][]string{
+		{"example", "1", "", "1"},
+		{"other", "10", "", "ten"},
+	}
+
+	must.Eq(t, []Alignment{AlignLeft, AlignRight, AlignLeft, AlignLeft}, DefaultColumnAlignment(tbl))
+}
+
+func TestRenderTable_ColumnAlignment(t *testing.T) {
+	tbl := NewTable("Name", "Count")
+	tbl.Rows = [][]string{
+		{"a", "1"},
+		{"bbbbbbb", "100"},
+	}
+
+	var buf bytes.Buffer
+	RenderTable(&buf, tbl, WithColumnAlignment([]Alignment{AlignLeft, AlignRight}))
+
+	lines := strings.Split(buf.String(), "\n")
+	must.SliceContains(t, lines, " a       |     1 ")
+	must.SliceContains(t, lines, " bbbbbbb |   100 ")
+}
+
+func TestRenderTable_ColumnSeparator(t *testing.T) {
+	tbl := NewTable("Name", "Count")
+	tbl.Rows = [][]string{
+		{"a", "1"},
+		{"bbbbbbb", "100"},
+	}
+
+	var buf bytes.Buffer
+	RenderTable(&buf, tbl, WithColumnSeparator("\t"))
+	must.Eq(t, "Name\tCount\na\t1\nbbbbbbb\t100\n", buf.String())
+
+	// A space separator keeps the columns aligned.
+	buf.Reset()
+	RenderTable(&buf, tbl, WithColumnSeparator(" "))
+	must.StrContains(t, buf.String(), " bbbbbbb | 100 ")
+}
+
+func TestRenderTable_Streaming(t *testing.T) {
+	tbl := NewTable("Pack Name", "Count")
+	tbl.Rows = [][]string{
+		{"a", "1"},
+		{"bbbbbbb", "100"},
+	}
+
+	// Small tables are streamed in the same style as they are rendered.
+	var rendered, streamed bytes.Buffer
+	alignment := WithColumnAlignment([]Alignment{AlignLeft, AlignRight})
+	RenderTable(&rendered, tbl, alignment)
+	RenderTable(&streamed, tbl, alignment, WithStreaming())
+	must.Eq(t, rendered.String(), streamed.String())
+
+	// Cells wider than the sampled rows are not truncated.
+	for range streamSampleRows {
+		tbl.Rows = append(tbl.Rows, []string{"c", "1"})
+	}
+	tbl.Rows = append(tbl.Rows, []string{"a-very-long-pack-name", "1"})
+
+	streamed.Reset()
+	RenderTable(&streamed, tbl, alignment, WithStreaming())
+	must.StrContains(t, streamed.String(), " a-very-long-pack-name |     1 \n")
+}
+
+func TestRenderTable_CSV(t *testing.T) {
+	tbl := NewTable("Name", "Description")
+	tbl.Rows = [][]string{
+		{"a", "plain"},
+		{"b", `say "hi", then leave`},
+		{"c", ""},
+	}
+
+	var buf bytes.Buffer
+	RenderTable(&buf, tbl, WithCSV(CSVDialect{}), WithColumnSeparator("\t"))
+	must.Eq(t, "Name,Description\na,plain\nb,\"say \"\"hi\"\", then leave\"\nc,\n", buf.String())
+
+	buf.Reset()
+	RenderTable(&buf, tbl, WithCSV(CSVDialect{Delimiter: ';', AlwaysQuote: true, CRLF: true}))
+	must.Eq(t, "\"Name\";\"Description\"\r\n\"a\";\"plain\"\r\n\"b\";\"say \"\"hi\"\", then leave\"\r\n\"c\";\"\"\r\n", buf.String())
+
+	// Fields are only quoted when they contain the delimiter in use.
+	buf.Reset()
+	RenderTable(&buf, tbl, WithCSV(CSVDialect{Delimiter: '\t'}))
+	must.StrContains(t, buf.String(), "b\t\"say \"\"hi\"\", then leave\"\n")
+	tbl.Rows = [][]string{{"d", "x, y"}}
+	buf.Reset()
+	RenderTable(&buf, tbl, WithCSV(CSVDialect{Delimiter: '\t'}))
+	must.Eq(t, "Name\tDescription\nd\tx, y\n", buf.String())
+
+	// Fields containing line breaks are quoted, and the records are read back
+	// as they were by an RFC 4180 reader.
+	tbl.Rows = [][]string{{"e", "line one\nline two"}, {"f", `a "b", c`}}
+	buf.Reset()
+	RenderTable(&buf, tbl, WithCSV(CSVDialect{}))
+	must.StrContains(t, buf.String(), "e,\"line one\nline two\"\n")
+	records, err := csv.NewReader(&buf).ReadAll()
+	must.NoError(t, err)
+	must.Eq(t, append([][]string{tbl.Headers}, tbl.Rows...), records)
+}
+
+func TestRenderTable_Markdown(t *testing.T) {
+	tbl := NewTable("Name", "Count", "Description")
+	tbl.Rows = [][]string{
+		{"a", "1", "plain"},
+		{"b", "10", "x | y\nz"},
+	}
+
+	var buf bytes.Buffer
+	RenderTable(&buf, tbl, WithMarkdownOutput(), WithCSV(CSVDialect{}),
+		WithColumnAlignment([]Alignment{AlignLeft, AlignRight}))
+	must.Eq(t, "| Name | Count | Description |\n"+
+		"| :--- | ---: | --- |\n"+
+		"| a | 1 | plain |\n"+
+		"| b | 10 | x \\| y<br>z |\n", buf.String())
+}
@@ -0,0 +1,231 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package terminal
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+)
+
+// TestMessage is a message output to a TestUI.
+type TestMessage struct {
+	// Style is the style the message was output with, such as ErrorStyle.
+	// It is empty for messages output without a style.
+	Style string
+
+	// Msg is the message with its format arguments interpolated.
+	Msg string
+}
+
+// TestError is an error output to a TestUI with ErrorWithContext.
+type TestError struct {
+	Err     error
+	Subject string
+	Context []string
+}
+
+// TestUI is a UI which records its output in memory, so tests can assert on
+// the output of commands. The output is formatted as the non-interactive UI
+// would format it, and each call is also recorded in a structured form. It is
+// safe for concurrent use.
+type TestUI struct {
+	ui     UI
+	stdout *syncBuffer
+	stderr *syncBuffer
+
+	mu          sync.Mutex
+	messages    []TestMessage
+	errors      []TestError
+	tables      []*Table
+	namedValues [][]NamedValue
+}
+
+// NewTestUI returns a TestUI with empty output.
+func NewTestUI() *TestUI {
+	stdout, stderr := new(syncBuffer), new(syncBuffer)
+	return &TestUI{
+		ui:     NonInteractiveUI(context.Background(), WithWriters(stdout, stderr)),
+		stdout: stdout,
+		stderr: stderr,
+	}
+}
+
+// Stdout returns the formatted output written to stdout.
+func (ui *TestUI) Stdout() string {
+	return ui.stdout.String()
+}
+
+// Stderr returns the formatted output written to stderr.
+func (ui *TestUI) Stderr() string {
+	return ui.stderr.String()
+}
+
+// Messages returns the messages output with Output, AppendToRow, and the
+// styled output functions, such as Error and Header, in the order they were
+// output.
+func (ui *TestUI) Messages() []TestMessage {
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+	return append([]TestMessage(nil), ui.messages...)
+}
+
+// ErrorsWithContext returns the errors output with ErrorWithContext, in the
+// order they were output.
+func (ui *TestUI) ErrorsWithContext() []TestError {
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+	return append([]TestError(nil), ui.errors...)
+}
+
+// Tables returns the tables output with Table, in the order they were output.
+func (ui *TestUI) Tables() []*Table {
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+	return append([]*Table(nil), ui.tables...)
+}
+
+// NamedValueRows returns the rows output by each call to NamedValues, in the
+// order they were output.
+func (ui *TestUI) NamedValueRows() [][]NamedValue {
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+	return append([][]NamedValue(nil), ui.namedValues...)
+}
+
+func (ui *TestUI) recordMessage(msg string, raw ...any) {
+	msg, style, _ := Interpret(msg, raw...)
+
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+	ui.messages = append(ui.messages, TestMessage{Style: style, Msg: msg})
+}
+
+// Input implements UI
+func (ui *TestUI) Input(input *Input) (string, error) {
+	return "", ErrNonInteractive
+}
+
+// Interactive implements UI
+func (ui *TestUI) Interactive() bool {
+	return false
+}
+
+// Output implements UI
+func (ui *TestUI) Output(msg string, raw ...any) {
+	ui.recordMessage(msg, raw...)
+	ui.ui.Output(msg, raw...)
+}
+
+// AppendToRow implements UI
+func (ui *TestUI) AppendToRow(msg string, raw ...any) {
+	ui.recordMessage(msg, raw...)
+	ui.ui.AppendToRow(msg, raw...)
+}
+
+// NamedValues implements UI
+func (ui *TestUI) NamedValues(rows []NamedValue, opts ...Option) {
+	ui.mu.Lock()
+	ui.namedValues = append(ui.namedValues, rows)
+	ui.mu.Unlock()
+
+	ui.ui.NamedValues(rows, opts...)
+}
+
+// OutputWriters implements UI
+func (ui *TestUI) OutputWriters() (io.Writer, io.Writer, error) {
+	return ui.ui.OutputWriters()
+}
+
+// Status implements UI
+func (ui *TestUI) Status() Status {
+	return ui.ui.Status()
+}
+
+// StepGroup implements UI
+func (ui *TestUI) StepGroup() StepGroup {
+	return ui.ui.StepGroup()
+}
+
+// Table implements UI
+func (ui *TestUI) Table(tbl *Table, opts ...Option) {
+	ui.mu.Lock()
+	ui.tables = append(ui.tables, tbl)
+	ui.mu.Unlock()
+
+	ui.ui.Table(tbl, opts...)
+}
+
+// Debug implements UI
+func (ui *TestUI) Debug(msg string) {
+	ui.Output(msg, WithDebugStyle())
+}
+
+// Error implements UI
+func (ui *TestUI) Error(msg string) {
+	ui.Output(msg, WithErrorStyle())
+}
+
+// ErrorWithContext implements UI
+func (ui *TestUI) ErrorWithContext(err error, sub string, ctx ...string) {
+	ui.mu.Lock()
+	ui.errors = append(ui.errors, TestError{
+		Err:     err,
+		Subject: sub,
+		Context: append([]string(nil), ctx...),
+	})
+	ui.mu.Unlock()
+
+	ui.ui.ErrorWithContext(err, sub, ctx...)
+}
+
+// Header implements UI
+func (ui *TestUI) Header(msg string) {
+	ui.Output(msg, WithHeaderStyle())
+}
+
+// Info implements UI
+func (ui *TestUI) Info(msg string) {
+	ui.Output(msg, WithInfoStyle())
+}
+
+// Success implements UI
+func (ui *TestUI) Success(msg string) {
+	ui.Output(msg, WithSuccessStyle())
+}
+
+// Trace implements UI
+func (ui *TestUI) Trace(msg string) {
+	ui.Output(msg, WithTraceStyle())
+}
+
+// Warning implements UI
+func (ui *TestUI) Warning(msg string) {
+	ui.Output(msg, WithWarningStyle())
+}
+
+// WarningBold implements UI
+func (ui *TestUI) WarningBold(msg string) {
+	ui.Output(msg, WithStyle(WarningBoldStyle))
+}
+
+// syncBuffer is a bytes.Buffer which is safe for concurrent use, as steps
+// and statuses write to it independently of the UI.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
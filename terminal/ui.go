@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"os"
 
 	"github.com/fatih/color"
 	"github.com/mitchellh/go-glint"
@@ -102,6 +103,25 @@ type UI interface {
 	WarningBold(string)
 }
 
+// clearScreenSequence moves the cursor to the top left of the terminal and
+// clears the screen.
+const clearScreenSequence = "\033[H\033[2J"
+
+// ClearScreen clears the terminal of an interactive UI, so the following output
+// is drawn in place of the previous output. Non-interactive output is kept, as
+// it is usually written to a log or a pipe.
+func ClearScreen(ui UI) error {
+	if !ui.Interactive() {
+		return nil
+	}
+	stdout, _, err := ui.OutputWriters()
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(stdout, clearScreenSequence)
+	return err
+}
+
 // StepGroup is a group of steps (that may be concurrent).
 type StepGroup interface {
 	// Start a step in the output with the arguments making up the initial message
@@ -138,6 +158,12 @@ type Step interface {
 
 // Interpret decomposes the msg and arguments into the message, style, and writer
 func Interpret(msg string, raw ...any) (string, string, io.Writer) {
+	return interpret(color.Output, msg, raw...)
+}
+
+// interpret is Interpret with w as the writer used unless the options specify
+// another.
+func interpret(w io.Writer, msg string, raw ...any) (string, string, io.Writer) {
 	// Build our args and options
 	var args []any
 	var opts []Option
@@ -153,7 +179,7 @@ func Interpret(msg string, raw ...any) (string, string, io.Writer) {
 	msg = fmt.Sprintf(msg, args...)
 
 	// Build our config and set our options
-	cfg := &config{Writer: color.Output}
+	cfg := &config{Writer: w}
 	for _, opt := range opts {
 		opt(cfg)
 	}
@@ -190,6 +216,25 @@ type config struct {
 
 	// The style the output should take on
 	Style string
+
+	// Alignments are the per-column alignments used when outputting a Table.
+	Alignments []Alignment
+
+	// Separator, when set, is output between the columns of a Table instead
+	// of aligning the columns with padding.
+	Separator string
+
+	// Stream writes the rows of a Table as they are formatted, rather than
+	// buffering the whole table, at the cost of approximate alignment.
+	Stream bool
+
+	// CSV, when set, outputs a Table as CSV using the dialect. It takes
+	// precedence over Separator.
+	CSV *CSVDialect
+
+	// Markdown outputs a Table as a GitHub Flavored Markdown table. It takes
+	// precedence over CSV and Separator.
+	Markdown bool
 }
 
 // Option controls output styling.
@@ -252,11 +297,144 @@ func WithStyle(style string) Option {
 	}
 }
 
+// WithColumnAlignment sets the alignment of the columns when outputting a
+// Table. Each alignment applies to the column at the same index and columns
+// without an alignment use the default.
+func WithColumnAlignment(alignments []Alignment) Option {
+	return func(c *config) { c.Alignments = alignments }
+}
+
+// WithColumnSeparator specifies the separator to output between the columns
+// of a Table. Columns are not padded or aligned when a separator other than a
+// space is used, which suits output that is processed by other tools.
+func WithColumnSeparator(sep string) Option {
+	return func(c *config) { c.Separator = sep }
+}
+
+// WithCSV outputs a Table as CSV, with the headers as the first record,
+// formatted using the dialect.
+func WithCSV(d CSVDialect) Option {
+	return func(c *config) { c.CSV = &d }
+}
+
+// WithMarkdownOutput outputs a Table as a GitHub Flavored Markdown table,
+// for embedding in documentation. The alignment of each column set using
+// WithColumnAlignment is included in the separator row.
+func WithMarkdownOutput() Option {
+	return func(c *config) { c.Markdown = true }
+}
+
+// WithStreaming writes the rows of a Table incrementally, rather than
+// buffering the whole table, so memory use stays bounded for very large
+// tables. The columns are sized using the first rows, so a longer cell in a
+// later row pushes the rest of its row out of alignment.
+func WithStreaming() Option {
+	return func(c *config) { c.Stream = true }
+}
+
 // WithWriter specifies the writer for the output.
 func WithWriter(w io.Writer) Option {
 	return func(c *config) { c.Writer = w }
 }
 
+// uiConfig is the configuration of a UI.
+type uiConfig struct {
+	stdout io.Writer
+	stderr io.Writer
+
+	// bufferSteps holds the output of each step until it is done.
+	bufferSteps bool
+
+	// prefixes are output before the lines of the non-interactive UI.
+	prefixes StylePrefixes
+
+	// noColor disables colored output.
+	noColor bool
+
+	// quiet drops all but the errors and warnings of the non-interactive UI.
+	quiet bool
+}
+
+// UIOption configures a UI when it is created.
+type UIOption func(*uiConfig)
+
+// WithWriters specifies the writers the UI outputs to, rather than the
+// stdout/stderr of the current process. This allows the output to be captured
+// when embedding nomad-pack.
+func WithWriters(stdout, stderr io.Writer) UIOption {
+	return func(c *uiConfig) {
+		c.stdout = stdout
+		c.stderr = stderr
+	}
+}
+
+// WithBufferedSteps holds the output of each step of a StepGroup, including
+// the output written to its TermOutput, until the step is done, and then
+// writes it at once. The output of concurrent steps is then not interleaved,
+// and is ordered by the time each step completes. It only applies to the
+// non-interactive UI, as the interactive UI redraws each step in place.
+func WithBufferedSteps() UIOption {
+	return func(c *uiConfig) { c.bufferSteps = true }
+}
+
+// StylePrefixes are the prefixes the non-interactive UI outputs before
+// headers, errors and steps, which stand in for the styling of the
+// interactive UI. An empty prefix outputs the line as is.
+type StylePrefixes struct {
+	Header string
+	Error  string
+	Step   string
+}
+
+// DefaultStylePrefixes are the prefixes used unless WithStylePrefixes is set.
+var DefaultStylePrefixes = StylePrefixes{
+	Header: "» ",
+	Error:  "! ",
+	Step:   "-> ",
+}
+
+// WithStylePrefixes replaces the prefixes the non-interactive UI outputs
+// before headers, errors and steps, such as when the output is parsed by
+// tools which expect plain lines. It has no effect on the interactive UI.
+func WithStylePrefixes(p StylePrefixes) UIOption {
+	return func(c *uiConfig) { c.prefixes = p }
+}
+
+// WithNoColor disables colored output, such as when the output is captured by
+// a log. Color is also disabled when the NO_COLOR environment variable is set.
+// The color package is disabled globally, and any escape codes which remain
+// in the messages, tables, and named values output by the non-interactive UI
+// are removed.
+func WithNoColor() UIOption {
+	return func(c *uiConfig) { c.noColor = true }
+}
+
+// WithQuiet drops the output of the non-interactive UI other than errors,
+// warnings, and unstyled messages, such as when only the exit code of a command
+// is of interest. Headers, info, success, debug, and trace messages are
+// dropped, as are tables, named values, and status updates. Errors and
+// warnings are written to stderr, so failures are still visible. It has no
+// effect on the interactive UI.
+func WithQuiet() UIOption {
+	return func(c *uiConfig) { c.quiet = true }
+}
+
+func newUIConfig(opts ...UIOption) *uiConfig {
+	cfg := &uiConfig{
+		stdout:   color.Output,
+		stderr:   color.Error,
+		prefixes: DefaultStylePrefixes,
+		noColor:  os.Getenv("NO_COLOR") != "",
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.noColor {
+		color.NoColor = true
+	}
+	return cfg
+}
+
 func ErrorWithContext(err error, sub string, ctx ...string) {
 
 	// Create a new glint document.
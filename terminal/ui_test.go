@@ -5,9 +5,15 @@ package terminal
 
 import (
 	"bytes"
+	"context"
+	"errors"
+	"fmt"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/fatih/color"
 	"github.com/shoenig/test/must"
 )
 
@@ -61,6 +67,24 @@ func TestNamedValues_server(t *testing.T) {
 	must.Eq(t, expected, buf.String())
 }
 
+func TestNamedValues_time(t *testing.T) {
+	var stdout bytes.Buffer
+	ui := NonInteractiveUI(context.Background(), WithWriters(&stdout, &stdout))
+	ui.NamedValues([]NamedValue{
+		{"Age", 200 * time.Second},
+		{"Deployed", time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)},
+		{"Stopped", time.Time{}},
+	})
+
+	expected := `
+       Age: 3m20s
+  Deployed: 2024-05-01T12:30:00Z
+
+`
+
+	must.Eq(t, strings.TrimLeft(expected, "\n"), stdout.String())
+}
+
 func TestStatusStyle(t *testing.T) {
 	var buf bytes.Buffer
 	var ui basicUI
@@ -79,3 +103,188 @@ two
 
 	must.Eq(t, expected, buf.String())
 }
+
+func TestNewUI_WithWriters(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	ui := NewUI(context.Background(), WithWriters(&stdout, &stderr))
+
+	ui.Output("output")
+	ui.Table(&Table{Headers: []string{"Name"}, Rows: [][]string{{"example"}}})
+
+	st := ui.Status()
+	st.Update("updating")
+	st.Step(StatusOK, "updated")
+	must.NoError(t, st.Close())
+
+	sg := ui.StepGroup()
+	step := sg.Add("stepping")
+	fmt.Fprint(step.TermOutput(), "step output\n")
+	step.Done()
+	sg.Wait()
+
+	out := stdout.String()
+	for _, expected := range []string{"output", "example", "updating", "updated", "-> stepping", "step output"} {
+		must.StrContains(t, out, expected)
+	}
+
+	outW, errW, err := ui.OutputWriters()
+	must.NoError(t, err)
+	must.Eq(t, &stdout, outW.(*bytes.Buffer))
+	must.Eq(t, &stderr, errW.(*bytes.Buffer))
+}
+
+func TestNonInteractiveUI_BufferedSteps(t *testing.T) {
+	var stdout bytes.Buffer
+	ui := NonInteractiveUI(context.Background(), WithWriters(&stdout, &stdout), WithBufferedSteps())
+	sg := ui.StepGroup()
+
+	// Each step writes its lines in turn with the other steps, so they would
+	// be interleaved if they were not buffered.
+	const steps, lines = 4, 50
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for i := range steps {
+		step := sg.Add("step %d", i)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			for j := range lines {
+				step.Update("step %d update %d", i, j)
+				fmt.Fprintf(step.TermOutput(), "step %d output %d\n", i, j)
+			}
+			step.Done()
+			step.Abort()
+		}()
+	}
+
+	// Output is only written once a step is done.
+	must.Eq(t, "", stdout.String())
+	close(start)
+	wg.Wait()
+	sg.Wait()
+
+	out := strings.Split(strings.TrimSuffix(stdout.String(), "\n"), "\n")
+	must.Len(t, steps*(2*lines+1), out)
+	for i := 0; i < len(out); i += 2*lines + 1 {
+		var step int
+		_, err := fmt.Sscanf(out[i], "-> step %d", &step)
+		must.NoError(t, err)
+		for j := range lines {
+			must.Eq(t, fmt.Sprintf("-> step %d update %d", step, j), out[i+1+2*j])
+			must.Eq(t, fmt.Sprintf("step %d output %d", step, j), out[i+2+2*j])
+		}
+	}
+}
+
+func TestNonInteractiveUI_StylePrefixes(t *testing.T) {
+	output := func(opts ...UIOption) string {
+		var stdout bytes.Buffer
+		ui := NonInteractiveUI(context.Background(), append(opts, WithWriters(&stdout, &stdout))...)
+		ui.Output("header", WithHeaderStyle())
+		ui.Output("failed\ncause", WithErrorStyle())
+		sg := ui.StepGroup()
+		sg.Add("step").Done()
+		sg.Wait()
+		return stdout.String()
+	}
+
+	must.Eq(t, "\n» header\n! failed\n  cause\n-> step\n", output())
+	must.Eq(t, "\n# header\nERROR: failed\n       cause\nstep\n", output(WithStylePrefixes(StylePrefixes{
+		Header: "# ",
+		Error:  "ERROR: ",
+	})))
+}
+
+func TestNonInteractiveUI_NoColor(t *testing.T) {
+	noColor := color.NoColor
+	t.Cleanup(func() { color.NoColor = noColor })
+	t.Setenv("NO_COLOR", "")
+
+	red := "\x1b[31mfailed\x1b[0m"
+	output := func(opts ...UIOption) string {
+		var stdout bytes.Buffer
+		ui := NonInteractiveUI(context.Background(), append(opts, WithWriters(&stdout, &stdout))...)
+		ui.Output(red)
+		ui.NamedValues([]NamedValue{{Name: "Status", Value: red}})
+		ui.Table(&Table{Headers: []string{"Status"}, Rows: [][]string{{red}}})
+		return stdout.String()
+	}
+
+	must.StrContains(t, output(), red)
+
+	out := output(WithNoColor())
+	must.StrNotContains(t, out, "\x1b[")
+	must.StrContains(t, out, "failed\n")
+	must.True(t, color.NoColor)
+
+	// Color is disabled by setting NO_COLOR in the same way.
+	t.Setenv("NO_COLOR", "1")
+	must.StrNotContains(t, output(), "\x1b[")
+}
+
+func TestNonInteractiveUI_Quiet(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	ui := NonInteractiveUI(context.Background(), WithWriters(&stdout, &stderr), WithQuiet())
+	ui.Header("header")
+	ui.Info("info")
+	ui.Success("success")
+	ui.Debug("debug")
+	ui.Trace("trace")
+	ui.NamedValues([]NamedValue{{Name: "Name", Value: "value"}})
+	ui.Table(&Table{Headers: []string{"Status"}, Rows: [][]string{{"running"}}})
+	ui.Status().Update("status")
+	ui.Output("rendered")
+	ui.Warning("careful")
+	ui.ErrorWithContext(errors.New("boom"), "failed", "Pack Name: example")
+
+	must.Eq(t, "rendered\n", stdout.String())
+	must.StrContains(t, stderr.String(), "careful")
+	must.StrContains(t, stderr.String(), "! Failed\n!   Error: boom\n")
+	must.StrContains(t, stderr.String(), "Pack Name: example")
+}
+
+// interactiveUI reports an interactive UI without a TTY, for testing.
+type interactiveUI struct{ UI }
+
+func (interactiveUI) Interactive() bool { return true }
+
+func TestClearScreen(t *testing.T) {
+	var stdout bytes.Buffer
+	ui := NonInteractiveUI(context.Background(), WithWriters(&stdout, &stdout))
+
+	must.NoError(t, ClearScreen(ui))
+	must.Eq(t, "", stdout.String())
+
+	must.NoError(t, ClearScreen(interactiveUI{ui}))
+	must.Eq(t, clearScreenSequence, stdout.String())
+}
+
+func TestTestUI(t *testing.T) {
+	ui := NewTestUI()
+
+	ui.Header("Summary")
+	ui.Warning("careful")
+	ui.NamedValues([]NamedValue{{Name: "Jobs", Value: 2}})
+	ui.Table(&Table{Headers: []string{"Job"}, Rows: [][]string{{"example"}}})
+	ui.ErrorWithContext(errors.New("boom"), "failed to run", "Pack Name: example")
+
+	must.Eq(t, []TestMessage{
+		{Style: HeaderStyle, Msg: "Summary"},
+		{Style: WarningStyle, Msg: "careful"},
+	}, ui.Messages())
+	must.Eq(t, [][]NamedValue{{{Name: "Jobs", Value: 2}}}, ui.NamedValueRows())
+	must.Len(t, 1, ui.Tables())
+	must.Eq(t, [][]string{{"example"}}, ui.Tables()[0].Rows)
+
+	errs := ui.ErrorsWithContext()
+	must.Len(t, 1, errs)
+	must.EqError(t, errs[0].Err, "boom")
+	must.Eq(t, "failed to run", errs[0].Subject)
+	must.Eq(t, []string{"Pack Name: example"}, errs[0].Context)
+
+	for _, expected := range []string{"» Summary", "warning: careful", "Jobs: 2", "example", "! Failed To Run", "Error: boom"} {
+		must.StrContains(t, ui.Stdout(), expected)
+	}
+	must.Eq(t, "", ui.Stderr())
+}